@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/foreground-eclipse/wallet/config"
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/foreground-eclipse/wallet/internal/storage/postgres"
+	"go.uber.org/zap"
+)
+
+// fximport loads FX rates from a JSON file, in the format POST /api/v1/admin/fx/rates accepts.
+// Either every rate in the file is loaded or none is.
+//
+//	go run ./cmd/fximport -file rates.json
+func main() {
+	file := flag.String("file", "", "JSON array of rates to load")
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create logger :%v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	if *file == "" {
+		logger.Fatal("file is required")
+	}
+	raw, err := os.ReadFile(*file)
+	if err != nil {
+		logger.Fatal("failed to read rates", zap.Error(err))
+	}
+	var reqs []requests.FXRateRequest
+	if err = json.Unmarshal(raw, &reqs); err != nil {
+		logger.Fatal("failed to parse rates", zap.String("file", *file), zap.Error(err))
+	}
+	rates := make([]models.FXRate, len(reqs))
+	for i, req := range reqs {
+		rates[i] = req.FXRate()
+	}
+
+	cfg := config.MustLoad("local")
+	storage, err := postgres.New(cfg)
+	if err != nil {
+		logger.Fatal("failed to connect to database", zap.Error(err))
+	}
+
+	imported, err := storage.ImportFXRates(context.Background(), rates)
+	if err != nil {
+		logger.Fatal("import failed", zap.Error(err))
+	}
+	logger.Info("import finished", zap.String("file", *file), zap.Int("rates", len(imported)))
+}
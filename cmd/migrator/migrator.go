@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -47,17 +48,27 @@ func Migrate(logger *zap.Logger, cfg *config.Config) error {
 
 	logger.Info("trying to apply migrations")
 
-	m, err := migrate.New(fmt.Sprintf("file://%s", dir), fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s", cfg.Database.User,
+	// The currency backfill migration reads the default currency from the wallet.default_currency setting.
+	m, err := migrate.New(fmt.Sprintf("file://%s", dir), fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s&options=%s", cfg.Database.User,
 		cfg.Database.Password,
 		cfg.Database.Host,
 		cfg.Database.Port,
 		cfg.Database.Name,
-		cfg.Database.SSLMode))
+		cfg.Database.SSLMode,
+		url.QueryEscape("-c wallet.default_currency="+cfg.Wallet.DefaultCurrency)))
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if backfillErr := backfillLedger(db); backfillErr != nil {
+		return fmt.Errorf("%s: %w", op, backfillErr)
+	}
+
 	if err != nil {
 		if errors.Is(err, migrate.ErrNoChange) {
 			logger.Info("no migrations to apply")
@@ -71,3 +82,40 @@ func Migrate(logger *zap.Logger, cfg *config.Config) error {
 
 	return nil
 }
+
+// backfillLedger opens ledger accounts for wallets created before the double-entry ledger
+// and posts their balance as an opening entry against the system opening_balance account.
+// Wallets that already have postings are left alone, so it is safe to run on every start.
+func backfillLedger(db *sql.DB) error {
+	_, err := db.Exec(`
+    INSERT INTO ledger_accounts (account_id, wallet_id, currency)
+    SELECT 'wallet:' || wallet_id, wallet_id, currency FROM wallets
+    ON CONFLICT (account_id) DO NOTHING
+    `)
+	if err != nil {
+		return fmt.Errorf("backfill ledger accounts: %w", err)
+	}
+	_, err = db.Exec(`
+    WITH opening AS (
+        SELECT gen_random_uuid() AS entry_id, wallet_id, balance, currency
+        FROM wallets
+        WHERE balance <> 0
+          AND NOT EXISTS (SELECT 1 FROM postings WHERE postings.account_id = 'wallet:' || wallets.wallet_id)
+    ), entries AS (
+        INSERT INTO journal_entries (id, kind, created_at)
+        SELECT entry_id, 'OPENING_BALANCE', now() AT TIME ZONE 'UTC' FROM opening
+    ), accounts AS (
+        INSERT INTO ledger_accounts (account_id, currency)
+        SELECT DISTINCT 'system:opening_balance:' || currency, currency FROM opening
+        ON CONFLICT (account_id) DO NOTHING
+    )
+    INSERT INTO postings (entry_id, account_id, amount, currency)
+    SELECT entry_id, 'wallet:' || wallet_id, balance, currency FROM opening
+    UNION ALL
+    SELECT entry_id, 'system:opening_balance:' || currency, -balance, currency FROM opening
+    `)
+	if err != nil {
+		return fmt.Errorf("backfill opening entries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/foreground-eclipse/wallet/config"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/foreground-eclipse/wallet/internal/storage/postgres"
+	"go.uber.org/zap"
+)
+
+// reconcile recomputes every wallet balance from its DEPOSIT, INTEREST and WITHDRAW operations
+// and writes the wallets that disagree to stdout.
+//
+//	go run ./cmd/reconcile -format csv -batch 5000
+//	go run ./cmd/reconcile -repair
+func main() {
+	format := flag.String("format", "json", "report format: json (one object per line) or csv")
+	batchSize := flag.Int("batch", 1000, "number of wallets read per query")
+	repair := flag.Bool("repair", false, "set mismatched balances to the recomputed value")
+	reason := flag.String("reason", "reconciliation", "reason stored in the audit record of repaired balances")
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create logger :%v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	if *batchSize <= 0 {
+		logger.Fatal("batch must be positive", zap.Int("batch", *batchSize))
+	}
+	reporter, err := newReporter(*format, os.Stdout)
+	if err != nil {
+		logger.Fatal("invalid format", zap.Error(err))
+	}
+
+	cfg := config.MustLoad("local")
+	storage, err := postgres.New(cfg)
+	if err != nil {
+		logger.Fatal("failed to connect to database", zap.Error(err))
+	}
+
+	ctx := context.Background()
+	mismatches, repaired := 0, 0
+	checked, err := storage.ReconcileBalances(ctx, *batchSize, func(mismatch models.BalanceMismatch) error {
+		mismatches++
+		if !*repair {
+			return reporter.Report(mismatch, false)
+		}
+		fixed, err := storage.RepairBalance(ctx, mismatch.WalletID, *reason)
+		if err != nil {
+			return fmt.Errorf("repair wallet %s: %w", mismatch.WalletID, err)
+		}
+		if fixed.Difference() == 0 {
+			// Someone fixed the wallet after it was read; nothing left to report.
+			mismatches--
+			return nil
+		}
+		repaired++
+		return reporter.Report(*fixed, true)
+	})
+	if flushErr := reporter.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		logger.Fatal("reconciliation failed", zap.Error(err), zap.Int("checked", checked))
+	}
+
+	logger.Info("reconciliation finished",
+		zap.Int("checked", checked),
+		zap.Int("mismatches", mismatches),
+		zap.Int("repaired", repaired),
+	)
+}
+
+type reporter interface {
+	Report(mismatch models.BalanceMismatch, repaired bool) error
+	Flush() error
+}
+
+func newReporter(format string, w io.Writer) (reporter, error) {
+	switch format {
+	case "json":
+		return &jsonReporter{encoder: json.NewEncoder(w)}, nil
+	case "csv":
+		return &csvReporter{writer: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+type mismatchRecord struct {
+	WalletID        string        `json:"walletId"`
+	Currency        string        `json:"currency"`
+	Balance         models.Amount `json:"balance"`
+	ComputedBalance models.Amount `json:"computedBalance"`
+	Difference      models.Amount `json:"difference"`
+	Repaired        bool          `json:"repaired"`
+}
+
+type jsonReporter struct {
+	encoder *json.Encoder
+}
+
+func (r *jsonReporter) Report(mismatch models.BalanceMismatch, repaired bool) error {
+	return r.encoder.Encode(mismatchRecord{
+		WalletID:        mismatch.WalletID,
+		Currency:        mismatch.Currency,
+		Balance:         mismatch.Balance,
+		ComputedBalance: mismatch.ComputedBalance,
+		Difference:      mismatch.Difference(),
+		Repaired:        repaired,
+	})
+}
+
+func (r *jsonReporter) Flush() error {
+	return nil
+}
+
+var csvHeader = []string{"wallet_id", "currency", "balance", "computed_balance", "difference", "repaired"}
+
+type csvReporter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (r *csvReporter) writeHeader() error {
+	if r.headerWritten {
+		return nil
+	}
+	r.headerWritten = true
+	return r.writer.Write(csvHeader)
+}
+
+func (r *csvReporter) Report(mismatch models.BalanceMismatch, repaired bool) error {
+	if err := r.writeHeader(); err != nil {
+		return err
+	}
+	err := r.writer.Write([]string{
+		mismatch.WalletID,
+		mismatch.Currency,
+		mismatch.Balance.String(),
+		mismatch.ComputedBalance.String(),
+		mismatch.Difference().String(),
+		strconv.FormatBool(repaired),
+	})
+	if err != nil {
+		return err
+	}
+	// Flush every row so the report is streamed rather than held until the end.
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *csvReporter) Flush() error {
+	if err := r.writeHeader(); err != nil {
+		return err
+	}
+	r.writer.Flush()
+	return r.writer.Error()
+}
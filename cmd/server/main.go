@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/foreground-eclipse/wallet/cmd/migrator"
 	"github.com/foreground-eclipse/wallet/config"
+	"github.com/foreground-eclipse/wallet/internal/events"
 	"github.com/foreground-eclipse/wallet/internal/handlers"
 	"github.com/foreground-eclipse/wallet/internal/storage/postgres"
+	"github.com/foreground-eclipse/wallet/internal/webhooks"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -28,14 +32,178 @@ func main() {
 		panic(err)
 	}
 
+	go expireHolds(logger, storage, cfg.Wallet.HoldSweep)
+	go snapshotBalances(logger, storage, cfg.Wallet.SnapshotEvery, cfg.Wallet.SnapshotLag)
+	go runSchedules(logger, storage, cfg.Wallet.Schedules.Sweep)
+	go runInterest(logger, storage, cfg.Wallet.Interest.Sweep, cfg.Wallet.Interest.Lag)
+	publisher := events.Publishers{events.NewLogPublisher(logger), webhooks.NewPublisher(storage)}
+	go dispatchEvents(logger, storage, publisher, cfg.Wallet.Outbox.Poll)
+	go deliverWebhooks(logger, storage, webhooks.NewClient(cfg.Wallet.Webhooks.Timeout), cfg.Wallet.Webhooks.Poll)
+
 	router := gin.Default()
+	router.Use(handlers.AmountFormat())
 
 	router.POST("/api/v1/wallet", handlers.HandleWalletOperation(logger, storage))
+	router.POST("/api/v1/wallet/batch", handlers.HandleBatchOperation(logger, storage))
+	router.POST("/api/v1/wallets", handlers.HandleCreateWallet(logger, storage))
 	router.GET("/api/v1/wallets/:walletId", handlers.HandleGetWalletBalance(logger, storage))
+	router.POST("/api/v1/owners", handlers.HandleCreateOwner(logger, storage))
+	router.GET("/api/v1/owners/:ownerId", handlers.HandleGetOwner(logger, storage))
+	router.GET("/api/v1/owners/by-external-id/:externalId", handlers.HandleGetOwnerByExternalID(logger, storage))
+	router.GET("/api/v1/owners/:ownerId/wallets", handlers.HandleListOwnerWallets(logger, storage))
+	router.GET("/api/v1/wallets/:walletId/operations", handlers.HandleListOperations(logger, storage))
+	router.GET("/api/v1/wallets/:walletId/operations/by-reference/:externalReference", handlers.HandleGetOperationByReference(logger, storage))
+	router.GET("/api/v1/wallets/:walletId/statement", handlers.HandleGetStatement(logger, storage))
+	router.POST("/api/v1/operations/:id/reverse", handlers.HandleReverseOperation(logger, storage))
+	router.POST("/api/v1/transfers", handlers.HandleTransfer(logger, storage))
+	router.POST("/api/v1/sweeps", handlers.HandleSweep(logger, storage))
+	router.POST("/api/v1/fx/quotes", handlers.HandleCreateFXQuote(logger, storage))
+	router.POST("/api/v1/exchanges", handlers.HandleExchange(logger, storage))
+	router.GET("/api/v1/wallets/:walletId/schedules", handlers.HandleListSchedules(logger, storage))
+	router.POST("/api/v1/schedules", handlers.HandleCreateSchedule(logger, storage))
+	router.GET("/api/v1/schedules/:scheduleId", handlers.HandleGetSchedule(logger, storage))
+	router.PATCH("/api/v1/schedules/:scheduleId", handlers.HandleUpdateSchedule(logger, storage))
+	router.DELETE("/api/v1/schedules/:scheduleId", handlers.HandleCancelSchedule(logger, storage))
+	router.GET("/api/v1/wallets/:walletId/interest-accruals", handlers.HandleListInterestAccruals(logger, storage))
+	router.POST("/api/v1/webhooks", handlers.HandleCreateWebhook(logger, storage))
+	router.GET("/api/v1/webhooks", handlers.HandleListWebhooks(logger, storage))
+	router.GET("/api/v1/webhooks/:webhookId", handlers.HandleGetWebhook(logger, storage))
+	router.PATCH("/api/v1/webhooks/:webhookId", handlers.HandleUpdateWebhook(logger, storage))
+	router.DELETE("/api/v1/webhooks/:webhookId", handlers.HandleDeleteWebhook(logger, storage))
+	router.GET("/api/v1/webhooks/:webhookId/deliveries", handlers.HandleListWebhookDeliveries(logger, storage))
+	router.GET("/api/v1/webhooks/:webhookId/deliveries/:deliveryId", handlers.HandleGetWebhookDelivery(logger, storage))
+	router.POST("/api/v1/webhooks/:webhookId/deliveries/:deliveryId/redeliver", handlers.HandleRedeliverWebhook(logger, storage))
+	router.POST("/api/v1/holds", handlers.HandleCreateHold(logger, storage))
+	router.GET("/api/v1/holds/:holdId", handlers.HandleGetHold(logger, storage))
+	router.POST("/api/v1/holds/:holdId/capture", handlers.HandleCaptureHold(logger, storage))
+	router.POST("/api/v1/holds/:holdId/void", handlers.HandleVoidHold(logger, storage))
+
+	admin := router.Group("/api/v1/admin")
+	admin.GET("/ledger/verify", handlers.HandleVerifyLedger(logger, storage))
+	admin.GET("/wallets", handlers.HandleListWallets(logger, storage))
+	admin.PUT("/wallets/:walletId/owner", handlers.HandleSetWalletOwner(logger, storage))
+	admin.PUT("/wallets/:walletId/status", handlers.HandleSetWalletStatus(logger, storage))
+	admin.PUT("/wallets/:walletId/overdraft", handlers.HandleSetOverdraftLimit(logger, storage))
+	admin.GET("/wallets/:walletId/limits", handlers.HandleGetWithdrawalLimits(logger, storage))
+	admin.PUT("/wallets/:walletId/limits", handlers.HandleSetWithdrawalLimits(logger, storage))
+	admin.PUT("/wallets/:walletId/tier", handlers.HandleSetWalletTier(logger, storage))
+	admin.PUT("/wallets/:walletId/interest-rate", handlers.HandleSetInterestRate(logger, storage))
+	admin.POST("/interest/run", handlers.HandleRunInterest(logger, storage))
+	admin.GET("/fx/rates", handlers.HandleListFXRates(logger, storage))
+	admin.POST("/fx/rates", handlers.HandleImportFXRates(logger, storage))
+	admin.GET("/fee-rules", handlers.HandleListFeeRules(logger, storage))
+	admin.POST("/fee-rules", handlers.HandleCreateFeeRule(logger, storage))
+	admin.DELETE("/fee-rules/:ruleId", handlers.HandleDeleteFeeRule(logger, storage))
 
 	router.Run(cfg.Server.Address)
 }
 
+// expireHolds periodically marks holds past their expiry as EXPIRED.
+func expireHolds(logger *zap.Logger, storage *postgres.Storage, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		expired, err := storage.ExpireHolds(context.Background())
+		if err != nil {
+			logger.Error("failed to expire holds", zap.Error(err))
+			continue
+		}
+		if expired > 0 {
+			logger.Info("expired holds", zap.Int("count", expired))
+		}
+	}
+}
+
+// runSchedules periodically runs scheduled operations that are due. Every replica runs it;
+// storage makes sure each occurrence is only run once.
+func runSchedules(logger *zap.Logger, storage *postgres.Storage, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ran, err := storage.RunDueSchedules(context.Background(), time.Now())
+		if err != nil {
+			logger.Error("failed to run schedules", zap.Error(err))
+		}
+		if ran > 0 {
+			logger.Info("ran scheduled operations", zap.Int("count", ran))
+		}
+	}
+}
+
+// runInterest periodically accrues interest for the last finished day and pays out finished months.
+// A day is only accrued lag after it ended; running it again, here or on another replica, credits nothing twice.
+func runInterest(logger *zap.Logger, storage *postgres.Storage, interval, lag time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		day := time.Now().UTC().Add(-lag).AddDate(0, 0, -1)
+		run, err := storage.RunInterest(context.Background(), day)
+		if err != nil {
+			logger.Error("failed to run interest", zap.Error(err))
+			continue
+		}
+		if run.Accrued > 0 || run.Paid > 0 {
+			logger.Info("ran interest", zap.String("day", run.Day), zap.Int("accrued", run.Accrued), zap.Int("paid", run.Paid))
+		}
+	}
+}
+
+// dispatchEvents periodically publishes the events written to the outbox. Every replica runs it; events are
+// claimed with SKIP LOCKED, so no two replicas publish the same one at once. A full batch is followed by the
+// next right away, so a backlog drains without waiting for the ticker.
+func dispatchEvents(logger *zap.Logger, storage *postgres.Storage, publisher events.Publisher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for {
+			delivered, more, err := storage.DispatchEvents(context.Background(), publisher)
+			if err != nil {
+				logger.Error("failed to dispatch events", zap.Error(err))
+				break
+			}
+			if delivered > 0 {
+				logger.Info("dispatched events", zap.Int("count", delivered))
+			}
+			if !more {
+				break
+			}
+		}
+	}
+}
+
+// deliverWebhooks periodically sends the webhook deliveries that are due. Every replica runs it; deliveries are
+// leased with SKIP LOCKED, so no two replicas send the same one at once.
+func deliverWebhooks(logger *zap.Logger, storage *postgres.Storage, sender webhooks.Sender, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		attempted, err := storage.DeliverWebhooks(context.Background(), sender, time.Now())
+		if err != nil {
+			logger.Error("failed to deliver webhooks", zap.Error(err))
+		}
+		if attempted > 0 {
+			logger.Info("attempted webhook deliveries", zap.Int("count", attempted))
+		}
+	}
+}
+
+// snapshotBalances periodically records wallet balances so point-in-time queries do not sum whole histories.
+// Snapshots are taken lag behind now, and never past the oldest transaction still open.
+func snapshotBalances(logger *zap.Logger, storage *postgres.Storage, interval, lag time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		taken, err := storage.SnapshotBalances(context.Background(), time.Now().Add(-lag))
+		if err != nil {
+			logger.Error("failed to snapshot balances", zap.Error(err))
+			continue
+		}
+		if taken > 0 {
+			logger.Info("took balance snapshots", zap.Int("count", taken))
+		}
+	}
+}
+
 func setupLogger() *zap.Logger {
 	atomicLevel := zap.NewAtomicLevelAt(zap.InfoLevel)
 
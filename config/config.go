@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -14,6 +15,7 @@ type (
 		Server   ServerConfig
 		Database DatabaseConfig
 		Redis    RedisConfig
+		Wallet   WalletConfig
 	}
 
 	// ServerConfig holds the configuration for the server settings
@@ -32,6 +34,76 @@ type (
 		SSLMode  string `env:"DATABASE_SSLMODE"`
 	}
 
+	// WalletConfig holds the configuration for wallet behaviour
+	WalletConfig struct {
+		DefaultCurrency string        `env:"WALLET_DEFAULT_CURRENCY" env-default:"USD"` // ISO 4217 code for new and pre-existing wallets
+		HoldTTL         time.Duration `env:"WALLET_HOLD_TTL" env-default:"168h"`        // Lifetime of holds created without an explicit TTL
+		HoldSweep       time.Duration `env:"WALLET_HOLD_SWEEP" env-default:"1m"`        // How often expired holds are marked EXPIRED
+		AutoCreate      bool          `env:"WALLET_AUTO_CREATE" env-default:"true"`     // Create unknown wallets on their first operation
+		SnapshotEvery   time.Duration `env:"WALLET_SNAPSHOT_EVERY" env-default:"1h"`    // How often balance snapshots are taken
+		SnapshotLag     time.Duration `env:"WALLET_SNAPSHOT_LAG" env-default:"1m"`      // How far behind now snapshots are taken
+		Schedules       ScheduleConfig
+		Withdrawals     WithdrawalLimitsConfig
+		Fees            FeeConfig
+		Interest        InterestConfig
+		FX              FXConfig
+		Outbox          OutboxConfig
+		Webhooks        WebhookConfig
+	}
+
+	// ScheduleConfig holds the configuration for scheduled operations
+	ScheduleConfig struct {
+		Sweep         time.Duration `env:"WALLET_SCHEDULE_SWEEP" env-default:"1m"`            // How often due schedules are looked for
+		RetryDelay    time.Duration `env:"WALLET_SCHEDULE_RETRY_DELAY" env-default:"1h"`      // Delay before the first retry, doubled after each failure
+		MaxRetryDelay time.Duration `env:"WALLET_SCHEDULE_MAX_RETRY_DELAY" env-default:"24h"` // Cap on the retry delay
+		MaxAttempts   int           `env:"WALLET_SCHEDULE_MAX_ATTEMPTS" env-default:"3"`      // Failed attempts after which a schedule is marked FAILED
+	}
+
+	// WithdrawalLimitsConfig holds the global limits on withdrawals, 0 meaning no limit.
+	// Wallets can override each of them through the admin API.
+	WithdrawalLimitsConfig struct {
+		PerOperation int64 `env:"WALLET_WITHDRAW_MAX_AMOUNT"`       // Largest single withdrawal, in minor units
+		Daily        int64 `env:"WALLET_WITHDRAW_MAX_DAILY"`        // Total withdrawn over a rolling 24 hours
+		Monthly      int64 `env:"WALLET_WITHDRAW_MAX_MONTHLY"`      // Total withdrawn over a rolling 30 days
+		HourlyCount  int   `env:"WALLET_WITHDRAW_MAX_HOURLY_COUNT"` // Number of withdrawals over a rolling hour
+	}
+
+	// FeeConfig holds where fees are booked and, optionally, the fee rules themselves
+	FeeConfig struct {
+		Wallets   map[string]string `env:"WALLET_FEE_WALLETS"`    // Fee-revenue wallet per currency, as USD:<wallet id>,EUR:<wallet id>
+		RulesFile string            `env:"WALLET_FEE_RULES_FILE"` // JSON array of fee rules used instead of the fee_rules table
+	}
+
+	// InterestConfig holds the configuration for the interest job
+	InterestConfig struct {
+		Sweep time.Duration `env:"WALLET_INTEREST_SWEEP" env-default:"1h"` // How often the job checks for a day to accrue
+		Lag   time.Duration `env:"WALLET_INTEREST_LAG" env-default:"5m"`   // How long after midnight UTC a day is accrued
+	}
+
+	// FXConfig holds the configuration for currency exchange
+	FXConfig struct {
+		QuoteTTL time.Duration `env:"WALLET_FX_QUOTE_TTL" env-default:"30s"` // How long a quoted rate can be exchanged at
+	}
+
+	// OutboxConfig holds the configuration for the outbox dispatcher
+	OutboxConfig struct {
+		Poll          time.Duration `env:"WALLET_OUTBOX_POLL" env-default:"1s"`             // How often pending events are looked for
+		BatchSize     int           `env:"WALLET_OUTBOX_BATCH_SIZE" env-default:"100"`      // Events claimed per transaction
+		RetryDelay    time.Duration `env:"WALLET_OUTBOX_RETRY_DELAY" env-default:"1s"`      // Delay before republishing a failed event, doubled after each failure
+		MaxRetryDelay time.Duration `env:"WALLET_OUTBOX_MAX_RETRY_DELAY" env-default:"10m"` // Cap on the retry delay; failed events are retried forever
+	}
+
+	// WebhookConfig holds the configuration for webhook deliveries
+	WebhookConfig struct {
+		Poll          time.Duration `env:"WALLET_WEBHOOK_POLL" env-default:"1s"`            // How often due deliveries are looked for
+		Timeout       time.Duration `env:"WALLET_WEBHOOK_TIMEOUT" env-default:"5s"`         // How long a receiver has to respond
+		Workers       int           `env:"WALLET_WEBHOOK_WORKERS" env-default:"8"`          // Deliveries sent at once by each replica
+		Lease         time.Duration `env:"WALLET_WEBHOOK_LEASE" env-default:"1m"`           // How long a claimed delivery is left to its sender, longer than the timeout
+		RetryDelay    time.Duration `env:"WALLET_WEBHOOK_RETRY_DELAY" env-default:"30s"`    // Delay before the first retry, doubled after each failure
+		MaxRetryDelay time.Duration `env:"WALLET_WEBHOOK_MAX_RETRY_DELAY" env-default:"1h"` // Cap on the retry delay
+		MaxAttempts   int           `env:"WALLET_WEBHOOK_MAX_ATTEMPTS" env-default:"10"`    // Failed attempts after which a delivery is marked DEAD
+	}
+
 	RedisConfig struct {
 		Addr     string `env:"REDIS_ADDR"`     // The address of the database
 		Password string `env:"REDIS_PASSWORD"` // The password for connecting to the database
@@ -43,7 +115,6 @@ type (
 
 func MustLoad(filename string) *Config {
 	configPath := fmt.Sprintf("./config/%s.env", filename)
-	fmt.Println(os.Getwd())
 	// Check if file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		log.Fatalf("config file doesnt exists %s", configPath)
@@ -54,7 +125,24 @@ func MustLoad(filename string) *Config {
 	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
 		log.Fatalf("cant read config: %s", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid config: %s", err)
+	}
 
 	return &cfg
 
 }
+
+// Validate rejects settings that load fine but would break the service at run time.
+func (c *Config) Validate() error {
+	schedules := c.Wallet.Schedules
+	// A zero delay would burn through a failing schedule's attempts in consecutive sweeps.
+	if schedules.RetryDelay <= 0 || schedules.MaxRetryDelay <= 0 {
+		return errors.New("WALLET_SCHEDULE_RETRY_DELAY and WALLET_SCHEDULE_MAX_RETRY_DELAY must be positive")
+	}
+	// A lease that ends before the receiver has to answer would let a second sender post the same delivery.
+	if c.Wallet.Webhooks.Lease <= c.Wallet.Webhooks.Timeout {
+		return errors.New("WALLET_WEBHOOK_LEASE must be longer than WALLET_WEBHOOK_TIMEOUT")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package requests
+
+import "github.com/foreground-eclipse/wallet/internal/models"
+
+const (
+	BatchAtomic     = "ATOMIC"      // Every operation is applied or none is
+	BatchBestEffort = "BEST_EFFORT" // Each operation succeeds or fails on its own
+)
+
+type BatchOperationRequest struct {
+	Mode       string                   `json:"mode,omitempty"` // ATOMIC by default
+	Operations []WalletOperationRequest `json:"operations"`
+}
+
+type BatchOperationResponse struct {
+	Mode      string            `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// BatchItemResult is the outcome of one operation of a batch, in request order.
+type BatchItemResult struct {
+	Index         int            `json:"index"`
+	WalletID      string         `json:"walletId"`
+	OperationType string         `json:"operationType"`
+	Amount        models.Amount  `json:"amount"`
+	Status        string         `json:"status"` // OK or error
+	OperationID   string         `json:"operationId,omitempty"`
+	Balance       *models.Amount `json:"balance,omitempty"` // Wallet balance right after the operation
+	Currency      string         `json:"currency,omitempty"`
+	Fee           models.Amount  `json:"fee,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	Err           error          `json:"-"`
+}
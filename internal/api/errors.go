@@ -1,7 +1,225 @@
 package requests
 
-type InsufficientFundsError struct{}
+import (
+	"fmt"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// InsufficientFundsError is returned when a debit exceeds what the wallet can spend:
+// its balance less active holds, plus its overdraft limit.
+type InsufficientFundsError struct {
+	Headroom models.Amount // What could still be debited
+}
 
 func (e InsufficientFundsError) Error() string {
-	return "insufficient funds"
+	return fmt.Sprintf("insufficient funds: %d left to spend", e.Headroom)
+}
+
+// IdempotencyKeyConflictError is returned when an idempotency key is reused with a different request body.
+type IdempotencyKeyConflictError struct{}
+
+func (e IdempotencyKeyConflictError) Error() string {
+	return "idempotency key was already used with a different request"
+}
+
+// CurrencyMismatchError is returned when an operation is requested in a currency the wallet does not hold.
+type CurrencyMismatchError struct {
+	WalletCurrency string
+	Currency       string
+}
+
+func (e CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("wallet holds %s, not %s", e.WalletCurrency, e.Currency)
+}
+
+// HoldNotActiveError is returned when capturing or voiding a hold that is no longer active.
+type HoldNotActiveError struct {
+	Status string
+}
+
+func (e HoldNotActiveError) Error() string {
+	return fmt.Sprintf("hold is %s", e.Status)
+}
+
+// CaptureExceedsHoldError is returned when a capture is larger than the held amount.
+type CaptureExceedsHoldError struct {
+	Held models.Amount
+}
+
+func (e CaptureExceedsHoldError) Error() string {
+	return fmt.Sprintf("capture exceeds held amount of %d", e.Held)
+}
+
+// ReversalExceedsOriginalError is returned when a reversal is larger than what is left of the original operation.
+type ReversalExceedsOriginalError struct {
+	Remaining models.Amount
+}
+
+func (e ReversalExceedsOriginalError) Error() string {
+	return fmt.Sprintf("reversal exceeds the %d left to reverse", e.Remaining)
+}
+
+// OperationNotReversibleError is returned for operations that cannot be reversed, such as reversals themselves.
+type OperationNotReversibleError struct {
+	Reason string
+}
+
+func (e OperationNotReversibleError) Error() string {
+	return "operation cannot be reversed: " + e.Reason
+}
+
+// WithdrawalLimitError is returned when a withdrawal would breach one of the wallet's withdrawal limits.
+type WithdrawalLimitError struct {
+	Limit string // Which limit was hit, e.g. "daily"
+	Max   int64
+}
+
+func (e WithdrawalLimitError) Error() string {
+	return fmt.Sprintf("%s withdrawal limit of %d exceeded", e.Limit, e.Max)
+}
+
+// WalletNotActiveError is returned for operations a frozen or closed wallet does not accept.
+type WalletNotActiveError struct {
+	Status string
+}
+
+func (e WalletNotActiveError) Error() string {
+	return fmt.Sprintf("wallet is %s", e.Status)
+}
+
+// WalletExistsError is returned when creating a wallet with an id that is already taken.
+type WalletExistsError struct{}
+
+func (e WalletExistsError) Error() string {
+	return "wallet already exists"
+}
+
+// WalletStatusTransitionError is returned for status changes the wallet lifecycle does not allow.
+type WalletStatusTransitionError struct {
+	From string
+	To   string
+}
+
+func (e WalletStatusTransitionError) Error() string {
+	return fmt.Sprintf("wallet cannot go from %s to %s", e.From, e.To)
+}
+
+// WalletNotEmptyError is returned when closing a wallet that still holds or reserves money.
+type WalletNotEmptyError struct {
+	Balance models.Amount
+	Held    models.Amount
+}
+
+func (e WalletNotEmptyError) Error() string {
+	return fmt.Sprintf("wallet still has a balance of %d and %d on hold", e.Balance, e.Held)
+}
+
+// ScheduleFinishedError is returned when changing a schedule that is completed or cancelled.
+type ScheduleFinishedError struct {
+	Status string
+}
+
+func (e ScheduleFinishedError) Error() string {
+	return fmt.Sprintf("schedule is %s", e.Status)
+}
+
+// BatchItemError is returned when an operation fails an atomic batch, which is then rolled back.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e BatchItemError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// ExternalReferenceConflictError is returned when a wallet already has an operation with the given external reference.
+type ExternalReferenceConflictError struct {
+	ExternalReference string
+}
+
+func (e ExternalReferenceConflictError) Error() string {
+	return fmt.Sprintf("operation with external reference %q already exists", e.ExternalReference)
+}
+
+// FeeRulesReadOnlyError is returned when changing fee rules that are loaded from a file.
+type FeeRulesReadOnlyError struct {
+	File string
+}
+
+func (e FeeRulesReadOnlyError) Error() string {
+	return fmt.Sprintf("fee rules are loaded from %s", e.File)
+}
+
+// FXRateNotFoundError is returned when no FX rate between two currencies is valid at the time of a quote.
+type FXRateNotFoundError struct {
+	FromCurrency string
+	ToCurrency   string
+}
+
+func (e FXRateNotFoundError) Error() string {
+	return fmt.Sprintf("no rate from %s to %s", e.FromCurrency, e.ToCurrency)
+}
+
+// QuoteNotUsableError is returned when exchanging on a quote that expired, was used or is for other currencies.
+type QuoteNotUsableError struct {
+	Reason string
+}
+
+func (e QuoteNotUsableError) Error() string {
+	return "quote cannot be used: " + e.Reason
+}
+
+// WalletsNotRelatedError is returned when sweeping between wallets that are not parent and child.
+type WalletsNotRelatedError struct{}
+
+func (e WalletsNotRelatedError) Error() string {
+	return "wallets are not parent and child"
+}
+
+// SubWalletsNotEmptyError is returned when closing a wallet with sub-wallets that still hold or reserve money.
+type SubWalletsNotEmptyError struct {
+	Count int
+}
+
+func (e SubWalletsNotEmptyError) Error() string {
+	return fmt.Sprintf("%d sub-wallets are not empty", e.Count)
+}
+
+// OwnerExistsError is returned when creating an owner with an external id that is already taken.
+type OwnerExistsError struct{}
+
+func (e OwnerExistsError) Error() string {
+	return "owner with this external id already exists"
+}
+
+// OwnerNotFoundError is returned when a wallet is given an owner that does not exist.
+type OwnerNotFoundError struct {
+	OwnerID string
+}
+
+func (e OwnerNotFoundError) Error() string {
+	return fmt.Sprintf("owner with id %s not found", e.OwnerID)
+}
+
+// WebhookDisabledError is returned when changing a webhook subscription, or redelivering to it, after it was deleted.
+type WebhookDisabledError struct{}
+
+func (e WebhookDisabledError) Error() string {
+	return "webhook subscription is disabled"
+}
+
+// DeliveryPendingError is returned when redelivering a delivery that is being sent or waits for its next attempt.
+type DeliveryPendingError struct {
+	NextAttemptAt time.Time
+}
+
+func (e DeliveryPendingError) Error() string {
+	return fmt.Sprintf("delivery is pending until %s", e.NextAttemptAt.UTC().Format(time.RFC3339))
 }
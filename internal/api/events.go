@@ -0,0 +1,85 @@
+package requests
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// Event types published through the outbox.
+const (
+	EventWalletCreated       = "WalletCreated"
+	EventOperationApplied    = "OperationApplied"
+	EventWalletStatusChanged = "WalletStatusChanged"
+	EventBalanceAdjusted     = "BalanceAdjusted"
+)
+
+// EventPayload is the data of one version of an event type. Within a version fields are only ever added,
+// as optional; any other change to a payload is a new version of its type.
+type EventPayload interface {
+	EventType() string
+	EventVersion() int
+}
+
+// EventEnvelope is an event as published: its payload with what is needed to route and deduplicate it.
+type EventEnvelope struct {
+	ID         string          `json:"id"`       // Unique per event, the same on every delivery of it
+	Sequence   int64           `json:"sequence"` // Increases with every event written
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	WalletID   string          `json:"walletId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WalletCreatedV1 is published when a wallet is opened, including by auto-create on its first operation.
+type WalletCreatedV1 struct {
+	WalletID string  `json:"walletId"`
+	Currency string  `json:"currency"`
+	ParentID *string `json:"parentId,omitempty"`
+	OwnerID  *string `json:"ownerId,omitempty"`
+}
+
+func (WalletCreatedV1) EventType() string { return EventWalletCreated }
+func (WalletCreatedV1) EventVersion() int { return 1 }
+
+// OperationAppliedV1 is published for every operation that changes a balance: deposits and withdrawals,
+// transfer, sweep and exchange legs, fees, reversals, captures and interest.
+type OperationAppliedV1 struct {
+	OperationID       string        `json:"operationId"`
+	WalletID          string        `json:"walletId"`
+	OperationType     string        `json:"operationType"`
+	Amount            models.Amount `json:"amount"`
+	Currency          string        `json:"currency"`
+	Balance           models.Amount `json:"balance"` // Balance of the wallet right after the operation
+	Timestamp         time.Time     `json:"timestamp"`
+	TransferID        *string       `json:"transferId,omitempty"`
+	ReversalOf        *string       `json:"reversalOf,omitempty"`
+	FeeFor            *string       `json:"feeFor,omitempty"`
+	ExternalReference *string       `json:"externalReference,omitempty"`
+}
+
+func (OperationAppliedV1) EventType() string { return EventOperationApplied }
+func (OperationAppliedV1) EventVersion() int { return 1 }
+
+type WalletStatusChangedV1 struct {
+	WalletID string `json:"walletId"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+func (WalletStatusChangedV1) EventType() string { return EventWalletStatusChanged }
+func (WalletStatusChangedV1) EventVersion() int { return 1 }
+
+// BalanceAdjustedV1 is published when reconciliation repairs a balance that drifted from its operations.
+type BalanceAdjustedV1 struct {
+	WalletID   string        `json:"walletId"`
+	Currency   string        `json:"currency"`
+	OldBalance models.Amount `json:"oldBalance"`
+	NewBalance models.Amount `json:"newBalance"`
+	Reason     string        `json:"reason"`
+}
+
+func (BalanceAdjustedV1) EventType() string { return EventBalanceAdjusted }
+func (BalanceAdjustedV1) EventVersion() int { return 1 }
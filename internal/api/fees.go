@@ -0,0 +1,47 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// FeeRuleRequest creates a fee rule. A fee rules file holds a JSON array of these.
+type FeeRuleRequest struct {
+	OperationType string         `json:"operationType"`      // DEPOSIT or WITHDRAW
+	Tier          *string        `json:"tier,omitempty"`     // Only wallets of this tier, any tier when omitted
+	Currency      *string        `json:"currency,omitempty"` // Only wallets in this currency, any currency when omitted
+	Fixed         models.Amount  `json:"fixed,omitempty"`    // Charged on every operation
+	RateBps       int64          `json:"rateBps,omitempty"`  // Percentage of the amount in hundredths of a percent
+	MinFee        *models.Amount `json:"minFee,omitempty"`
+	MaxFee        *models.Amount `json:"maxFee,omitempty"`
+}
+
+func (r FeeRuleRequest) Rule() models.FeeRule {
+	return models.FeeRule{
+		OperationType: r.OperationType,
+		Tier:          r.Tier,
+		Currency:      r.Currency,
+		Fixed:         r.Fixed,
+		RateBps:       r.RateBps,
+		MinFee:        r.MinFee,
+		MaxFee:        r.MaxFee,
+	}
+}
+
+type FeeRuleResponse struct {
+	ID            string         `json:"id,omitempty"` // Empty for rules read from a file
+	OperationType string         `json:"operationType"`
+	Tier          *string        `json:"tier,omitempty"`
+	Currency      *string        `json:"currency,omitempty"`
+	Fixed         models.Amount  `json:"fixed"`
+	RateBps       int64          `json:"rateBps"`
+	MinFee        *models.Amount `json:"minFee,omitempty"`
+	MaxFee        *models.Amount `json:"maxFee,omitempty"`
+	CreatedAt     *time.Time     `json:"createdAt,omitempty"`
+}
+
+// SetWalletTierRequest moves a wallet to the tier fee rules match it on.
+type SetWalletTierRequest struct {
+	Tier string `json:"tier"`
+}
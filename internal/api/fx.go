@@ -0,0 +1,83 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// FXRateRequest loads an FX rate. The admin endpoint and the rates file importer take a JSON array of these.
+type FXRateRequest struct {
+	FromCurrency string     `json:"fromCurrency"`
+	ToCurrency   string     `json:"toCurrency"`
+	Rate         string     `json:"rate"`                // Minor units of toCurrency per minor unit of fromCurrency, e.g. "1.085"
+	SpreadBps    int64      `json:"spreadBps,omitempty"` // Kept by the house, in hundredths of a percent
+	ValidFrom    *time.Time `json:"validFrom,omitempty"` // Defaults to now
+	ValidTo      *time.Time `json:"validTo,omitempty"`   // Open-ended when omitted
+}
+
+func (r FXRateRequest) FXRate() models.FXRate {
+	rate := models.FXRate{
+		FromCurrency: r.FromCurrency,
+		ToCurrency:   r.ToCurrency,
+		Rate:         r.Rate,
+		SpreadBps:    r.SpreadBps,
+		ValidTo:      r.ValidTo,
+	}
+	if r.ValidFrom != nil {
+		rate.ValidFrom = *r.ValidFrom
+	}
+	return rate
+}
+
+type FXRateResponse struct {
+	ID           string     `json:"id"`
+	FromCurrency string     `json:"fromCurrency"`
+	ToCurrency   string     `json:"toCurrency"`
+	Rate         string     `json:"rate"`
+	SpreadBps    int64      `json:"spreadBps"`
+	ValidFrom    time.Time  `json:"validFrom"`
+	ValidTo      *time.Time `json:"validTo,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// FXQuoteRequest asks for the rate an exchange between two currencies would be made at now.
+type FXQuoteRequest struct {
+	FromCurrency string        `json:"fromCurrency"`
+	ToCurrency   string        `json:"toCurrency"`
+	Amount       models.Amount `json:"amount,omitempty"` // If set, the quote also shows what it converts to
+}
+
+type FXQuoteResponse struct {
+	QuoteID         string        `json:"quoteId"`
+	FromCurrency    string        `json:"fromCurrency"`
+	ToCurrency      string        `json:"toCurrency"`
+	Rate            string        `json:"rate"`
+	SpreadBps       int64         `json:"spreadBps"`
+	Amount          models.Amount `json:"amount,omitempty"`
+	ConvertedAmount models.Amount `json:"convertedAmount,omitempty"`
+	ExpiresAt       time.Time     `json:"expiresAt"`
+}
+
+// ExchangeRequest converts amount from one wallet into another wallet in a different currency at a quoted rate.
+type ExchangeRequest struct {
+	FromWalletID string        `json:"fromWalletId"`
+	ToWalletID   string        `json:"toWalletId"`
+	Amount       models.Amount `json:"amount"` // In the currency of fromWalletId
+	QuoteID      string        `json:"quoteId"`
+}
+
+type ExchangeResponse struct {
+	ExchangeID      string        `json:"exchangeId"` // transferId of both legs
+	QuoteID         string        `json:"quoteId"`
+	FromWalletID    string        `json:"fromWalletId"`
+	ToWalletID      string        `json:"toWalletId"`
+	Amount          models.Amount `json:"amount"`
+	ConvertedAmount models.Amount `json:"convertedAmount"`
+	FromCurrency    string        `json:"fromCurrency"`
+	ToCurrency      string        `json:"toCurrency"`
+	Rate            string        `json:"rate"`
+	SpreadBps       int64         `json:"spreadBps"`
+	FromBalance     models.Amount `json:"fromBalance"`
+	ToBalance       models.Amount `json:"toBalance"`
+}
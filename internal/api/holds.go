@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+type CreateHoldRequest struct {
+	WalletID   string        `json:"walletId"`
+	Amount     models.Amount `json:"amount"`
+	Currency   string        `json:"currency,omitempty"`   // ISO 4217 code, defaults to the wallet's currency
+	TTLSeconds int           `json:"ttlSeconds,omitempty"` // Defaults to WALLET_HOLD_TTL
+}
+
+type CaptureHoldRequest struct {
+	Amount models.Amount `json:"amount,omitempty"` // Defaults to the full held amount
+}
+
+type HoldResponse struct {
+	ID             string        `json:"id"`
+	WalletID       string        `json:"walletId"`
+	Amount         models.Amount `json:"amount"`
+	CapturedAmount models.Amount `json:"capturedAmount"`
+	Currency       string        `json:"currency"`
+	Status         string        `json:"status"`
+	OperationID    *string       `json:"operationId,omitempty"`
+	ExpiresAt      time.Time     `json:"expiresAt"`
+	CreatedAt      time.Time     `json:"createdAt"`
+}
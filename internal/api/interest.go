@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// SetInterestRateRequest sets the annual rate a wallet earns interest at.
+type SetInterestRateRequest struct {
+	AnnualRateBps *int64 `json:"annualRateBps"` // Hundredths of a percent, 0 stops accrual
+}
+
+// RunInterestRequest accrues interest for one day by hand, e.g. for a day the job missed.
+type RunInterestRequest struct {
+	Day string `json:"day"` // YYYY-MM-DD, in UTC
+}
+
+type InterestRunResponse struct {
+	Day     string `json:"day"`
+	Accrued int    `json:"accrued"` // Accruals recorded, zero when the day had already run
+	Paid    int    `json:"paid"`    // INTEREST operations posted for months that ended by the end of the day
+}
+
+type InterestAccrualResponse struct {
+	Day           string        `json:"day"`
+	Balance       models.Amount `json:"balance"` // End-of-day balance interest was earned on
+	RateBps       int64         `json:"rateBps"`
+	AccruedMicros int64         `json:"accruedMicros"` // In millionths of a minor unit
+	PaidAt        *time.Time    `json:"paidAt,omitempty"`
+	OperationID   *string       `json:"operationId,omitempty"`
+}
@@ -0,0 +1,19 @@
+package requests
+
+import "github.com/foreground-eclipse/wallet/internal/models"
+
+// WithdrawalLimits caps withdrawals from a wallet. A nil field means no limit at that level.
+type WithdrawalLimits struct {
+	PerOperation *models.Amount `json:"perOperation,omitempty"` // Largest single withdrawal
+	Daily        *models.Amount `json:"daily,omitempty"`        // Total withdrawn over a rolling 24 hours
+	Monthly      *models.Amount `json:"monthly,omitempty"`      // Total withdrawn over a rolling 30 days
+	HourlyCount  *int           `json:"hourlyCount,omitempty"`  // Number of withdrawals over a rolling hour
+}
+
+// WithdrawalLimitsResponse shows a wallet's own limits and the ones actually enforced,
+// where unset wallet limits fall back to the global configuration.
+type WithdrawalLimitsResponse struct {
+	WalletID  string           `json:"walletId"`
+	Wallet    WithdrawalLimits `json:"wallet"`
+	Effective WithdrawalLimits `json:"effective"`
+}
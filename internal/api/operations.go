@@ -0,0 +1,84 @@
+package requests
+
+import (
+	"github.com/foreground-eclipse/wallet/internal/models"
+
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultOperationsLimit = 50
+	MaxOperationsLimit     = 500
+)
+
+// OperationsFilter selects a page of a wallet's operation history.
+// Operations are ordered from newest to oldest by timestamp, then by id.
+type OperationsFilter struct {
+	WalletID      string
+	OperationType string
+	MinAmount     *models.Amount
+	MaxAmount     *models.Amount
+	From          *time.Time // Inclusive
+	To            *time.Time // Exclusive
+	Cursor        *OperationsCursor
+	Limit         int
+}
+
+// OperationsCursor points at the last operation of the previous page.
+type OperationsCursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+func (c OperationsCursor) Encode() string {
+	raw := c.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeOperationsCursor(cursor string) (*OperationsCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	timestamp, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &OperationsCursor{Timestamp: parsed, ID: id}, nil
+}
+
+type OperationResponse struct {
+	ID            string        `json:"id"`
+	WalletID      string        `json:"walletId"`
+	OperationType string        `json:"operationType"`
+	Amount        models.Amount `json:"amount"`
+	Currency      string        `json:"currency"`
+	Timestamp     time.Time     `json:"timestamp"`
+	TransferID    *string       `json:"transferId,omitempty"`
+	ReversalOf    *string       `json:"reversalOf,omitempty"`
+	Fee           models.Amount `json:"fee,omitempty"`          // Fee charged on this operation
+	FeeFor        *string       `json:"feeFor,omitempty"`       // Set on fees: the operation they were charged on
+	ExchangeRate  *string       `json:"exchangeRate,omitempty"` // Set on both legs of an exchange
+	SpreadBps     *int64        `json:"spreadBps,omitempty"`
+
+	Description       *string         `json:"description,omitempty"`
+	ExternalReference *string         `json:"externalReference,omitempty"`
+	Metadata          json.RawMessage `json:"metadata,omitempty"`
+}
+
+type ReverseOperationRequest struct {
+	Amount models.Amount `json:"amount,omitempty"` // Defaults to the part of the original not reversed yet
+}
+
+type OperationsPage struct {
+	Operations []OperationResponse `json:"operations"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,100 @@
+package requests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+const (
+	DefaultWalletsLimit = 50
+	MaxWalletsLimit     = 500
+)
+
+// Orders a wallet listing can be sorted in. Ties are broken by wallet id.
+const (
+	SortByCreatedAt = "createdAt"
+	SortByBalance   = "balance"
+)
+
+type CreateOwnerRequest struct {
+	ExternalID  string          `json:"externalId"` // The owner's id in the calling service
+	DisplayName string          `json:"displayName"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"` // Free-form JSON object
+}
+
+type OwnerResponse struct {
+	ID          string          `json:"id"`
+	ExternalID  string          `json:"externalId"`
+	DisplayName string          `json:"displayName"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+type SetWalletOwnerRequest struct {
+	OwnerID string `json:"ownerId"`
+}
+
+// WalletsFilter selects a page of wallets, sorted by Sort and then by wallet id.
+type WalletsFilter struct {
+	OwnerID    string
+	Status     string
+	Currency   string
+	Tier       string
+	MinBalance *models.Amount
+	MaxBalance *models.Amount
+	Sort       string // SortByCreatedAt or SortByBalance
+	Descending bool
+	Cursor     *WalletsCursor
+	Limit      int
+}
+
+// WalletsCursor points at the last wallet of the previous page. Only the field the listing is sorted by is set.
+type WalletsCursor struct {
+	CreatedAt time.Time
+	Balance   models.Amount
+	ID        string
+}
+
+func (c WalletsCursor) Encode(sort string) string {
+	value := c.CreatedAt.UTC().Format(time.RFC3339Nano)
+	if sort == SortByBalance {
+		value = strconv.FormatInt(int64(c.Balance), 10)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(value + "|" + c.ID))
+}
+
+// DecodeWalletsCursor reads a cursor written by Encode for a listing sorted the same way.
+func DecodeWalletsCursor(cursor, sort string) (*WalletsCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	value, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	decoded := &WalletsCursor{ID: id}
+	if sort == SortByBalance {
+		balance, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		decoded.Balance = models.Amount(balance)
+		return decoded, nil
+	}
+	if decoded.CreatedAt, err = time.Parse(time.RFC3339Nano, value); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return decoded, nil
+}
+
+type WalletsPage struct {
+	Wallets    []WalletBalanceResponse `json:"wallets"`
+	NextCursor string                  `json:"nextCursor,omitempty"`
+}
@@ -1,14 +1,26 @@
 package requests
 
+import (
+	"github.com/foreground-eclipse/wallet/internal/models"
+
+	"encoding/json"
+	"time"
+)
+
 const (
 	StatusOK    = "OK"
 	StatusError = "error"
 )
 
 type WalletOperationRequest struct {
-	WalletID      string `json:"valletId"`
-	OperationType string `json:"operationType"` // "DEPOSIT" or "WITHDRAW"
-	Amount        int    `json:"amount"`
+	WalletID      string        `json:"valletId"`
+	OperationType string        `json:"operationType"` // "DEPOSIT" or "WITHDRAW"; INTEREST is only posted by the interest job
+	Amount        models.Amount `json:"amount"`
+	Currency      string        `json:"currency,omitempty"` // ISO 4217 code, defaults to the wallet's currency
+
+	Description       string          `json:"description,omitempty"`
+	ExternalReference string          `json:"externalReference,omitempty"` // Caller's own id, unique per wallet
+	Metadata          json.RawMessage `json:"metadata,omitempty"`          // Free-form JSON object
 }
 
 type WalletOperationResponse struct {
@@ -17,9 +29,73 @@ type WalletOperationResponse struct {
 	Data   interface{} `json:"data,omitempty"`
 }
 
+type WalletOperationResult struct {
+	WalletID      string        `json:"walletId"`
+	OperationType string        `json:"operationType"`
+	Balance       models.Amount `json:"balance"`
+	Currency      string        `json:"currency"`
+	Fee           models.Amount `json:"fee,omitempty"` // Charged on top of the amount
+}
+
+// StoredResponse is a response recorded under an idempotency key and replayed on retries.
+type StoredResponse struct {
+	Status   int
+	Body     []byte
+	Replayed bool
+}
+
+type TransferRequest struct {
+	FromWalletID string        `json:"fromWalletId"`
+	ToWalletID   string        `json:"toWalletId"`
+	Amount       models.Amount `json:"amount"`
+}
+
+type TransferResponse struct {
+	TransferID   string        `json:"transferId"`
+	FromWalletID string        `json:"fromWalletId"`
+	ToWalletID   string        `json:"toWalletId"`
+	Amount       models.Amount `json:"amount"`
+	FromBalance  models.Amount `json:"fromBalance"`
+	ToBalance    models.Amount `json:"toBalance"`
+	Currency     string        `json:"currency"`
+}
+
 type WalletBalanceResponse struct {
-	WalletID string `json:"walletId"`
-	Balance  int    `json:"balance"`
+	WalletID       string        `json:"walletId"`
+	Balance        models.Amount `json:"balance"`   // Ledger balance
+	Available      models.Amount `json:"available"` // Balance minus active holds
+	Currency       string        `json:"currency"`
+	OverdraftLimit models.Amount `json:"overdraftLimit,omitempty"`
+	Status         string        `json:"status"`
+	Tier           string        `json:"tier,omitempty"`
+	InterestRate   int64         `json:"annualRateBps,omitempty"`
+	ParentID       *string       `json:"parentId,omitempty"`
+	OwnerID        *string       `json:"ownerId,omitempty"`
+	CreatedAt      *time.Time    `json:"createdAt,omitempty"`
+}
+
+// HistoricalBalanceResponse is a wallet's balance at a point in time.
+type HistoricalBalanceResponse struct {
+	WalletID string        `json:"walletId"`
+	Balance  models.Amount `json:"balance"`
+	Currency string        `json:"currency"`
+	AsOf     time.Time     `json:"asOf"`
+}
+
+type CreateWalletRequest struct {
+	WalletID string `json:"walletId,omitempty"` // Generated when empty
+	Currency string `json:"currency,omitempty"` // ISO 4217 code, defaults to the parent's or WALLET_DEFAULT_CURRENCY
+	ParentID string `json:"parentId,omitempty"` // Opens a sub-wallet of this wallet
+	OwnerID  string `json:"ownerId,omitempty"`  // Defaults to the parent's owner
+}
+
+type SetWalletStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// SetOverdraftLimitRequest sets how far below zero a wallet's balance may go.
+type SetOverdraftLimitRequest struct {
+	OverdraftLimit *models.Amount `json:"overdraftLimit"`
 }
 
 func WalletOperationResponseOK(data interface{}) WalletOperationResponse {
@@ -35,3 +111,16 @@ func Error(err error) WalletOperationResponse {
 		Error:  err.Error(),
 	}
 }
+
+// LedgerReport is the result of checking the double-entry ledger against wallet balances.
+type LedgerReport struct {
+	Balanced   bool                     `json:"balanced"`
+	Totals     map[string]models.Amount `json:"totals"` // Sum of all postings per currency, zero when balanced
+	Mismatches []LedgerMismatch         `json:"mismatches"`
+}
+
+type LedgerMismatch struct {
+	WalletID      string        `json:"walletId"`
+	Balance       models.Amount `json:"balance"`
+	LedgerBalance models.Amount `json:"ledgerBalance"`
+}
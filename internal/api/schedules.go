@@ -0,0 +1,44 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+type CreateScheduleRequest struct {
+	WalletID      string        `json:"walletId"`
+	OperationType string        `json:"operationType"`
+	Amount        models.Amount `json:"amount"`
+	Currency      string        `json:"currency,omitempty"`      // ISO 4217 code, must match the wallet
+	StartAt       *time.Time    `json:"startAt,omitempty"`       // First occurrence, defaults to now
+	Interval      string        `json:"interval,omitempty"`      // DAY, WEEK or MONTH; empty for a one-off operation
+	IntervalCount int           `json:"intervalCount,omitempty"` // Run every intervalCount intervals, 1 by default
+	EndAt         *time.Time    `json:"endAt,omitempty"`
+}
+
+// UpdateScheduleRequest changes the fields that are set.
+type UpdateScheduleRequest struct {
+	Amount *models.Amount `json:"amount,omitempty"`
+	Status *string        `json:"status,omitempty"` // ACTIVE or PAUSED
+	EndAt  *time.Time     `json:"endAt,omitempty"`
+}
+
+type ScheduleResponse struct {
+	ID              string        `json:"id"`
+	WalletID        string        `json:"walletId"`
+	OperationType   string        `json:"operationType"`
+	Amount          models.Amount `json:"amount"`
+	Currency        string        `json:"currency"`
+	Interval        *string       `json:"interval,omitempty"`
+	IntervalCount   int           `json:"intervalCount"`
+	StartAt         time.Time     `json:"startAt"`
+	EndAt           *time.Time    `json:"endAt,omitempty"`
+	NextRunAt       time.Time     `json:"nextRunAt"`
+	Occurrence      int           `json:"occurrence"`
+	Status          string        `json:"status"`
+	Attempts        int           `json:"attempts"`
+	LastError       *string       `json:"lastError,omitempty"`
+	LastOperationID *string       `json:"lastOperationId,omitempty"`
+	CreatedAt       time.Time     `json:"createdAt"`
+}
@@ -0,0 +1,18 @@
+package requests
+
+import "github.com/foreground-eclipse/wallet/internal/models"
+
+// WalletTreeResponse is a wallet with its sub-wallets and the balances of the whole tree under it.
+type WalletTreeResponse struct {
+	WalletBalanceResponse
+	TotalBalance   models.Amount         `json:"totalBalance"`   // Balance of the wallet and every wallet under it
+	TotalAvailable models.Amount         `json:"totalAvailable"` // Same for available balances
+	Children       []*WalletTreeResponse `json:"children"`
+}
+
+// SweepRequest moves money between a wallet and its parent or one of its children.
+type SweepRequest struct {
+	FromWalletID string        `json:"fromWalletId"`
+	ToWalletID   string        `json:"toWalletId"`
+	Amount       models.Amount `json:"amount,omitempty"` // Defaults to the whole available balance of fromWalletId
+}
@@ -0,0 +1,72 @@
+package requests
+
+import "time"
+
+// Limits of the webhook delivery log.
+const (
+	DefaultDeliveriesLimit = 50
+	MaxDeliveriesLimit     = 500
+)
+
+// EventTypes lists every event type a webhook can subscribe to.
+var EventTypes = []string{EventWalletCreated, EventOperationApplied, EventWalletStatusChanged, EventBalanceAdjusted}
+
+// CreateWebhookRequest subscribes URL to events of the given types, about one wallet, every wallet of an
+// owner or, with neither set, every wallet.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	WalletID   *string  `json:"walletId,omitempty"`
+	OwnerID    *string  `json:"ownerId,omitempty"`
+	Secret     string   `json:"secret,omitempty"` // Signing key, generated when empty
+}
+
+// UpdateWebhookRequest changes the fields that are set.
+type UpdateWebhookRequest struct {
+	URL        *string   `json:"url,omitempty"`
+	EventTypes *[]string `json:"eventTypes,omitempty"`
+	Status     *string   `json:"status,omitempty"` // ACTIVE or PAUSED
+}
+
+type WebhookResponse struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	WalletID   *string   `json:"walletId,omitempty"`
+	OwnerID    *string   `json:"ownerId,omitempty"`
+	Secret     string    `json:"secret,omitempty"` // Only returned when the subscription is created
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// WebhookDeliveriesFilter selects deliveries of a subscription, newest first.
+type WebhookDeliveriesFilter struct {
+	Status string // PENDING, DELIVERED or DEAD; any when empty
+	Limit  int
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string                   `json:"id"`
+	WebhookID      string                   `json:"webhookId"`
+	EventID        string                   `json:"eventId"`
+	EventType      string                   `json:"eventType"`
+	WalletID       string                   `json:"walletId"`
+	Status         string                   `json:"status"`
+	Attempts       int                      `json:"attempts"`
+	NextAttemptAt  *time.Time               `json:"nextAttemptAt,omitempty"` // Only while PENDING
+	LastStatusCode *int                     `json:"lastStatusCode,omitempty"`
+	LastError      *string                  `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time               `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	Body           string                   `json:"body,omitempty"`       // Only on a single delivery
+	AttemptLog     []WebhookAttemptResponse `json:"attemptLog,omitempty"` // Only on a single delivery
+}
+
+type WebhookAttemptResponse struct {
+	StatusCode   *int      `json:"statusCode,omitempty"`
+	Error        *string   `json:"error,omitempty"`
+	ResponseBody *string   `json:"responseBody,omitempty"`
+	DurationMs   int64     `json:"durationMs"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
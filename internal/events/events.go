@@ -0,0 +1,59 @@
+// Package events publishes the wallet events the outbox dispatcher reads from storage.
+package events
+
+import (
+	"context"
+	"errors"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"go.uber.org/zap"
+)
+
+// Publisher hands an event to downstream consumers. The outbox gives at-least-once delivery: an event whose
+// publication fails, or whose success is not recorded before a crash, is published again, so consumers
+// must be ready to see an event id twice.
+type Publisher interface {
+	Publish(ctx context.Context, event requests.EventEnvelope) error
+}
+
+// PublisherFunc lets a plain function act as a Publisher.
+type PublisherFunc func(ctx context.Context, event requests.EventEnvelope) error
+
+func (f PublisherFunc) Publish(ctx context.Context, event requests.EventEnvelope) error {
+	return f(ctx, event)
+}
+
+// Publishers hands every event to each of its publishers in turn. If any of them fails the event is
+// published again later, to all of them.
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, event requests.EventEnvelope) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogPublisher writes events to the log. It is the publisher the server uses when no other is wired in.
+type LogPublisher struct {
+	logger *zap.Logger
+}
+
+func NewLogPublisher(logger *zap.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event requests.EventEnvelope) error {
+	p.logger.Info("published event",
+		zap.String("id", event.ID),
+		zap.Int64("sequence", event.Sequence),
+		zap.String("type", event.Type),
+		zap.Int("version", event.Version),
+		zap.String("walletId", event.WalletID),
+		zap.ByteString("data", event.Data),
+	)
+	return nil
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AmountFormatHeader lets clients ask for amounts as decimal strings instead of JSON numbers,
+// for clients that parse numbers as doubles and would lose precision above 2^53.
+// Amounts in requests are accepted in either form regardless of the header.
+const AmountFormatHeader = "Amount-Format"
+
+// amountFields are the JSON keys holding amounts in minor units. Every number under one of them,
+// including the values of maps such as the ledger totals, is written as a string.
+var amountFields = map[string]bool{
+	"amount":          true,
+	"balance":         true,
+	"available":       true,
+	"overdraftLimit":  true,
+	"capturedAmount":  true,
+	"fromBalance":     true,
+	"toBalance":       true,
+	"ledgerBalance":   true,
+	"openingBalance":  true,
+	"closingBalance":  true,
+	"perOperation":    true,
+	"daily":           true,
+	"monthly":         true,
+	"totals":          true,
+	"fee":             true,
+	"fixed":           true,
+	"minFee":          true,
+	"maxFee":          true,
+	"convertedAmount": true,
+	"totalBalance":    true,
+	"totalAvailable":  true,
+}
+
+// AmountFormat rewrites the amounts in JSON responses as strings when the request carries
+// "Amount-Format: string". JSON is rewritten as it is written, so streamed statements stay streamed;
+// other content types pass through untouched.
+func AmountFormat() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.EqualFold(c.GetHeader(AmountFormatHeader), "string") {
+			c.Next()
+			return
+		}
+
+		writer := &amountWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// amountWriter quotes the numbers under amount fields on the way out. It scans the body one byte at a time
+// and only remembers where it is in the document, so a value may be split across writes.
+type amountWriter struct {
+	gin.ResponseWriter
+	decided, json bool
+
+	containers []container
+	expectKey  bool
+	inString   bool
+	escaped    bool
+	isKey      bool
+	key        []byte
+	inAmount   bool
+}
+
+// container is an object or array the scanner is inside of. amount is set when every number in it is an amount.
+type container struct {
+	object    bool
+	amount    bool
+	keyAmount bool // the current key of an object is an amount field
+}
+
+func (w *amountWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.json = strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	if !w.json {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, err := w.ResponseWriter.Write(w.rewrite(data)); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *amountWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// rewrite returns data with the numbers under amount fields quoted.
+func (w *amountWriter) rewrite(data []byte) []byte {
+	out := make([]byte, 0, len(data)+8)
+	for _, b := range data {
+		if w.inString {
+			out = append(out, b)
+			if w.isKey {
+				w.key = append(w.key, b)
+			}
+			switch {
+			case w.escaped:
+				w.escaped = false
+			case b == '\\':
+				w.escaped = true
+			case b == '"':
+				w.inString = false
+				if w.isKey {
+					var key string
+					_ = json.Unmarshal(w.key, &key)
+					w.containers[len(w.containers)-1].keyAmount = amountFields[key]
+				}
+			}
+			continue
+		}
+		if w.inAmount {
+			if isNumberByte(b) {
+				out = append(out, b)
+				continue
+			}
+			out = append(out, '"')
+			w.inAmount = false
+		}
+
+		switch {
+		case b == '"':
+			w.inString = true
+			w.isKey = w.expectKey && len(w.containers) > 0 && w.containers[len(w.containers)-1].object
+			w.key = append(w.key[:0], b)
+		case b == '{' || b == '[':
+			w.containers = append(w.containers, container{object: b == '{', amount: w.amountValue()})
+			w.expectKey = b == '{'
+		case b == '}' || b == ']':
+			if len(w.containers) > 0 {
+				w.containers = w.containers[:len(w.containers)-1]
+			}
+		case b == ',':
+			w.expectKey = len(w.containers) > 0 && w.containers[len(w.containers)-1].object
+		case b == ':':
+			w.expectKey = false
+		case b == '-' || (b >= '0' && b <= '9'):
+			if w.amountValue() {
+				out = append(out, '"')
+				w.inAmount = true
+			}
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// amountValue reports whether a value starting at the current position is an amount.
+func (w *amountWriter) amountValue() bool {
+	if len(w.containers) == 0 {
+		return false
+	}
+	top := w.containers[len(w.containers)-1]
+	return top.amount || (top.object && top.keyAmount)
+}
+
+func isNumberByte(b byte) bool {
+	return (b >= '0' && b <= '9') || b == '-' || b == '+' || b == '.' || b == 'e' || b == 'E'
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmountFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AmountFormat())
+	router.GET("/balance", func(c *gin.Context) {
+		c.JSON(http.StatusOK, requests.WalletOperationResponseOK(requests.WalletBalanceResponse{
+			WalletID:  "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+			Balance:   9007199254740993,
+			Available: -5,
+			Currency:  "USD",
+			Status:    models.WalletActive,
+		}))
+	})
+	router.GET("/ledger", func(c *gin.Context) {
+		c.JSON(http.StatusOK, requests.WalletOperationResponseOK(requests.LedgerReport{
+			Balanced:   true,
+			Totals:     map[string]models.Amount{"USD": 0},
+			Mismatches: []requests.LedgerMismatch{},
+		}))
+	})
+	router.GET("/csv", func(c *gin.Context) {
+		c.Header("Content-Type", "text/csv")
+		c.Writer.WriteString("amount,balance\n")
+		c.Writer.Flush()
+		c.Writer.WriteString("-5,9007199254740993\n")
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Writer.WriteString(`{"status":"OK","data":{"openingBalance":12`)
+		c.Writer.Flush()
+		c.Writer.WriteString(`34,"operations":[{"id":"o\"1","amount":-5}`)
+		c.Writer.Flush()
+		c.Writer.WriteString(`,{"id":"o2","amount":7,"balance":1241}],"closingBalance":1241}}`)
+	})
+	router.GET("/error", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, requests.Error(assert.AnError))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		format         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Numbers By Default",
+			path:           "/balance",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":9007199254740993,"available":-5,"currency":"USD","status":"ACTIVE"}}`,
+		},
+		{
+			name:           "Strings On Request",
+			path:           "/balance",
+			format:         "string",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":"9007199254740993","available":"-5","currency":"USD","status":"ACTIVE"}}`,
+		},
+		{
+			name:           "Map Of Amounts",
+			path:           "/ledger",
+			format:         "string",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"balanced":true,"totals":{"USD":"0"},"mismatches":[]}}`,
+		},
+		{
+			name:           "Streamed JSON",
+			path:           "/stream",
+			format:         "string",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"openingBalance":"1234","operations":[{"id":"o\"1","amount":"-5"},{"id":"o2","amount":"7","balance":"1241"}],"closingBalance":"1241"}}`,
+		},
+		{
+			name:           "CSV Untouched",
+			path:           "/csv",
+			format:         "string",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "amount,balance\n-5,9007199254740993\n",
+		},
+		{
+			name:           "Error Untouched",
+			path:           "/error",
+			format:         "string",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"` + assert.AnError.Error() + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.format != "" {
+				req.Header.Set(AmountFormatHeader, tt.format)
+			}
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Equal(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
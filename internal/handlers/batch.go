@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxBatchSize caps the number of operations in one batch, which all run in a single transaction.
+const maxBatchSize = 1000
+
+type BatchOperationHandler interface {
+	ProcessBatch(ctx context.Context, req requests.BatchOperationRequest) (*requests.BatchOperationResponse, error)
+}
+
+func HandleBatchOperation(logger *zap.Logger, handler BatchOperationHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.BatchOperationRequest
+		const op = "api/v1/wallet/batch"
+
+		logger.Info("proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+
+		if err := validateBatchRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		batchChan := make(chan *requests.BatchOperationResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			batch, err := handler.ProcessBatch(c.Request.Context(), req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			batchChan <- batch
+		}()
+		select {
+		case batch := <-batchChan:
+			for i := range batch.Results {
+				if result := &batch.Results[i]; result.Err != nil {
+					_, message := operationErrorStatus(result.Err)
+					result.Error = message + ": " + result.Err.Error()
+				}
+			}
+			logRequest(c, logger, "request procceeded successfully",
+				zap.String("mode", batch.Mode),
+				zap.Int("succeeded", batch.Succeeded),
+				zap.Int("failed", batch.Failed),
+			)
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(batch))
+		case err := <-errChan:
+			handleOperationError(c, logger, err)
+		}
+	}
+}
+
+func validateBatchRequest(req requests.BatchOperationRequest) error {
+	switch req.Mode {
+	case "", requests.BatchAtomic, requests.BatchBestEffort:
+	default:
+		return errors.New("mode must be ATOMIC or BEST_EFFORT")
+	}
+	if len(req.Operations) == 0 {
+		return errors.New("operations must not be empty")
+	}
+	if len(req.Operations) > maxBatchSize {
+		return fmt.Errorf("a batch holds at most %d operations", maxBatchSize)
+	}
+	for i, item := range req.Operations {
+		if err := validateRequest(item); err != nil {
+			return fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return nil
+}
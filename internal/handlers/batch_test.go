@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockBatchOperationHandler struct {
+	ProcessBatchFunc func(ctx context.Context, req requests.BatchOperationRequest) (*requests.BatchOperationResponse, error)
+}
+
+func (m *mockBatchOperationHandler) ProcessBatch(ctx context.Context, req requests.BatchOperationRequest) (*requests.BatchOperationResponse, error) {
+	if m.ProcessBatchFunc != nil {
+		return m.ProcessBatchFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func TestHandleBatchOperation(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	balance := models.Amount(400)
+
+	tests := []struct {
+		name             string
+		requestBody      string
+		mockProcessBatch func(ctx context.Context, req requests.BatchOperationRequest) (*requests.BatchOperationResponse, error)
+		expectedStatus   int
+		expectedBody     string
+	}{
+		{
+			name:           "Empty Operations",
+			requestBody:    `{"operations": []}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: operations must not be empty"}`,
+		},
+		{
+			name:           "Bad Mode",
+			requestBody:    `{"mode": "SOME", "operations": [{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 100}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: mode must be ATOMIC or BEST_EFFORT"}`,
+		},
+		{
+			name:           "Invalid Item",
+			requestBody:    `{"operations": [{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 100}, {"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": -1}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: operation 1: amount must be a positive integer"}`,
+		},
+		{
+			name:        "Atomic Failure",
+			requestBody: `{"operations": [{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 100}]}`,
+			mockProcessBatch: func(ctx context.Context, req requests.BatchOperationRequest) (*requests.BatchOperationResponse, error) {
+				return nil, requests.BatchItemError{Index: 0, Err: requests.InsufficientFundsError{}}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"status":"error","error":"balance cant become negative: operation 0: insufficient funds: 0 left to spend"}`,
+		},
+		{
+			name: "Best Effort",
+			requestBody: `{"mode": "BEST_EFFORT", "operations": [{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 400},` +
+				`{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 500}]}`,
+			mockProcessBatch: func(ctx context.Context, req requests.BatchOperationRequest) (*requests.BatchOperationResponse, error) {
+				return &requests.BatchOperationResponse{
+					Mode:      req.Mode,
+					Succeeded: 1,
+					Failed:    1,
+					Results: []requests.BatchItemResult{
+						{Index: 0, WalletID: req.Operations[0].WalletID, OperationType: "DEPOSIT", Amount: 400, Status: requests.StatusOK,
+							OperationID: "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11", Balance: &balance, Currency: "USD"},
+						{Index: 1, WalletID: req.Operations[1].WalletID, OperationType: "WITHDRAW", Amount: 500, Status: requests.StatusError,
+							Err: requests.InsufficientFundsError{Headroom: 400}},
+					},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"status":"OK","data":{"mode":"BEST_EFFORT","succeeded":1,"failed":1,"results":[` +
+				`{"index":0,"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"DEPOSIT","amount":400,"status":"OK","operationId":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","balance":400,"currency":"USD"},` +
+				`{"index":1,"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"WITHDRAW","amount":500,"status":"error","error":"balance cant become negative: insufficient funds: 400 left to spend"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockBatchOperationHandler{
+				ProcessBatchFunc: tt.mockProcessBatch,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/wallet/batch", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleBatchOperation(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type FeeRuleHandler interface {
+	ListFeeRules(ctx context.Context) ([]models.FeeRule, error)
+	CreateFeeRule(ctx context.Context, req requests.FeeRuleRequest) (*models.FeeRule, error)
+	DeleteFeeRule(ctx context.Context, ruleID string) (*models.FeeRule, error)
+}
+
+func HandleListFeeRules(logger *zap.Logger, handler FeeRuleHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/admin/fee-rules"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		rulesChan := make(chan []models.FeeRule, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			rules, err := handler.ListFeeRules(c.Request.Context())
+			if err != nil {
+				errChan <- err
+				return
+			}
+			rulesChan <- rules
+		}()
+		select {
+		case rules := <-rulesChan:
+			resp := make([]requests.FeeRuleResponse, len(rules))
+			for i := range rules {
+				resp[i] = feeRuleResponse(&rules[i])
+			}
+			logRequest(c, logger, "request procceeded successfully", zap.Int("rules", len(resp)))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(resp))
+		case err := <-errChan:
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+func HandleCreateFeeRule(logger *zap.Logger, handler FeeRuleHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.FeeRuleRequest
+		const op = "api/v1/admin/fee-rules"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateFeeRuleRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		runFeeRuleAction(c, logger, http.StatusCreated, func(ctx context.Context) (*models.FeeRule, error) {
+			return handler.CreateFeeRule(ctx, req)
+		})
+	}
+}
+
+func HandleDeleteFeeRule(logger *zap.Logger, handler FeeRuleHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/admin/fee-rules/{ruleId}"
+		ruleID := c.Param("ruleId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("ruleId", ruleID))
+
+		if _, err := uuid.Parse(ruleID); err != nil {
+			logError(c, logger, errors.New("invalid rule id format"), http.StatusBadRequest, "invalid ruleId format")
+			return
+		}
+
+		runFeeRuleAction(c, logger, http.StatusOK, func(ctx context.Context) (*models.FeeRule, error) {
+			return handler.DeleteFeeRule(ctx, ruleID)
+		})
+	}
+}
+
+// runFeeRuleAction runs a change to the fee rules and writes the rule it returns, or the error, as the response.
+func runFeeRuleAction(c *gin.Context, logger *zap.Logger, status int, action func(ctx context.Context) (*models.FeeRule, error)) {
+	ruleChan := make(chan *models.FeeRule, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		rule, err := action(c.Request.Context())
+		if err != nil {
+			errChan <- err
+			return
+		}
+		ruleChan <- rule
+	}()
+	select {
+	case rule := <-ruleChan:
+		logRequest(c, logger, "request procceeded successfully", zap.String("ruleId", rule.ID))
+		c.JSON(status, requests.WalletOperationResponseOK(feeRuleResponse(rule)))
+	case err := <-errChan:
+		if errors.Is(err, sql.ErrNoRows) {
+			logError(c, logger, err, http.StatusNotFound, "fee rule not found")
+			return
+		}
+		var readOnlyErr requests.FeeRulesReadOnlyError
+		if errors.As(err, &readOnlyErr) {
+			logError(c, logger, err, http.StatusConflict, "fee rules are read-only")
+			return
+		}
+		logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func feeRuleResponse(rule *models.FeeRule) requests.FeeRuleResponse {
+	resp := requests.FeeRuleResponse{
+		ID:            rule.ID,
+		OperationType: rule.OperationType,
+		Tier:          rule.Tier,
+		Currency:      rule.Currency,
+		Fixed:         rule.Fixed,
+		RateBps:       rule.RateBps,
+		MinFee:        rule.MinFee,
+		MaxFee:        rule.MaxFee,
+	}
+	if !rule.CreatedAt.IsZero() {
+		createdAt := rule.CreatedAt
+		resp.CreatedAt = &createdAt
+	}
+	return resp
+}
+
+func validateFeeRuleRequest(req requests.FeeRuleRequest) error {
+	if err := req.Rule().Validate(); err != nil {
+		return err
+	}
+	if req.Currency != nil {
+		return validateCurrency(*req.Currency)
+	}
+	return nil
+}
+
+type WalletTierSetter interface {
+	SetWalletTier(ctx context.Context, walletID, tier string) (*requests.WalletBalanceResponse, error)
+}
+
+func HandleSetWalletTier(logger *zap.Logger, setter WalletTierSetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.SetWalletTierRequest
+		const op = "api/v1/admin/wallets/{walletId}/tier"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := models.ValidateTier(req.Tier); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		walletChan := make(chan *requests.WalletBalanceResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			wallet, err := setter.SetWalletTier(c.Request.Context(), walletID, req.Tier)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			walletChan <- wallet
+		}()
+		select {
+		case wallet := <-walletChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.String("tier", wallet.Tier))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(wallet))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockFeeRuleHandler struct {
+	ListFeeRulesFunc  func(ctx context.Context) ([]models.FeeRule, error)
+	CreateFeeRuleFunc func(ctx context.Context, req requests.FeeRuleRequest) (*models.FeeRule, error)
+	DeleteFeeRuleFunc func(ctx context.Context, ruleID string) (*models.FeeRule, error)
+}
+
+func (m *mockFeeRuleHandler) ListFeeRules(ctx context.Context) ([]models.FeeRule, error) {
+	if m.ListFeeRulesFunc != nil {
+		return m.ListFeeRulesFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockFeeRuleHandler) CreateFeeRule(ctx context.Context, req requests.FeeRuleRequest) (*models.FeeRule, error) {
+	if m.CreateFeeRuleFunc != nil {
+		return m.CreateFeeRuleFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *mockFeeRuleHandler) DeleteFeeRule(ctx context.Context, ruleID string) (*models.FeeRule, error) {
+	if m.DeleteFeeRuleFunc != nil {
+		return m.DeleteFeeRuleFunc(ctx, ruleID)
+	}
+	return nil, nil
+}
+
+type mockWalletTierSetter struct {
+	SetWalletTierFunc func(ctx context.Context, walletID, tier string) (*requests.WalletBalanceResponse, error)
+}
+
+func (m *mockWalletTierSetter) SetWalletTier(ctx context.Context, walletID, tier string) (*requests.WalletBalanceResponse, error) {
+	if m.SetWalletTierFunc != nil {
+		return m.SetWalletTierFunc(ctx, walletID, tier)
+	}
+	return nil, nil
+}
+
+const testFeeRuleID = "c3d4e5f6-a7b8-9012-3456-7890abcdef12"
+
+func TestHandleCreateFeeRule(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		requestBody       string
+		mockCreateFeeRule func(ctx context.Context, req requests.FeeRuleRequest) (*models.FeeRule, error)
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:           "Empty Body",
+			requestBody:    ``,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"failed to process request: empty json"}`,
+		},
+		{
+			name:           "Unknown Operation Type",
+			requestBody:    `{"operationType": "TRANSFER", "fixed": 10}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: operationType must be DEPOSIT or WITHDRAW"}`,
+		},
+		{
+			name:           "Rate Above 100%",
+			requestBody:    `{"operationType": "WITHDRAW", "rateBps": 10001}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: rateBps must be between 0 and 10000"}`,
+		},
+		{
+			name:           "Max Below Min",
+			requestBody:    `{"operationType": "WITHDRAW", "rateBps": 100, "minFee": 50, "maxFee": 10}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: maxFee cant be below minFee"}`,
+		},
+		{
+			name:           "Invalid Tier",
+			requestBody:    `{"operationType": "WITHDRAW", "tier": "gold"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: tier must be 1 to 32 uppercase letters, digits or underscores"}`,
+		},
+		{
+			name:           "Invalid Currency",
+			requestBody:    `{"operationType": "WITHDRAW", "currency": "usd"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: currency must be an ISO 4217 code"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"operationType": "WITHDRAW", "tier": "GOLD", "fixed": 10, "rateBps": 150, "maxFee": 500}`,
+			mockCreateFeeRule: func(ctx context.Context, req requests.FeeRuleRequest) (*models.FeeRule, error) {
+				rule := req.Rule()
+				rule.ID = testFeeRuleID
+				rule.CreatedAt = createdAt
+				return &rule, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":{"id":"c3d4e5f6-a7b8-9012-3456-7890abcdef12","operationType":"WITHDRAW","tier":"GOLD","fixed":10,"rateBps":150,"maxFee":500,"createdAt":"2024-03-01T12:00:00Z"}}`,
+		},
+		{
+			name:        "Rules Loaded From File",
+			requestBody: `{"operationType": "WITHDRAW", "fixed": 10}`,
+			mockCreateFeeRule: func(ctx context.Context, req requests.FeeRuleRequest) (*models.FeeRule, error) {
+				return nil, requests.FeeRulesReadOnlyError{File: "fees.json"}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"fee rules are read-only: fee rules are loaded from fees.json"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockFeeRuleHandler{
+				CreateFeeRuleFunc: tt.mockCreateFeeRule,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/admin/fee-rules", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleCreateFeeRule(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleDeleteFeeRule(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name              string
+		ruleId            string
+		mockDeleteFeeRule func(ctx context.Context, ruleID string) (*models.FeeRule, error)
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:           "Invalid UUID",
+			ruleId:         "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid ruleId format: invalid rule id format"}`,
+		},
+		{
+			name:   "Success",
+			ruleId: testFeeRuleID,
+			mockDeleteFeeRule: func(ctx context.Context, ruleID string) (*models.FeeRule, error) {
+				return &models.FeeRule{ID: ruleID, OperationType: "WITHDRAW", Fixed: 10}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"id":"c3d4e5f6-a7b8-9012-3456-7890abcdef12","operationType":"WITHDRAW","fixed":10,"rateBps":0}}`,
+		},
+		{
+			name:   "Not Found",
+			ruleId: testFeeRuleID,
+			mockDeleteFeeRule: func(ctx context.Context, ruleID string) (*models.FeeRule, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"fee rule not found: sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockFeeRuleHandler{
+				DeleteFeeRuleFunc: tt.mockDeleteFeeRule,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/admin/fee-rules/%s", tt.ruleId), nil)
+			c.Params = []gin.Param{{Key: "ruleId", Value: tt.ruleId}}
+
+			HandleDeleteFeeRule(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleSetWalletTier(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const walletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+
+	tests := []struct {
+		name              string
+		walletId          string
+		requestBody       string
+		mockSetWalletTier func(ctx context.Context, walletID, tier string) (*requests.WalletBalanceResponse, error)
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:           "Invalid UUID",
+			walletId:       "invalid-uuid",
+			requestBody:    `{"tier": "GOLD"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid walletId format: invalid wallet id format"}`,
+		},
+		{
+			name:           "Missing Tier",
+			walletId:       walletID,
+			requestBody:    `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: tier must be 1 to 32 uppercase letters, digits or underscores"}`,
+		},
+		{
+			name:        "Success",
+			walletId:    walletID,
+			requestBody: `{"tier": "GOLD"}`,
+			mockSetWalletTier: func(ctx context.Context, id, tier string) (*requests.WalletBalanceResponse, error) {
+				return &requests.WalletBalanceResponse{
+					WalletID:  id,
+					Balance:   100,
+					Available: 100,
+					Currency:  "USD",
+					Status:    "ACTIVE",
+					Tier:      tier,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":100,"available":100,"currency":"USD","status":"ACTIVE","tier":"GOLD"}}`,
+		},
+		{
+			name:        "Wallet Not Found",
+			walletId:    walletID,
+			requestBody: `{"tier": "GOLD"}`,
+			mockSetWalletTier: func(ctx context.Context, id, tier string) (*requests.WalletBalanceResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: no such a wallet"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWalletTierSetter{
+				SetWalletTierFunc: tt.mockSetWalletTier,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/admin/wallets/%s/tier", tt.walletId), bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "walletId", Value: tt.walletId}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleSetWalletTier(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type FXRateHandler interface {
+	ImportFXRates(ctx context.Context, rates []models.FXRate) ([]models.FXRate, error)
+	ListFXRates(ctx context.Context, fromCurrency, toCurrency string) ([]models.FXRate, error)
+}
+
+type FXQuoter interface {
+	CreateFXQuote(ctx context.Context, req requests.FXQuoteRequest) (*requests.FXQuoteResponse, error)
+}
+
+type ExchangeHandler interface {
+	Exchange(ctx context.Context, req requests.ExchangeRequest) (*requests.ExchangeResponse, error)
+}
+
+// HandleImportFXRates loads a JSON array of rates, all of them or none.
+func HandleImportFXRates(logger *zap.Logger, handler FXRateHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqs []requests.FXRateRequest
+		const op = "api/v1/admin/fx/rates"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&reqs); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if len(reqs) == 0 {
+			logError(c, logger, errors.New("no rates"), http.StatusBadRequest, "bad request data")
+			return
+		}
+		rates := make([]models.FXRate, len(reqs))
+		for i, req := range reqs {
+			if err := validateFXRateRequest(req); err != nil {
+				logError(c, logger, fmt.Errorf("rate %d: %w", i, err), http.StatusBadRequest, "bad request data")
+				return
+			}
+			rates[i] = req.FXRate()
+		}
+
+		ratesChan := make(chan []models.FXRate, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			imported, err := handler.ImportFXRates(c.Request.Context(), rates)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			ratesChan <- imported
+		}()
+		select {
+		case imported := <-ratesChan:
+			logRequest(c, logger, "request procceeded successfully", zap.Int("rates", len(imported)))
+			c.JSON(http.StatusCreated, requests.WalletOperationResponseOK(fxRateResponses(imported)))
+		case err := <-errChan:
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+func HandleListFXRates(logger *zap.Logger, handler FXRateHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/admin/fx/rates"
+		fromCurrency, toCurrency := c.Query("from"), c.Query("to")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		for _, code := range []string{fromCurrency, toCurrency} {
+			if code == "" {
+				continue
+			}
+			if err := validateCurrency(code); err != nil {
+				logError(c, logger, err, http.StatusBadRequest, "bad request data")
+				return
+			}
+		}
+
+		ratesChan := make(chan []models.FXRate, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			rates, err := handler.ListFXRates(c.Request.Context(), fromCurrency, toCurrency)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			ratesChan <- rates
+		}()
+		select {
+		case rates := <-ratesChan:
+			logRequest(c, logger, "request procceeded successfully", zap.Int("rates", len(rates)))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(fxRateResponses(rates)))
+		case err := <-errChan:
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+// HandleCreateFXQuote locks the current rate between two currencies for a short while.
+func HandleCreateFXQuote(logger *zap.Logger, quoter FXQuoter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.FXQuoteRequest
+		const op = "api/v1/fx/quotes"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateFXQuoteRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		quoteChan := make(chan *requests.FXQuoteResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			quote, err := quoter.CreateFXQuote(c.Request.Context(), req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			quoteChan <- quote
+		}()
+		select {
+		case quote := <-quoteChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("quoteId", quote.QuoteID), zap.String("rate", quote.Rate))
+			c.JSON(http.StatusCreated, requests.WalletOperationResponseOK(quote))
+		case err := <-errChan:
+			var rateNotFoundErr requests.FXRateNotFoundError
+			if errors.As(err, &rateNotFoundErr) {
+				logError(c, logger, err, http.StatusNotFound, "rate not found")
+				return
+			}
+			if errors.Is(err, models.ErrAmountOverflow) || errors.Is(err, models.ErrConvertedTooSmall) {
+				logError(c, logger, err, http.StatusUnprocessableEntity, "amount out of range")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+// HandleExchange converts money between two wallets of different currencies at a quoted rate.
+func HandleExchange(logger *zap.Logger, handler ExchangeHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.ExchangeRequest
+		const op = "api/v1/exchanges"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateExchangeRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		exchangeChan := make(chan *requests.ExchangeResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			exchange, err := handler.Exchange(c.Request.Context(), req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			exchangeChan <- exchange
+		}()
+		select {
+		case exchange := <-exchangeChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("exchangeId", exchange.ExchangeID))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(exchange))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, err, http.StatusNotFound, "wallet not found")
+				return
+			}
+			var quoteNotUsableErr requests.QuoteNotUsableError
+			if errors.As(err, &quoteNotUsableErr) {
+				logError(c, logger, err, http.StatusConflict, "quote not usable")
+				return
+			}
+			var insufficientFundsErr requests.InsufficientFundsError
+			if errors.As(err, &insufficientFundsErr) {
+				logError(c, logger, err, http.StatusForbidden, "balance cant become negative")
+				return
+			}
+			var withdrawalLimitErr requests.WithdrawalLimitError
+			if errors.As(err, &withdrawalLimitErr) {
+				logError(c, logger, err, http.StatusForbidden, "withdrawal limit exceeded")
+				return
+			}
+			if errors.Is(err, models.ErrAmountOverflow) || errors.Is(err, models.ErrConvertedTooSmall) {
+				logError(c, logger, err, http.StatusUnprocessableEntity, "amount out of range")
+				return
+			}
+			var walletNotActiveErr requests.WalletNotActiveError
+			if errors.As(err, &walletNotActiveErr) {
+				logError(c, logger, err, http.StatusConflict, "wallet not active")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+func validateFXRateRequest(req requests.FXRateRequest) error {
+	for _, code := range []string{req.FromCurrency, req.ToCurrency} {
+		if err := validateCurrency(code); err != nil {
+			return err
+		}
+	}
+	rate := req.FXRate()
+	if rate.ValidFrom.IsZero() {
+		// Storage starts the rate now; it must not have ended by then.
+		rate.ValidFrom = time.Now().UTC()
+	}
+	return rate.Validate()
+}
+
+func validateFXQuoteRequest(req requests.FXQuoteRequest) error {
+	for _, code := range []string{req.FromCurrency, req.ToCurrency} {
+		if err := validateCurrency(code); err != nil {
+			return err
+		}
+	}
+	if req.FromCurrency == req.ToCurrency {
+		return errors.New("fromCurrency and toCurrency must differ")
+	}
+	if req.Amount < 0 {
+		return errors.New("amount must be a positive integer")
+	}
+	return nil
+}
+
+func validateExchangeRequest(req requests.ExchangeRequest) error {
+	if err := validateTransferRequest(requests.TransferRequest{FromWalletID: req.FromWalletID, ToWalletID: req.ToWalletID, Amount: req.Amount}); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(req.QuoteID); err != nil {
+		return errors.New("invalid quote id format")
+	}
+	return nil
+}
+
+func fxRateResponses(rates []models.FXRate) []requests.FXRateResponse {
+	resp := make([]requests.FXRateResponse, len(rates))
+	for i, rate := range rates {
+		resp[i] = requests.FXRateResponse{
+			ID:           rate.ID,
+			FromCurrency: rate.FromCurrency,
+			ToCurrency:   rate.ToCurrency,
+			Rate:         rate.Rate,
+			SpreadBps:    rate.SpreadBps,
+			ValidFrom:    rate.ValidFrom,
+			ValidTo:      rate.ValidTo,
+			CreatedAt:    rate.CreatedAt,
+		}
+	}
+	return resp
+}
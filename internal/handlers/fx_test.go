@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockFXHandler struct {
+	ImportFXRatesFunc func(ctx context.Context, rates []models.FXRate) ([]models.FXRate, error)
+	ListFXRatesFunc   func(ctx context.Context, fromCurrency, toCurrency string) ([]models.FXRate, error)
+	CreateFXQuoteFunc func(ctx context.Context, req requests.FXQuoteRequest) (*requests.FXQuoteResponse, error)
+	ExchangeFunc      func(ctx context.Context, req requests.ExchangeRequest) (*requests.ExchangeResponse, error)
+}
+
+func (m *mockFXHandler) ImportFXRates(ctx context.Context, rates []models.FXRate) ([]models.FXRate, error) {
+	if m.ImportFXRatesFunc != nil {
+		return m.ImportFXRatesFunc(ctx, rates)
+	}
+	return nil, nil
+}
+
+func (m *mockFXHandler) ListFXRates(ctx context.Context, fromCurrency, toCurrency string) ([]models.FXRate, error) {
+	if m.ListFXRatesFunc != nil {
+		return m.ListFXRatesFunc(ctx, fromCurrency, toCurrency)
+	}
+	return nil, nil
+}
+
+func (m *mockFXHandler) CreateFXQuote(ctx context.Context, req requests.FXQuoteRequest) (*requests.FXQuoteResponse, error) {
+	if m.CreateFXQuoteFunc != nil {
+		return m.CreateFXQuoteFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *mockFXHandler) Exchange(ctx context.Context, req requests.ExchangeRequest) (*requests.ExchangeResponse, error) {
+	if m.ExchangeFunc != nil {
+		return m.ExchangeFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+const (
+	testFromWalletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+	testToWalletID   = "b2c3d4e5-f6a7-8901-2345-67890abcdef1"
+	testQuoteID      = "d4e5f6a7-b8c9-0123-4567-890abcdef123"
+	testExchangeID   = "e5f6a7b8-c9d0-1234-5678-90abcdef1234"
+)
+
+func TestHandleImportFXRates(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	validFrom := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		requestBody       string
+		mockImportFXRates func(ctx context.Context, rates []models.FXRate) ([]models.FXRate, error)
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:           "No Rates",
+			requestBody:    `[]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: no rates"}`,
+		},
+		{
+			name:           "Invalid Rate",
+			requestBody:    `[{"fromCurrency": "USD", "toCurrency": "EUR", "rate": "0.92"}, {"fromCurrency": "EUR", "toCurrency": "USD", "rate": "-1"}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: rate 1: rate must be a positive decimal number"}`,
+		},
+		{
+			name:           "Same Currency",
+			requestBody:    `[{"fromCurrency": "USD", "toCurrency": "USD", "rate": "1"}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: rate 0: fromCurrency and toCurrency must differ"}`,
+		},
+		{
+			name:           "Window Ends Before It Starts",
+			requestBody:    `[{"fromCurrency": "USD", "toCurrency": "EUR", "rate": "0.92", "validFrom": "2024-03-02T00:00:00Z", "validTo": "2024-03-01T00:00:00Z"}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: rate 0: validTo must be after validFrom"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `[{"fromCurrency": "USD", "toCurrency": "EUR", "rate": "0.9200", "spreadBps": 50, "validFrom": "2024-03-01T00:00:00Z"}]`,
+			mockImportFXRates: func(ctx context.Context, rates []models.FXRate) ([]models.FXRate, error) {
+				rates[0].ID = testExchangeID
+				rates[0].Rate = "0.92"
+				rates[0].CreatedAt = validFrom
+				return rates, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":[{"id":"e5f6a7b8-c9d0-1234-5678-90abcdef1234","fromCurrency":"USD","toCurrency":"EUR","rate":"0.92","spreadBps":50,"validFrom":"2024-03-01T00:00:00Z","createdAt":"2024-03-01T00:00:00Z"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockFXHandler{
+				ImportFXRatesFunc: tt.mockImportFXRates,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/admin/fx/rates", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleImportFXRates(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleCreateFXQuote(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	expiresAt := time.Date(2024, 3, 1, 12, 0, 30, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		requestBody       string
+		mockCreateFXQuote func(ctx context.Context, req requests.FXQuoteRequest) (*requests.FXQuoteResponse, error)
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:           "Invalid Currency",
+			requestBody:    `{"fromCurrency": "usd", "toCurrency": "EUR"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: currency must be an ISO 4217 code"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"fromCurrency": "USD", "toCurrency": "EUR", "amount": 10000}`,
+			mockCreateFXQuote: func(ctx context.Context, req requests.FXQuoteRequest) (*requests.FXQuoteResponse, error) {
+				return &requests.FXQuoteResponse{
+					QuoteID:         testQuoteID,
+					FromCurrency:    req.FromCurrency,
+					ToCurrency:      req.ToCurrency,
+					Rate:            "0.92",
+					SpreadBps:       50,
+					Amount:          req.Amount,
+					ConvertedAmount: 9154,
+					ExpiresAt:       expiresAt,
+				}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":{"quoteId":"d4e5f6a7-b8c9-0123-4567-890abcdef123","fromCurrency":"USD","toCurrency":"EUR","rate":"0.92","spreadBps":50,"amount":10000,"convertedAmount":9154,"expiresAt":"2024-03-01T12:00:30Z"}}`,
+		},
+		{
+			name:        "No Rate",
+			requestBody: `{"fromCurrency": "USD", "toCurrency": "JPY"}`,
+			mockCreateFXQuote: func(ctx context.Context, req requests.FXQuoteRequest) (*requests.FXQuoteResponse, error) {
+				return nil, requests.FXRateNotFoundError{FromCurrency: req.FromCurrency, ToCurrency: req.ToCurrency}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"rate not found: no rate from USD to JPY"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockFXHandler{
+				CreateFXQuoteFunc: tt.mockCreateFXQuote,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/fx/quotes", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleCreateFXQuote(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleExchange(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockExchange   func(ctx context.Context, req requests.ExchangeRequest) (*requests.ExchangeResponse, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Missing Quote",
+			requestBody:    `{"fromWalletId": "` + testFromWalletID + `", "toWalletId": "` + testToWalletID + `", "amount": 10000}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: invalid quote id format"}`,
+		},
+		{
+			name:           "Non-positive Amount",
+			requestBody:    `{"fromWalletId": "` + testFromWalletID + `", "toWalletId": "` + testToWalletID + `", "amount": 0, "quoteId": "` + testQuoteID + `"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: amount must be a positive integer"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"fromWalletId": "` + testFromWalletID + `", "toWalletId": "` + testToWalletID + `", "amount": 10000, "quoteId": "` + testQuoteID + `"}`,
+			mockExchange: func(ctx context.Context, req requests.ExchangeRequest) (*requests.ExchangeResponse, error) {
+				return &requests.ExchangeResponse{
+					ExchangeID:      testExchangeID,
+					QuoteID:         req.QuoteID,
+					FromWalletID:    req.FromWalletID,
+					ToWalletID:      req.ToWalletID,
+					Amount:          req.Amount,
+					ConvertedAmount: 9154,
+					FromCurrency:    "USD",
+					ToCurrency:      "EUR",
+					Rate:            "0.92",
+					SpreadBps:       50,
+					FromBalance:     0,
+					ToBalance:       9154,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"exchangeId":"e5f6a7b8-c9d0-1234-5678-90abcdef1234","quoteId":"d4e5f6a7-b8c9-0123-4567-890abcdef123","fromWalletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","toWalletId":"b2c3d4e5-f6a7-8901-2345-67890abcdef1","amount":10000,"convertedAmount":9154,"fromCurrency":"USD","toCurrency":"EUR","rate":"0.92","spreadBps":50,"fromBalance":0,"toBalance":9154}}`,
+		},
+		{
+			name:        "Expired Quote",
+			requestBody: `{"fromWalletId": "` + testFromWalletID + `", "toWalletId": "` + testToWalletID + `", "amount": 10000, "quoteId": "` + testQuoteID + `"}`,
+			mockExchange: func(ctx context.Context, req requests.ExchangeRequest) (*requests.ExchangeResponse, error) {
+				return nil, requests.QuoteNotUsableError{Reason: "it expired"}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"quote not usable: quote cannot be used: it expired"}`,
+		},
+		{
+			name:        "Converts To Nothing",
+			requestBody: `{"fromWalletId": "` + testFromWalletID + `", "toWalletId": "` + testToWalletID + `", "amount": 1, "quoteId": "` + testQuoteID + `"}`,
+			mockExchange: func(ctx context.Context, req requests.ExchangeRequest) (*requests.ExchangeResponse, error) {
+				return nil, models.ErrConvertedTooSmall
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status":"error","error":"amount out of range: amount converts to less than one minor unit"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockFXHandler{
+				ExchangeFunc: tt.mockExchange,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/exchanges", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleExchange(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
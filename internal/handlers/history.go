@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type OperationsLister interface {
+	ListOperations(ctx context.Context, filter requests.OperationsFilter) (*requests.OperationsPage, error)
+}
+
+type OperationByReferenceGetter interface {
+	GetOperationByReference(ctx context.Context, walletID, externalReference string) (*requests.OperationResponse, error)
+}
+
+func HandleListOperations(logger *zap.Logger, lister OperationsLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/wallets/{walletId}/operations"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+
+		filter, err := parseOperationsFilter(c, walletID)
+		if err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		pageChan := make(chan *requests.OperationsPage, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			page, err := lister.ListOperations(c.Request.Context(), filter)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			pageChan <- page
+		}()
+		select {
+		case page := <-pageChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.Int("operations", len(page.Operations)))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(page))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+	}
+}
+
+func HandleGetOperationByReference(logger *zap.Logger, getter OperationByReferenceGetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/wallets/{walletId}/operations/by-reference/{externalReference}"
+		walletID := c.Param("walletId")
+		externalReference := c.Param("externalReference")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID),
+			zap.String("externalReference", externalReference))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+
+		operationChan := make(chan *requests.OperationResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			operation, err := getter.GetOperationByReference(c.Request.Context(), walletID, externalReference)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			operationChan <- operation
+		}()
+		select {
+		case operation := <-operationChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("operationId", operation.ID))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(operation))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such an operation"), http.StatusNotFound, "operation not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+func parseOperationsFilter(c *gin.Context, walletID string) (requests.OperationsFilter, error) {
+	filter := requests.OperationsFilter{
+		WalletID: walletID,
+		Limit:    requests.DefaultOperationsLimit,
+	}
+
+	if operationType := c.Query("type"); operationType != "" {
+		switch operationType {
+		case "DEPOSIT", "WITHDRAW", "INTEREST":
+		default:
+			return filter, errors.New("type must be DEPOSIT, WITHDRAW or INTEREST")
+		}
+		filter.OperationType = operationType
+	}
+
+	var err error
+	if filter.MinAmount, err = parseAmountQuery(c, "minAmount"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = parseAmountQuery(c, "maxAmount"); err != nil {
+		return filter, err
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return filter, errors.New("minAmount must not be greater than maxAmount")
+	}
+
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return filter, err
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.New("from must be before to")
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		filter.Cursor, err = requests.DecodeOperationsCursor(cursor)
+		if err != nil {
+			return filter, err
+		}
+		if _, err := uuid.Parse(filter.Cursor.ID); err != nil {
+			return filter, errors.New("invalid cursor")
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit <= 0 || filter.Limit > requests.MaxOperationsLimit {
+			return filter, errors.New("limit must be an integer between 1 and " + strconv.Itoa(requests.MaxOperationsLimit))
+		}
+	}
+
+	return filter, nil
+}
+
+func parseAmountQuery(c *gin.Context, name string) (*models.Amount, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed < 0 {
+		return nil, errors.New(name + " must be a non-negative integer")
+	}
+	amount := models.Amount(parsed)
+	return &amount, nil
+}
+
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New(name + " must be an RFC3339 timestamp")
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockOperationsLister struct {
+	ListOperationsFunc func(ctx context.Context, filter requests.OperationsFilter) (*requests.OperationsPage, error)
+}
+
+func (m *mockOperationsLister) ListOperations(ctx context.Context, filter requests.OperationsFilter) (*requests.OperationsPage, error) {
+	if m.ListOperationsFunc != nil {
+		return m.ListOperationsFunc(ctx, filter)
+	}
+	return nil, nil
+}
+
+func TestHandleListOperations(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const walletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cursor := requests.OperationsCursor{Timestamp: timestamp, ID: "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11"}.Encode()
+
+	tests := []struct {
+		name               string
+		walletId           string
+		query              string
+		mockListOperations func(ctx context.Context, filter requests.OperationsFilter) (*requests.OperationsPage, error)
+		expectedStatus     int
+		expectedBody       string
+	}{
+		{
+			name:           "Invalid UUID",
+			walletId:       "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid walletId format: invalid wallet id format"}`,
+		},
+		{
+			name:           "Invalid Type",
+			walletId:       walletID,
+			query:          "type=TRANSFER",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: type must be DEPOSIT, WITHDRAW or INTEREST"}`,
+		},
+		{
+			name:           "Invalid Amount Range",
+			walletId:       walletID,
+			query:          "minAmount=100&maxAmount=10",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: minAmount must not be greater than maxAmount"}`,
+		},
+		{
+			name:           "Invalid Date",
+			walletId:       walletID,
+			query:          "from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: from must be an RFC3339 timestamp"}`,
+		},
+		{
+			name:           "Invalid Cursor",
+			walletId:       walletID,
+			query:          "cursor=not-a-cursor",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: invalid cursor"}`,
+		},
+		{
+			name:           "Invalid Limit",
+			walletId:       walletID,
+			query:          "limit=1000",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: limit must be an integer between 1 and 500"}`,
+		},
+		{
+			name:     "Success With Filters",
+			walletId: walletID,
+			query:    "type=DEPOSIT&minAmount=10&maxAmount=500&from=2024-05-01T00:00:00Z&to=2024-06-01T00:00:00Z&limit=1&cursor=" + cursor,
+			mockListOperations: func(ctx context.Context, filter requests.OperationsFilter) (*requests.OperationsPage, error) {
+				assert.Equal(t, walletID, filter.WalletID)
+				assert.Equal(t, "DEPOSIT", filter.OperationType)
+				assert.Equal(t, models.Amount(10), *filter.MinAmount)
+				assert.Equal(t, models.Amount(500), *filter.MaxAmount)
+				assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), filter.From.UTC())
+				assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), filter.To.UTC())
+				assert.Equal(t, "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11", filter.Cursor.ID)
+				assert.Equal(t, 1, filter.Limit)
+				return &requests.OperationsPage{
+					Operations: []requests.OperationResponse{
+						{
+							ID:            "5c4a3b2e-9d1f-4f5a-8a6b-2c3d4e5f6a7b",
+							WalletID:      walletID,
+							OperationType: "DEPOSIT",
+							Amount:        100,
+							Currency:      "USD",
+							Timestamp:     timestamp,
+						},
+					},
+					NextCursor: "next",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"operations":[{"id":"5c4a3b2e-9d1f-4f5a-8a6b-2c3d4e5f6a7b","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"DEPOSIT","amount":100,"currency":"USD","timestamp":"2024-05-01T12:00:00Z"}],"nextCursor":"next"}}`,
+		},
+		{
+			name:     "Default Limit",
+			walletId: walletID,
+			mockListOperations: func(ctx context.Context, filter requests.OperationsFilter) (*requests.OperationsPage, error) {
+				assert.Equal(t, requests.DefaultOperationsLimit, filter.Limit)
+				return &requests.OperationsPage{Operations: []requests.OperationResponse{}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"operations":[]}}`,
+		},
+		{
+			name:     "Wallet Not Found",
+			walletId: walletID,
+			mockListOperations: func(ctx context.Context, filter requests.OperationsFilter) (*requests.OperationsPage, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: no such a wallet"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockOperationsLister{
+				ListOperationsFunc: tt.mockListOperations,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/wallets/"+tt.walletId+"/operations?"+tt.query, nil)
+			c.Params = []gin.Param{{Key: "walletId", Value: tt.walletId}}
+
+			HandleListOperations(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+type mockOperationByReferenceGetter struct {
+	GetOperationByReferenceFunc func(ctx context.Context, walletID, externalReference string) (*requests.OperationResponse, error)
+}
+
+func (m *mockOperationByReferenceGetter) GetOperationByReference(ctx context.Context, walletID, externalReference string) (*requests.OperationResponse, error) {
+	if m.GetOperationByReferenceFunc != nil {
+		return m.GetOperationByReferenceFunc(ctx, walletID, externalReference)
+	}
+	return nil, nil
+}
+
+func TestHandleGetOperationByReference(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	description, reference := "May rent", "invoice-42"
+
+	tests := []struct {
+		name                  string
+		walletID              string
+		mockGetOperationByRef func(ctx context.Context, walletID, externalReference string) (*requests.OperationResponse, error)
+		expectedStatus        int
+		expectedBody          string
+	}{
+		{
+			name:           "Invalid WalletId",
+			walletID:       "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid walletId format: invalid wallet id format"}`,
+		},
+		{
+			name:     "Not Found",
+			walletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+			mockGetOperationByRef: func(ctx context.Context, walletID, externalReference string) (*requests.OperationResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"operation not found: no such an operation"}`,
+		},
+		{
+			name:     "Success",
+			walletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+			mockGetOperationByRef: func(ctx context.Context, walletID, externalReference string) (*requests.OperationResponse, error) {
+				assert.Equal(t, reference, externalReference)
+				return &requests.OperationResponse{
+					ID:                "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11",
+					WalletID:          walletID,
+					OperationType:     "WITHDRAW",
+					Amount:            999,
+					Currency:          "USD",
+					Timestamp:         time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+					Description:       &description,
+					ExternalReference: &reference,
+					Metadata:          []byte(`{"orderId":7}`),
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"status":"OK","data":{"id":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef",` +
+				`"operationType":"WITHDRAW","amount":999,"currency":"USD","timestamp":"2024-05-01T09:00:00Z","description":"May rent",` +
+				`"externalReference":"invoice-42","metadata":{"orderId":7}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGetter := &mockOperationByReferenceGetter{
+				GetOperationByReferenceFunc: tt.mockGetOperationByRef,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/wallets/"+tt.walletID+"/operations/by-reference/"+reference, nil)
+			c.Params = []gin.Param{{Key: "walletId", Value: tt.walletID}, {Key: "externalReference", Value: reference}}
+
+			HandleGetOperationByReference(logger, mockGetter)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type HoldHandler interface {
+	CreateHold(ctx context.Context, req requests.CreateHoldRequest) (*models.Hold, error)
+	GetHold(ctx context.Context, holdID string) (*models.Hold, error)
+	CaptureHold(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error)
+	VoidHold(ctx context.Context, holdID string) (*models.Hold, error)
+}
+
+func HandleCreateHold(logger *zap.Logger, handler HoldHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.CreateHoldRequest
+		const op = "api/v1/holds"
+
+		logger.Info("proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateCreateHoldRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		runHoldAction(c, logger, func(ctx context.Context) (*models.Hold, error) {
+			return handler.CreateHold(ctx, req)
+		})
+	}
+}
+
+func HandleGetHold(logger *zap.Logger, handler HoldHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/holds/{holdId}"
+		holdID := c.Param("holdId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("holdId", holdID))
+
+		if _, err := uuid.Parse(holdID); err != nil {
+			logError(c, logger, errors.New("invalid hold id format"), http.StatusBadRequest, "invalid holdId format")
+			return
+		}
+		runHoldAction(c, logger, func(ctx context.Context) (*models.Hold, error) {
+			return handler.GetHold(ctx, holdID)
+		})
+	}
+}
+
+func HandleCaptureHold(logger *zap.Logger, handler HoldHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.CaptureHoldRequest
+		const op = "api/v1/holds/{holdId}/capture"
+		holdID := c.Param("holdId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("holdId", holdID))
+
+		if _, err := uuid.Parse(holdID); err != nil {
+			logError(c, logger, errors.New("invalid hold id format"), http.StatusBadRequest, "invalid holdId format")
+			return
+		}
+		// The body is optional: an empty body captures the full hold.
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if req.Amount < 0 {
+			logError(c, logger, errors.New("amount must be a positive integer"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		runHoldAction(c, logger, func(ctx context.Context) (*models.Hold, error) {
+			return handler.CaptureHold(ctx, holdID, req.Amount)
+		})
+	}
+}
+
+func HandleVoidHold(logger *zap.Logger, handler HoldHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/holds/{holdId}/void"
+		holdID := c.Param("holdId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("holdId", holdID))
+
+		if _, err := uuid.Parse(holdID); err != nil {
+			logError(c, logger, errors.New("invalid hold id format"), http.StatusBadRequest, "invalid holdId format")
+			return
+		}
+		runHoldAction(c, logger, func(ctx context.Context) (*models.Hold, error) {
+			return handler.VoidHold(ctx, holdID)
+		})
+	}
+}
+
+func runHoldAction(c *gin.Context, logger *zap.Logger, action func(ctx context.Context) (*models.Hold, error)) {
+	holdChan := make(chan *models.Hold, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		hold, err := action(c.Request.Context())
+		if err != nil {
+			errChan <- err
+			return
+		}
+		holdChan <- hold
+	}()
+	select {
+	case hold := <-holdChan:
+		logRequest(c, logger, "request procceeded successfully", zap.String("holdId", hold.ID), zap.String("status", hold.Status))
+		c.JSON(http.StatusOK, requests.WalletOperationResponseOK(holdResponse(hold)))
+	case err := <-errChan:
+		if errors.Is(err, sql.ErrNoRows) {
+			logError(c, logger, err, http.StatusNotFound, "not found")
+			return
+		}
+		var insufficientFundsErr requests.InsufficientFundsError
+		if errors.As(err, &insufficientFundsErr) {
+			logError(c, logger, err, http.StatusForbidden, "balance cant become negative")
+			return
+		}
+		var currencyMismatchErr requests.CurrencyMismatchError
+		if errors.As(err, &currencyMismatchErr) {
+			logError(c, logger, err, http.StatusUnprocessableEntity, "currency mismatch")
+			return
+		}
+		var walletNotActiveErr requests.WalletNotActiveError
+		if errors.As(err, &walletNotActiveErr) {
+			logError(c, logger, err, http.StatusConflict, "wallet not active")
+			return
+		}
+		var holdNotActiveErr requests.HoldNotActiveError
+		if errors.As(err, &holdNotActiveErr) {
+			logError(c, logger, err, http.StatusConflict, "hold cannot be changed")
+			return
+		}
+		var captureExceedsHoldErr requests.CaptureExceedsHoldError
+		if errors.As(err, &captureExceedsHoldErr) {
+			logError(c, logger, err, http.StatusUnprocessableEntity, "bad capture amount")
+			return
+		}
+		logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func holdResponse(hold *models.Hold) requests.HoldResponse {
+	return requests.HoldResponse{
+		ID:             hold.ID,
+		WalletID:       hold.WalletID,
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Currency:       hold.Currency,
+		Status:         hold.Status,
+		OperationID:    hold.OperationID,
+		ExpiresAt:      hold.ExpiresAt,
+		CreatedAt:      hold.CreatedAt,
+	}
+}
+
+func validateCreateHoldRequest(req requests.CreateHoldRequest) error {
+	if req.WalletID == "" {
+		return errors.New("empty wallet id")
+	}
+	if _, err := uuid.Parse(req.WalletID); err != nil {
+		return errors.New("invalid wallet id format")
+	}
+	if req.Amount <= 0 {
+		return errors.New("amount must be a positive integer")
+	}
+	if req.Currency != "" {
+		if err := validateCurrency(req.Currency); err != nil {
+			return err
+		}
+	}
+	if req.TTLSeconds < 0 {
+		return errors.New("ttlSeconds must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockHoldHandler struct {
+	CreateHoldFunc  func(ctx context.Context, req requests.CreateHoldRequest) (*models.Hold, error)
+	GetHoldFunc     func(ctx context.Context, holdID string) (*models.Hold, error)
+	CaptureHoldFunc func(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error)
+	VoidHoldFunc    func(ctx context.Context, holdID string) (*models.Hold, error)
+}
+
+func (m *mockHoldHandler) CreateHold(ctx context.Context, req requests.CreateHoldRequest) (*models.Hold, error) {
+	if m.CreateHoldFunc != nil {
+		return m.CreateHoldFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *mockHoldHandler) GetHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	if m.GetHoldFunc != nil {
+		return m.GetHoldFunc(ctx, holdID)
+	}
+	return nil, nil
+}
+
+func (m *mockHoldHandler) CaptureHold(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error) {
+	if m.CaptureHoldFunc != nil {
+		return m.CaptureHoldFunc(ctx, holdID, amount)
+	}
+	return nil, nil
+}
+
+func (m *mockHoldHandler) VoidHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	if m.VoidHoldFunc != nil {
+		return m.VoidHoldFunc(ctx, holdID)
+	}
+	return nil, nil
+}
+
+const testHoldID = "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11"
+
+func testHold(status string, captured models.Amount) *models.Hold {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return &models.Hold{
+		ID:             testHoldID,
+		WalletID:       "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+		Amount:         300,
+		CapturedAmount: captured,
+		Currency:       "USD",
+		Status:         status,
+		ExpiresAt:      created.Add(time.Hour),
+		CreatedAt:      created,
+		UpdatedAt:      created,
+	}
+}
+
+func TestHandleCreateHold(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockCreateHold func(ctx context.Context, req requests.CreateHoldRequest) (*models.Hold, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Invalid WalletId Format",
+			requestBody:    `{"walletId": "invalid-uuid", "amount": 300}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: invalid wallet id format"}`,
+		},
+		{
+			name:           "Invalid amount",
+			requestBody:    `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "amount": 0}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: amount must be a positive integer"}`,
+		},
+		{
+			name:           "Invalid TTL",
+			requestBody:    `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "amount": 300, "ttlSeconds": -1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: ttlSeconds must not be negative"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "amount": 300, "ttlSeconds": 3600}`,
+			mockCreateHold: func(ctx context.Context, req requests.CreateHoldRequest) (*models.Hold, error) {
+				assert.Equal(t, 3600, req.TTLSeconds)
+				return testHold(models.HoldActive, 0), nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"id":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","amount":300,"capturedAmount":0,"currency":"USD","status":"ACTIVE","expiresAt":"2024-05-01T13:00:00Z","createdAt":"2024-05-01T12:00:00Z"}}`,
+		},
+		{
+			name:        "Insufficient Funds Error",
+			requestBody: `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "amount": 300}`,
+			mockCreateHold: func(ctx context.Context, req requests.CreateHoldRequest) (*models.Hold, error) {
+				return nil, requests.InsufficientFundsError{}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"status":"error","error":"balance cant become negative: insufficient funds: 0 left to spend"}`,
+		},
+		{
+			name:        "Wallet Not Found",
+			requestBody: `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "amount": 300}`,
+			mockCreateHold: func(ctx context.Context, req requests.CreateHoldRequest) (*models.Hold, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"not found: sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockHoldHandler{
+				CreateHoldFunc: tt.mockCreateHold,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/holds", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleCreateHold(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleCaptureHold(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name            string
+		holdId          string
+		requestBody     string
+		mockCaptureHold func(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error)
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:           "Invalid HoldId Format",
+			holdId:         "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid holdId format: invalid hold id format"}`,
+		},
+		{
+			name:        "Full Capture With Empty Body",
+			holdId:      testHoldID,
+			requestBody: "",
+			mockCaptureHold: func(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error) {
+				assert.Zero(t, amount)
+				hold := testHold(models.HoldCaptured, 300)
+				operationID := "5c4a3b2e-9d1f-4f5a-8a6b-2c3d4e5f6a7b"
+				hold.OperationID = &operationID
+				return hold, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"id":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","amount":300,"capturedAmount":300,"currency":"USD","status":"CAPTURED","operationId":"5c4a3b2e-9d1f-4f5a-8a6b-2c3d4e5f6a7b","expiresAt":"2024-05-01T13:00:00Z","createdAt":"2024-05-01T12:00:00Z"}}`,
+		},
+		{
+			name:        "Partial Capture",
+			holdId:      testHoldID,
+			requestBody: `{"amount": 120}`,
+			mockCaptureHold: func(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error) {
+				assert.Equal(t, models.Amount(120), amount)
+				return testHold(models.HoldCaptured, 120), nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"id":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","amount":300,"capturedAmount":120,"currency":"USD","status":"CAPTURED","expiresAt":"2024-05-01T13:00:00Z","createdAt":"2024-05-01T12:00:00Z"}}`,
+		},
+		{
+			name:        "Capture Exceeds Hold",
+			holdId:      testHoldID,
+			requestBody: `{"amount": 301}`,
+			mockCaptureHold: func(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error) {
+				return nil, requests.CaptureExceedsHoldError{Held: 300}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status":"error","error":"bad capture amount: capture exceeds held amount of 300"}`,
+		},
+		{
+			name:        "Hold Expired",
+			holdId:      testHoldID,
+			requestBody: `{}`,
+			mockCaptureHold: func(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error) {
+				return nil, requests.HoldNotActiveError{Status: models.HoldExpired}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"hold cannot be changed: hold is EXPIRED"}`,
+		},
+		{
+			name:        "Internal Server Error",
+			holdId:      testHoldID,
+			requestBody: `{}`,
+			mockCaptureHold: func(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error) {
+				return nil, errors.New("some other error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status":"error","error":"internal server error: some other error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockHoldHandler{
+				CaptureHoldFunc: tt.mockCaptureHold,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/holds/"+tt.holdId+"/capture", bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "holdId", Value: tt.holdId}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleCaptureHold(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleVoidHold(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		mockVoidHold   func(ctx context.Context, holdID string) (*models.Hold, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockVoidHold: func(ctx context.Context, holdID string) (*models.Hold, error) {
+				return testHold(models.HoldVoided, 0), nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"id":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","amount":300,"capturedAmount":0,"currency":"USD","status":"VOIDED","expiresAt":"2024-05-01T13:00:00Z","createdAt":"2024-05-01T12:00:00Z"}}`,
+		},
+		{
+			name: "Already Captured",
+			mockVoidHold: func(ctx context.Context, holdID string) (*models.Hold, error) {
+				return nil, requests.HoldNotActiveError{Status: models.HoldCaptured}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"hold cannot be changed: hold is CAPTURED"}`,
+		},
+		{
+			name: "Hold Not Found",
+			mockVoidHold: func(ctx context.Context, holdID string) (*models.Hold, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"not found: sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockHoldHandler{
+				VoidHoldFunc: tt.mockVoidHold,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/holds/"+testHoldID+"/void", nil)
+			c.Params = []gin.Param{{Key: "holdId", Value: testHoldID}}
+
+			HandleVoidHold(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type InterestRateSetter interface {
+	SetInterestRate(ctx context.Context, walletID string, rateBps int64) (*requests.WalletBalanceResponse, error)
+}
+
+type InterestRunner interface {
+	RunInterest(ctx context.Context, day time.Time) (*requests.InterestRunResponse, error)
+}
+
+type InterestAccrualLister interface {
+	ListInterestAccruals(ctx context.Context, walletID string, from, to time.Time) ([]models.InterestAccrual, error)
+}
+
+func HandleSetInterestRate(logger *zap.Logger, setter InterestRateSetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.SetInterestRateRequest
+		const op = "api/v1/admin/wallets/{walletId}/interest-rate"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if req.AnnualRateBps == nil || *req.AnnualRateBps < 0 || *req.AnnualRateBps > models.MaxRateBps {
+			logError(c, logger, errors.New("annualRateBps must be between 0 and 10000"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		walletChan := make(chan *requests.WalletBalanceResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			wallet, err := setter.SetInterestRate(c.Request.Context(), walletID, *req.AnnualRateBps)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			walletChan <- wallet
+		}()
+		select {
+		case wallet := <-walletChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.Int64("annualRateBps", wallet.InterestRate))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(wallet))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+// HandleRunInterest accrues a finished day and pays out the months finished by it, for catching up on days
+// the job missed. Days already run are skipped, so it is safe to repeat.
+func HandleRunInterest(logger *zap.Logger, runner InterestRunner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.RunInterestRequest
+		const op = "api/v1/admin/interest/run"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		day, err := time.Parse(time.DateOnly, req.Day)
+		if err != nil {
+			logError(c, logger, errors.New("day must be a YYYY-MM-DD date"), http.StatusBadRequest, "bad request data")
+			return
+		}
+		if !day.Before(time.Now().UTC().AddDate(0, 0, -1)) {
+			logError(c, logger, errors.New("day must be over"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		runChan := make(chan *requests.InterestRunResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			run, err := runner.RunInterest(c.Request.Context(), day)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			runChan <- run
+		}()
+		select {
+		case run := <-runChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("day", run.Day), zap.Int("accrued", run.Accrued), zap.Int("paid", run.Paid))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(run))
+		case err := <-errChan:
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+// HandleListInterestAccruals lists a wallet's daily accruals for one month, the current one by default.
+func HandleListInterestAccruals(logger *zap.Logger, lister InterestAccrualLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/wallets/{walletId}/interest-accruals"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		now := time.Now().UTC()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if month := c.Query("month"); month != "" {
+			parsed, err := time.Parse("2006-01", month)
+			if err != nil {
+				logError(c, logger, errors.New("month must be a YYYY-MM month"), http.StatusBadRequest, "bad request data")
+				return
+			}
+			from = parsed
+		}
+
+		accrualsChan := make(chan []models.InterestAccrual, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			accruals, err := lister.ListInterestAccruals(c.Request.Context(), walletID, from, from.AddDate(0, 1, 0))
+			if err != nil {
+				errChan <- err
+				return
+			}
+			accrualsChan <- accruals
+		}()
+		select {
+		case accruals := <-accrualsChan:
+			resp := make([]requests.InterestAccrualResponse, len(accruals))
+			for i, accrual := range accruals {
+				resp[i] = requests.InterestAccrualResponse{
+					Day:           accrual.Day.Format(time.DateOnly),
+					Balance:       accrual.Balance,
+					RateBps:       accrual.RateBps,
+					AccruedMicros: accrual.AccruedMicros,
+					PaidAt:        accrual.PaidAt,
+					OperationID:   accrual.OperationID,
+				}
+			}
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.Int("accruals", len(resp)))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(resp))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
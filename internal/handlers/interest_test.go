@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockInterestHandler struct {
+	SetInterestRateFunc      func(ctx context.Context, walletID string, rateBps int64) (*requests.WalletBalanceResponse, error)
+	RunInterestFunc          func(ctx context.Context, day time.Time) (*requests.InterestRunResponse, error)
+	ListInterestAccrualsFunc func(ctx context.Context, walletID string, from, to time.Time) ([]models.InterestAccrual, error)
+}
+
+func (m *mockInterestHandler) SetInterestRate(ctx context.Context, walletID string, rateBps int64) (*requests.WalletBalanceResponse, error) {
+	if m.SetInterestRateFunc != nil {
+		return m.SetInterestRateFunc(ctx, walletID, rateBps)
+	}
+	return nil, nil
+}
+
+func (m *mockInterestHandler) RunInterest(ctx context.Context, day time.Time) (*requests.InterestRunResponse, error) {
+	if m.RunInterestFunc != nil {
+		return m.RunInterestFunc(ctx, day)
+	}
+	return nil, nil
+}
+
+func (m *mockInterestHandler) ListInterestAccruals(ctx context.Context, walletID string, from, to time.Time) ([]models.InterestAccrual, error) {
+	if m.ListInterestAccrualsFunc != nil {
+		return m.ListInterestAccrualsFunc(ctx, walletID, from, to)
+	}
+	return nil, nil
+}
+
+func TestHandleSetInterestRate(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const walletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+
+	tests := []struct {
+		name                string
+		walletId            string
+		requestBody         string
+		mockSetInterestRate func(ctx context.Context, walletID string, rateBps int64) (*requests.WalletBalanceResponse, error)
+		expectedStatus      int
+		expectedBody        string
+	}{
+		{
+			name:           "Invalid UUID",
+			walletId:       "invalid-uuid",
+			requestBody:    `{"annualRateBps": 250}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid walletId format: invalid wallet id format"}`,
+		},
+		{
+			name:           "Missing Rate",
+			walletId:       walletID,
+			requestBody:    `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: annualRateBps must be between 0 and 10000"}`,
+		},
+		{
+			name:           "Rate Above 100%",
+			walletId:       walletID,
+			requestBody:    `{"annualRateBps": 10001}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: annualRateBps must be between 0 and 10000"}`,
+		},
+		{
+			name:        "Success",
+			walletId:    walletID,
+			requestBody: `{"annualRateBps": 250}`,
+			mockSetInterestRate: func(ctx context.Context, id string, rateBps int64) (*requests.WalletBalanceResponse, error) {
+				return &requests.WalletBalanceResponse{
+					WalletID:     id,
+					Balance:      100,
+					Available:    100,
+					Currency:     "USD",
+					Status:       "ACTIVE",
+					InterestRate: rateBps,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":100,"available":100,"currency":"USD","status":"ACTIVE","annualRateBps":250}}`,
+		},
+		{
+			name:        "Wallet Not Found",
+			walletId:    walletID,
+			requestBody: `{"annualRateBps": 0}`,
+			mockSetInterestRate: func(ctx context.Context, id string, rateBps int64) (*requests.WalletBalanceResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: no such a wallet"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockInterestHandler{
+				SetInterestRateFunc: tt.mockSetInterestRate,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/admin/wallets/%s/interest-rate", tt.walletId), bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "walletId", Value: tt.walletId}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleSetInterestRate(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleRunInterest(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	today := time.Now().UTC().Format(time.DateOnly)
+
+	tests := []struct {
+		name            string
+		requestBody     string
+		mockRunInterest func(ctx context.Context, day time.Time) (*requests.InterestRunResponse, error)
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:           "Invalid Day",
+			requestBody:    `{"day": "01/03/2024"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: day must be a YYYY-MM-DD date"}`,
+		},
+		{
+			name:           "Day Not Over",
+			requestBody:    fmt.Sprintf(`{"day": %q}`, today),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: day must be over"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"day": "2024-03-31"}`,
+			mockRunInterest: func(ctx context.Context, day time.Time) (*requests.InterestRunResponse, error) {
+				return &requests.InterestRunResponse{Day: day.Format(time.DateOnly), Accrued: 2, Paid: 2}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"day":"2024-03-31","accrued":2,"paid":2}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockInterestHandler{
+				RunInterestFunc: tt.mockRunInterest,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/admin/interest/run", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleRunInterest(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleListInterestAccruals(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const walletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+	paidAt := time.Date(2024, 3, 1, 0, 5, 0, 0, time.UTC)
+	operationID := "b2c3d4e5-f6a7-8901-2345-67890abcdef1"
+
+	tests := []struct {
+		name                     string
+		walletId                 string
+		query                    string
+		mockListInterestAccruals func(ctx context.Context, walletID string, from, to time.Time) ([]models.InterestAccrual, error)
+		expectedStatus           int
+		expectedBody             string
+	}{
+		{
+			name:           "Invalid Month",
+			walletId:       walletID,
+			query:          "?month=2024-3",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: month must be a YYYY-MM month"}`,
+		},
+		{
+			name:     "Success",
+			walletId: walletID,
+			query:    "?month=2024-02",
+			mockListInterestAccruals: func(ctx context.Context, id string, from, to time.Time) ([]models.InterestAccrual, error) {
+				if !from.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+					return nil, fmt.Errorf("unexpected range %s - %s", from, to)
+				}
+				return []models.InterestAccrual{{
+					WalletID:      id,
+					Day:           time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+					Balance:       100000,
+					RateBps:       365,
+					AccruedMicros: 10000000,
+					PaidAt:        &paidAt,
+					OperationID:   &operationID,
+				}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":[{"day":"2024-02-29","balance":100000,"rateBps":365,"accruedMicros":10000000,"paidAt":"2024-03-01T00:05:00Z","operationId":"b2c3d4e5-f6a7-8901-2345-67890abcdef1"}]}`,
+		},
+		{
+			name:     "Wallet Not Found",
+			walletId: walletID,
+			mockListInterestAccruals: func(ctx context.Context, id string, from, to time.Time) ([]models.InterestAccrual, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: no such a wallet"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockInterestHandler{
+				ListInterestAccrualsFunc: tt.mockListInterestAccruals,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s/interest-accruals%s", tt.walletId, tt.query), nil)
+			c.Params = []gin.Param{{Key: "walletId", Value: tt.walletId}}
+
+			HandleListInterestAccruals(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
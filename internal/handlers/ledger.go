@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type LedgerVerifier interface {
+	VerifyLedger(ctx context.Context) (*requests.LedgerReport, error)
+}
+
+func HandleVerifyLedger(logger *zap.Logger, verifier LedgerVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/admin/ledger/verify"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		reportChan := make(chan *requests.LedgerReport, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			report, err := verifier.VerifyLedger(c.Request.Context())
+			if err != nil {
+				errChan <- err
+				return
+			}
+			reportChan <- report
+		}()
+		select {
+		case report := <-reportChan:
+			if !report.Balanced {
+				logger.Error("ledger is not balanced", zap.Any("totals", report.Totals), zap.Int("mismatches", len(report.Mismatches)))
+			}
+			logRequest(c, logger, "request procceeded successfully", zap.Bool("balanced", report.Balanced))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(report))
+		case err := <-errChan:
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+	}
+}
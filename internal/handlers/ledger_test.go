@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockLedgerVerifier struct {
+	VerifyLedgerFunc func(ctx context.Context) (*requests.LedgerReport, error)
+}
+
+func (m *mockLedgerVerifier) VerifyLedger(ctx context.Context) (*requests.LedgerReport, error) {
+	if m.VerifyLedgerFunc != nil {
+		return m.VerifyLedgerFunc(ctx)
+	}
+	return nil, nil
+}
+
+func TestHandleVerifyLedger(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name             string
+		mockVerifyLedger func(ctx context.Context) (*requests.LedgerReport, error)
+		expectedStatus   int
+		expectedBody     string
+	}{
+		{
+			name: "Balanced",
+			mockVerifyLedger: func(ctx context.Context) (*requests.LedgerReport, error) {
+				return &requests.LedgerReport{
+					Balanced:   true,
+					Totals:     map[string]models.Amount{"USD": 0},
+					Mismatches: []requests.LedgerMismatch{},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"balanced":true,"totals":{"USD":0},"mismatches":[]}}`,
+		},
+		{
+			name: "Mismatch",
+			mockVerifyLedger: func(ctx context.Context) (*requests.LedgerReport, error) {
+				return &requests.LedgerReport{
+					Balanced: false,
+					Totals:   map[string]models.Amount{"USD": 0},
+					Mismatches: []requests.LedgerMismatch{
+						{WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef", Balance: 100, LedgerBalance: 90},
+					},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"balanced":false,"totals":{"USD":0},"mismatches":[{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":100,"ledgerBalance":90}]}}`,
+		},
+		{
+			name: "Internal Server Error",
+			mockVerifyLedger: func(ctx context.Context) (*requests.LedgerReport, error) {
+				return nil, errors.New("some other error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status":"error","error":"internal server error: some other error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockLedgerVerifier{
+				VerifyLedgerFunc: tt.mockVerifyLedger,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/admin/ledger/verify", nil)
+
+			HandleVerifyLedger(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
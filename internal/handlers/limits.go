@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type WithdrawalLimitsHandler interface {
+	GetWithdrawalLimits(ctx context.Context, walletID string) (*requests.WithdrawalLimitsResponse, error)
+	SetWithdrawalLimits(ctx context.Context, walletID string, limits requests.WithdrawalLimits) (*requests.WithdrawalLimitsResponse, error)
+}
+
+func HandleGetWithdrawalLimits(logger *zap.Logger, handler WithdrawalLimitsHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/admin/wallets/{walletId}/limits"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		runLimitsAction(c, logger, func(ctx context.Context) (*requests.WithdrawalLimitsResponse, error) {
+			return handler.GetWithdrawalLimits(ctx, walletID)
+		})
+	}
+}
+
+func HandleSetWithdrawalLimits(logger *zap.Logger, handler WithdrawalLimitsHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.WithdrawalLimits
+		const op = "api/v1/admin/wallets/{walletId}/limits"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		for _, limit := range []*models.Amount{req.PerOperation, req.Daily, req.Monthly} {
+			if limit != nil && *limit <= 0 {
+				logError(c, logger, errors.New("limits must be positive integers"), http.StatusBadRequest, "bad request data")
+				return
+			}
+		}
+		if req.HourlyCount != nil && *req.HourlyCount <= 0 {
+			logError(c, logger, errors.New("limits must be positive integers"), http.StatusBadRequest, "bad request data")
+			return
+		}
+		runLimitsAction(c, logger, func(ctx context.Context) (*requests.WithdrawalLimitsResponse, error) {
+			return handler.SetWithdrawalLimits(ctx, walletID, req)
+		})
+	}
+}
+
+// runLimitsAction runs a withdrawal limits storage call and writes its result or error.
+func runLimitsAction(c *gin.Context, logger *zap.Logger, action func(ctx context.Context) (*requests.WithdrawalLimitsResponse, error)) {
+	limitsChan := make(chan *requests.WithdrawalLimitsResponse, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		limits, err := action(c.Request.Context())
+		if err != nil {
+			errChan <- err
+			return
+		}
+		limitsChan <- limits
+	}()
+	select {
+	case limits := <-limitsChan:
+		logRequest(c, logger, "request procceeded successfully", zap.String("walletId", limits.WalletID))
+		c.JSON(http.StatusOK, requests.WalletOperationResponseOK(limits))
+	case err := <-errChan:
+		if errors.Is(err, sql.ErrNoRows) {
+			logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+			return
+		}
+		logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+	}
+}
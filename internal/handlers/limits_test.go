@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockWithdrawalLimitsHandler struct {
+	GetWithdrawalLimitsFunc func(ctx context.Context, walletID string) (*requests.WithdrawalLimitsResponse, error)
+	SetWithdrawalLimitsFunc func(ctx context.Context, walletID string, limits requests.WithdrawalLimits) (*requests.WithdrawalLimitsResponse, error)
+}
+
+func (m *mockWithdrawalLimitsHandler) GetWithdrawalLimits(ctx context.Context, walletID string) (*requests.WithdrawalLimitsResponse, error) {
+	if m.GetWithdrawalLimitsFunc != nil {
+		return m.GetWithdrawalLimitsFunc(ctx, walletID)
+	}
+	return nil, nil
+}
+
+func (m *mockWithdrawalLimitsHandler) SetWithdrawalLimits(ctx context.Context, walletID string, limits requests.WithdrawalLimits) (*requests.WithdrawalLimitsResponse, error) {
+	if m.SetWithdrawalLimitsFunc != nil {
+		return m.SetWithdrawalLimitsFunc(ctx, walletID, limits)
+	}
+	return nil, nil
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func amountPtr(v models.Amount) *models.Amount {
+	return &v
+}
+
+func TestHandleSetWithdrawalLimits(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const walletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+
+	tests := []struct {
+		name                    string
+		walletId                string
+		requestBody             string
+		mockSetWithdrawalLimits func(ctx context.Context, walletID string, limits requests.WithdrawalLimits) (*requests.WithdrawalLimitsResponse, error)
+		expectedStatus          int
+		expectedBody            string
+	}{
+		{
+			name:           "Invalid UUID",
+			walletId:       "invalid-uuid",
+			requestBody:    `{"daily": 1000}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid walletId format: invalid wallet id format"}`,
+		},
+		{
+			name:           "Non Positive Limit",
+			walletId:       walletID,
+			requestBody:    `{"daily": 1000, "hourlyCount": 0}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: limits must be positive integers"}`,
+		},
+		{
+			name:        "Success",
+			walletId:    walletID,
+			requestBody: `{"daily": 1000}`,
+			mockSetWithdrawalLimits: func(ctx context.Context, id string, limits requests.WithdrawalLimits) (*requests.WithdrawalLimitsResponse, error) {
+				assert.Nil(t, limits.PerOperation)
+				return &requests.WithdrawalLimitsResponse{
+					WalletID:  id,
+					Wallet:    limits,
+					Effective: requests.WithdrawalLimits{PerOperation: amountPtr(300), Daily: limits.Daily},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","wallet":{"daily":1000},"effective":{"perOperation":300,"daily":1000}}}`,
+		},
+		{
+			name:        "Wallet Not Found",
+			walletId:    walletID,
+			requestBody: `{}`,
+			mockSetWithdrawalLimits: func(ctx context.Context, id string, limits requests.WithdrawalLimits) (*requests.WithdrawalLimitsResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: no such a wallet"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWithdrawalLimitsHandler{
+				SetWithdrawalLimitsFunc: tt.mockSetWithdrawalLimits,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/admin/wallets/%s/limits", tt.walletId), bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "walletId", Value: tt.walletId}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleSetWithdrawalLimits(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleGetWithdrawalLimits(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	mockHandler := &mockWithdrawalLimitsHandler{
+		GetWithdrawalLimitsFunc: func(ctx context.Context, id string) (*requests.WithdrawalLimitsResponse, error) {
+			return &requests.WithdrawalLimitsResponse{
+				WalletID:  id,
+				Effective: requests.WithdrawalLimits{HourlyCount: intPtr(10)},
+			}, nil
+		},
+	}
+	recorder := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/admin/wallets/a1b2c3d4-e5f6-7890-1234-567890abcdef/limits", nil)
+	c.Params = []gin.Param{{Key: "walletId", Value: "a1b2c3d4-e5f6-7890-1234-567890abcdef"}}
+
+	HandleGetWithdrawalLimits(logger, mockHandler)(c)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","wallet":{},"effective":{"hourlyCount":10}}}`, recorder.Body.String())
+}
@@ -1,25 +1,44 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 
 	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"golang.org/x/text/currency"
 )
 
 type WalletOperationHandler interface {
-	ProcessOperation(ctx context.Context, req requests.WalletOperationRequest) error
+	ProcessOperation(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error)
 	GetWalletBalance(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
+	ProcessOperationIdempotent(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error)
 }
 
+// IdempotencyKeyHeader lets clients retry POST /api/v1/wallet without applying the operation twice.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const maxIdempotencyKeyLength = 255
+
+const (
+	maxDescriptionLength       = 500
+	maxExternalReferenceLength = 255
+	maxMetadataSize            = 4096
+)
+
 func HandleWalletOperation(logger *zap.Logger, handler WalletOperationHandler) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req requests.WalletOperationRequest
@@ -51,13 +70,19 @@ func HandleWalletOperation(logger *zap.Logger, handler WalletOperationHandler) g
 			logError(c, logger, validationError, http.StatusBadRequest, "bad request data")
 			return
 		}
+
+		if idempotencyKey := c.GetHeader(IdempotencyKeyHeader); idempotencyKey != "" {
+			handleIdempotentOperation(c, logger, handler, req, idempotencyKey, reqBody)
+			return
+		}
+
 		var wg sync.WaitGroup
 		errChan := make(chan error, 1)
-		balanceChan := make(chan *requests.WalletBalanceResponse, 1)
+		resultChan := make(chan requests.WalletOperationResult, 1)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err = handler.ProcessOperation(c.Request.Context(), req)
+			operation, err := handler.ProcessOperation(c.Request.Context(), req)
 			if err != nil {
 				errChan <- err
 				return
@@ -67,37 +92,103 @@ func HandleWalletOperation(logger *zap.Logger, handler WalletOperationHandler) g
 				errChan <- err
 				return
 			}
-			balanceChan <- walletBalance
+			resultChan <- requests.WalletOperationResult{
+				WalletID:      req.WalletID,
+				OperationType: req.OperationType,
+				Balance:       walletBalance.Balance,
+				Currency:      walletBalance.Currency,
+				Fee:           operation.Fee,
+			}
 		}()
 		go func() {
 			wg.Wait()
 			close(errChan)
-			close(balanceChan)
+			close(resultChan)
 		}()
 		select {
-		case balance := <-balanceChan:
+		case result := <-resultChan:
 			logger.Info("request procceeded successfully", zap.String("request_body", string(reqBody)))
-			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(map[string]interface{}{
-				"walletId":      req.WalletID,
-				"operationType": req.OperationType,
-				"balance":       balance.Balance,
-			}))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(result))
 		case err := <-errChan:
-			if errors.Is(err, sql.ErrNoRows) {
-				logError(c, logger, err, http.StatusNotFound, "wallet not found")
-				return
-			}
-			var insufficientFundsErr requests.InsufficientFundsError
-			if errors.As(err, &insufficientFundsErr) {
-				logError(c, logger, err, http.StatusForbidden, "balance cant become negative")
-				return
-			}
-			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			handleOperationError(c, logger, err)
+		}
+	}
+}
+
+func handleIdempotentOperation(c *gin.Context, logger *zap.Logger, handler WalletOperationHandler, req requests.WalletOperationRequest, key string, reqBody []byte) {
+	if len(key) > maxIdempotencyKeyLength {
+		logError(c, logger, errors.New("idempotency key is too long"), http.StatusBadRequest, "bad request data")
+		return
+	}
+	sum := sha256.Sum256(reqBody)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	respChan := make(chan *requests.StoredResponse, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		resp, err := handler.ProcessOperationIdempotent(c.Request.Context(), req, key, fingerprint)
+		if err != nil {
+			errChan <- err
 			return
 		}
+		respChan <- resp
+	}()
+	select {
+	case resp := <-respChan:
+		logger.Info("request procceeded successfully",
+			zap.String("request_body", string(reqBody)),
+			zap.String("idempotency_key", key),
+			zap.Bool("replayed", resp.Replayed),
+		)
+		if resp.Replayed {
+			c.Header("Idempotent-Replayed", "true")
+		}
+		c.Data(resp.Status, "application/json; charset=utf-8", resp.Body)
+	case err := <-errChan:
+		handleOperationError(c, logger, err)
 	}
 }
 
+func handleOperationError(c *gin.Context, logger *zap.Logger, err error) {
+	status, message := operationErrorStatus(err)
+	logError(c, logger, err, status, message)
+}
+
+// operationErrorStatus maps an error from processing an operation to its response status and message.
+func operationErrorStatus(err error) (int, string) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound, "wallet not found"
+	}
+	var insufficientFundsErr requests.InsufficientFundsError
+	if errors.As(err, &insufficientFundsErr) {
+		return http.StatusForbidden, "balance cant become negative"
+	}
+	var withdrawalLimitErr requests.WithdrawalLimitError
+	if errors.As(err, &withdrawalLimitErr) {
+		return http.StatusForbidden, "withdrawal limit exceeded"
+	}
+	var currencyMismatchErr requests.CurrencyMismatchError
+	if errors.As(err, &currencyMismatchErr) {
+		return http.StatusUnprocessableEntity, "currency mismatch"
+	}
+	var walletNotActiveErr requests.WalletNotActiveError
+	if errors.As(err, &walletNotActiveErr) {
+		return http.StatusConflict, "wallet not active"
+	}
+	if errors.Is(err, models.ErrAmountOverflow) {
+		return http.StatusUnprocessableEntity, "amount out of range"
+	}
+	var externalRefErr requests.ExternalReferenceConflictError
+	if errors.As(err, &externalRefErr) {
+		return http.StatusConflict, "duplicate external reference"
+	}
+	var idempotencyConflictErr requests.IdempotencyKeyConflictError
+	if errors.As(err, &idempotencyConflictErr) {
+		return http.StatusConflict, "idempotency key conflict"
+	}
+	return http.StatusInternalServerError, "internal server error"
+}
+
 func validateRequest(req requests.WalletOperationRequest) error {
 	if req.WalletID == "" {
 		return errors.New("empty wallet id")
@@ -117,6 +208,40 @@ func validateRequest(req requests.WalletOperationRequest) error {
 	if req.Amount <= 0 {
 		return errors.New("amount must be a positive integer")
 	}
+	if req.Currency != "" {
+		if err := validateCurrency(req.Currency); err != nil {
+			return err
+		}
+	}
+	if len(req.Description) > maxDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", maxDescriptionLength)
+	}
+	if len(req.ExternalReference) > maxExternalReferenceLength {
+		return fmt.Errorf("externalReference must be at most %d characters", maxExternalReferenceLength)
+	}
+	return validateMetadata(req.Metadata)
+}
+
+// validateMetadata checks that metadata, when given, is a JSON object of bounded size. A literal null counts as absent.
+func validateMetadata(metadata json.RawMessage) error {
+	trimmed := bytes.TrimSpace(metadata)
+	if len(metadata) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return errors.New("metadata must be a JSON object")
+	}
+	if len(metadata) > maxMetadataSize {
+		return fmt.Errorf("metadata must be at most %d bytes", maxMetadataSize)
+	}
+	return nil
+}
+
+// validateCurrency checks that code is an upper-case ISO 4217 currency code.
+func validateCurrency(code string) error {
+	if _, err := currency.ParseISO(code); err != nil || strings.ToUpper(code) != code {
+		return errors.New("currency must be an ISO 4217 code")
+	}
 	return nil
 }
 func logError(c *gin.Context, logger *zap.Logger, err error, status int, message string) {
@@ -5,26 +5,30 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
 
 type mockWalletOperationHandler struct {
-	ProcessOperationFunc func(ctx context.Context, req requests.WalletOperationRequest) error
-	GetWalletBalanceFunc func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
+	ProcessOperationFunc           func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error)
+	GetWalletBalanceFunc           func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
+	ProcessOperationIdempotentFunc func(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error)
 }
 
-func (m *mockWalletOperationHandler) ProcessOperation(ctx context.Context, req requests.WalletOperationRequest) error {
+func (m *mockWalletOperationHandler) ProcessOperation(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
 	if m.ProcessOperationFunc != nil {
 		return m.ProcessOperationFunc(ctx, req)
 	}
-	return nil
+	return &models.Operation{}, nil
 }
 
 func (m *mockWalletOperationHandler) GetWalletBalance(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
@@ -34,19 +38,21 @@ func (m *mockWalletOperationHandler) GetWalletBalance(ctx context.Context, walle
 	return nil, nil
 }
 
+func (m *mockWalletOperationHandler) ProcessOperationIdempotent(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error) {
+	if m.ProcessOperationIdempotentFunc != nil {
+		return m.ProcessOperationIdempotentFunc(ctx, req, key, fingerprint)
+	}
+	return nil, nil
+}
+
 func TestHandleWalletOperation(t *testing.T) {
 	logger, _ := zap.NewProduction()
-	defer func(logger *zap.Logger) {
-		err := logger.Sync()
-		if err != nil {
-			panic(err)
-		}
-	}(logger)
+	defer logger.Sync()
 
 	tests := []struct {
 		name                 string
 		requestBody          string
-		mockProcessOperation func(ctx context.Context, req requests.WalletOperationRequest) error
+		mockProcessOperation func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error)
 		mockGetWalletBalance func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
 		expectedStatus       int
 		expectedBody         string
@@ -84,43 +90,165 @@ func TestHandleWalletOperation(t *testing.T) {
 		{
 			name:        "Success",
 			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000}`,
-			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) error {
-				return nil
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return &models.Operation{}, nil
 			},
 			mockGetWalletBalance: func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
 				return &requests.WalletBalanceResponse{
 					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
 					Balance:  1000,
+					Currency: "USD",
 				}, nil
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"DEPOSIT","balance":1000}}`,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"DEPOSIT","balance":1000,"currency":"USD"}}`,
+		},
+		{
+			name:        "Success With Fee",
+			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 1000}`,
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return &models.Operation{Type: "WITHDRAW", Amount: req.Amount, Fee: 25}, nil
+			},
+			mockGetWalletBalance: func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
+				return &requests.WalletBalanceResponse{
+					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+					Balance:  975,
+					Currency: "USD",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"WITHDRAW","balance":975,"currency":"USD","fee":25}}`,
 		},
 		{
 			name:        "Insufficient Funds Error",
 			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 1000}`,
-			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) error {
-				return requests.InsufficientFundsError{}
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return nil, requests.InsufficientFundsError{Headroom: 250}
 			},
 			mockGetWalletBalance: func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
 				return &requests.WalletBalanceResponse{
 					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
 					Balance:  1000,
+					Currency: "USD",
 				}, nil
 			},
 			expectedStatus: http.StatusForbidden,
-			expectedBody:   `{"status":"error","error":"balance cant become negative: insufficient funds"}`,
+			expectedBody:   `{"status":"error","error":"balance cant become negative: insufficient funds: 250 left to spend"}`,
+		},
+		{
+			name:           "Invalid Currency",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000, "currency": "usd"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: currency must be an ISO 4217 code"}`,
+		},
+		{
+			name:           "Unknown Currency",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000, "currency": "XYZ"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: currency must be an ISO 4217 code"}`,
+		},
+		{
+			name:        "Currency Mismatch Error",
+			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000, "currency": "EUR"}`,
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return nil, requests.CurrencyMismatchError{WalletCurrency: "USD", Currency: "EUR"}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status":"error","error":"currency mismatch: wallet holds USD, not EUR"}`,
+		},
+		{
+			name:        "Amount As String",
+			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": "9007199254740993"}`,
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				assert.Equal(t, models.Amount(9007199254740993), req.Amount)
+				return &models.Operation{}, nil
+			},
+			mockGetWalletBalance: func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
+				return &requests.WalletBalanceResponse{
+					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+					Balance:  9007199254740993,
+					Currency: "USD",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"DEPOSIT","balance":9007199254740993,"currency":"USD"}}`,
+		},
+		{
+			name:           "Fractional Amount",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": "12.50"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"failed to process request: amount must be an integer number of minor units within 64 bits"}`,
+		},
+		{
+			name:           "Amount With Plus Sign",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": "+5"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"failed to process request: amount must be an integer number of minor units within 64 bits"}`,
+		},
+		{
+			name:           "Amount Beyond 64 Bits",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 9223372036854775808}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"failed to process request: amount must be an integer number of minor units within 64 bits"}`,
+		},
+		{
+			name:        "Balance Overflow",
+			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 9223372036854775807}`,
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return nil, fmt.Errorf("database.processOperation: %w", models.ErrAmountOverflow)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status":"error","error":"amount out of range: database.processOperation: amount overflows 64 bits"}`,
+		},
+		{
+			name:           "Metadata Not An Object",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000, "metadata": [1, 2]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: metadata must be a JSON object"}`,
+		},
+		{
+			name:        "Null Metadata",
+			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000, "metadata": null}`,
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return &models.Operation{}, nil
+			},
+			mockGetWalletBalance: func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
+				return &requests.WalletBalanceResponse{
+					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+					Balance:  1000,
+					Currency: "USD",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"DEPOSIT","balance":1000,"currency":"USD"}}`,
+		},
+		{
+			name:           "External Reference Too Long",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000, "externalReference": "` + strings.Repeat("r", 256) + `"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: externalReference must be at most 255 characters"}`,
+		},
+		{
+			name:        "Duplicate External Reference",
+			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000, "externalReference": "invoice-42", "metadata": {"orderId": 7}}`,
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				assert.JSONEq(t, `{"orderId": 7}`, string(req.Metadata))
+				return nil, requests.ExternalReferenceConflictError{ExternalReference: req.ExternalReference}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"duplicate external reference: operation with external reference \"invoice-42\" already exists"}`,
 		},
 		{
 			name:        "Wallet Not Found Error from ProcessOperation",
 			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 1000}`,
-			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) error {
-				return sql.ErrNoRows
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return nil, sql.ErrNoRows
 			},
 			mockGetWalletBalance: func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
 				return &requests.WalletBalanceResponse{
 					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
 					Balance:  1000,
+					Currency: "USD",
 				}, nil
 			},
 			expectedStatus: http.StatusNotFound,
@@ -129,13 +257,14 @@ func TestHandleWalletOperation(t *testing.T) {
 		{
 			name:        "Internal Server Error from ProcessOperation",
 			requestBody: `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 1000}`,
-			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) error {
-				return errors.New("some other error")
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return nil, errors.New("some other error")
 			},
 			mockGetWalletBalance: func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
 				return &requests.WalletBalanceResponse{
 					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
 					Balance:  1000,
+					Currency: "USD",
 				}, nil
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -166,13 +295,14 @@ func TestHandleWalletOperation(t *testing.T) {
 				return &requests.WalletBalanceResponse{
 					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
 					Balance:  1000,
+					Currency: "USD",
 				}, nil
 			},
-			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) error {
-				return nil
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return &models.Operation{}, nil
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"WITHDRAW","balance":1000}}`,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"WITHDRAW","balance":1000,"currency":"USD"}}`,
 		},
 		{
 			name:        "Empty JSON",
@@ -181,10 +311,11 @@ func TestHandleWalletOperation(t *testing.T) {
 				return &requests.WalletBalanceResponse{
 					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
 					Balance:  1000,
+					Currency: "USD",
 				}, nil
 			},
-			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) error {
-				return nil
+			mockProcessOperation: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+				return &models.Operation{}, nil
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"status":"error","error":"bad request data: empty wallet id"}`,
@@ -211,3 +342,134 @@ func TestHandleWalletOperation(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleWalletOperationIdempotent(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const requestBody = `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000}`
+	const storedBody = `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"DEPOSIT","balance":1000,"currency":"USD"}}`
+
+	tests := []struct {
+		name                           string
+		requestBody                    string
+		idempotencyKey                 string
+		mockProcessOperationIdempotent func(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error)
+		expectedStatus                 int
+		expectedBody                   string
+		expectedReplayed               bool
+	}{
+		{
+			name:           "First Request",
+			requestBody:    requestBody,
+			idempotencyKey: "key-1",
+			mockProcessOperationIdempotent: func(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error) {
+				return &requests.StoredResponse{Status: http.StatusOK, Body: []byte(storedBody)}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   storedBody,
+		},
+		{
+			name:           "Replayed Request",
+			requestBody:    requestBody,
+			idempotencyKey: "key-1",
+			mockProcessOperationIdempotent: func(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error) {
+				return &requests.StoredResponse{Status: http.StatusOK, Body: []byte(storedBody), Replayed: true}, nil
+			},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     storedBody,
+			expectedReplayed: true,
+		},
+		{
+			name:           "Key Reused With Different Body",
+			requestBody:    requestBody,
+			idempotencyKey: "key-1",
+			mockProcessOperationIdempotent: func(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error) {
+				return nil, requests.IdempotencyKeyConflictError{}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"idempotency key conflict: idempotency key was already used with a different request"}`,
+		},
+		{
+			name:           "Insufficient Funds Error",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 1000}`,
+			idempotencyKey: "key-2",
+			mockProcessOperationIdempotent: func(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error) {
+				return nil, requests.InsufficientFundsError{}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"status":"error","error":"balance cant become negative: insufficient funds: 0 left to spend"}`,
+		},
+		{
+			name:           "Withdrawal Limit Error",
+			requestBody:    `{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 1000}`,
+			idempotencyKey: "key-3",
+			mockProcessOperationIdempotent: func(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error) {
+				return nil, requests.WithdrawalLimitError{Limit: "daily", Max: 500}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"status":"error","error":"withdrawal limit exceeded: daily withdrawal limit of 500 exceeded"}`,
+		},
+		{
+			name:           "Key Too Long",
+			requestBody:    requestBody,
+			idempotencyKey: strings.Repeat("k", 256),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: idempotency key is too long"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWalletOperationHandler{
+				ProcessOperationFunc: func(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
+					t.Error("ProcessOperation must not be called when an idempotency key is given")
+					return &models.Operation{}, nil
+				},
+				ProcessOperationIdempotentFunc: tt.mockProcessOperationIdempotent,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Request.Header.Set(IdempotencyKeyHeader, tt.idempotencyKey)
+
+			HandleWalletOperation(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+			assert.Equal(t, tt.expectedReplayed, recorder.Header().Get("Idempotent-Replayed") == "true")
+		})
+	}
+}
+
+func TestHandleWalletOperationIdempotentFingerprint(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	var fingerprints []string
+	mockHandler := &mockWalletOperationHandler{
+		ProcessOperationIdempotentFunc: func(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error) {
+			fingerprints = append(fingerprints, fingerprint)
+			return &requests.StoredResponse{Status: http.StatusOK, Body: []byte(`{}`)}, nil
+		},
+	}
+	bodies := []string{
+		`{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1000}`,
+		`{"amount":1000,"operationType":"DEPOSIT","valletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef"}`,
+		`{"valletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 1001}`,
+	}
+	for _, body := range bodies {
+		recorder := httptest.NewRecorder()
+		gin.SetMode(gin.TestMode)
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set(IdempotencyKeyHeader, "key-1")
+		HandleWalletOperation(logger, mockHandler)(c)
+	}
+
+	assert.Len(t, fingerprints, 3)
+	assert.Equal(t, fingerprints[0], fingerprints[1], "formatting must not change the fingerprint")
+	assert.NotEqual(t, fingerprints[0], fingerprints[2], "a different amount must change the fingerprint")
+}
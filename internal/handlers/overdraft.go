@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type OverdraftLimitSetter interface {
+	SetOverdraftLimit(ctx context.Context, walletID string, limit models.Amount) (*requests.WalletBalanceResponse, error)
+}
+
+func HandleSetOverdraftLimit(logger *zap.Logger, setter OverdraftLimitSetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.SetOverdraftLimitRequest
+		const op = "api/v1/admin/wallets/{walletId}/overdraft"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if req.OverdraftLimit == nil {
+			logError(c, logger, errors.New("overdraftLimit is required"), http.StatusBadRequest, "bad request data")
+			return
+		}
+		if *req.OverdraftLimit < 0 {
+			logError(c, logger, errors.New("overdraftLimit cant be negative"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		balanceChan := make(chan *requests.WalletBalanceResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			balance, err := setter.SetOverdraftLimit(c.Request.Context(), walletID, *req.OverdraftLimit)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			balanceChan <- balance
+		}()
+		select {
+		case balance := <-balanceChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.Int64("overdraftLimit", int64(balance.OverdraftLimit)))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(balance))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockOverdraftLimitSetter struct {
+	SetOverdraftLimitFunc func(ctx context.Context, walletID string, limit models.Amount) (*requests.WalletBalanceResponse, error)
+}
+
+func (m *mockOverdraftLimitSetter) SetOverdraftLimit(ctx context.Context, walletID string, limit models.Amount) (*requests.WalletBalanceResponse, error) {
+	if m.SetOverdraftLimitFunc != nil {
+		return m.SetOverdraftLimitFunc(ctx, walletID, limit)
+	}
+	return nil, nil
+}
+
+func TestHandleSetOverdraftLimit(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const walletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+
+	tests := []struct {
+		name                  string
+		walletId              string
+		requestBody           string
+		mockSetOverdraftLimit func(ctx context.Context, walletID string, limit models.Amount) (*requests.WalletBalanceResponse, error)
+		expectedStatus        int
+		expectedBody          string
+	}{
+		{
+			name:           "Invalid UUID",
+			walletId:       "invalid-uuid",
+			requestBody:    `{"overdraftLimit": 1000}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid walletId format: invalid wallet id format"}`,
+		},
+		{
+			name:           "Missing Limit",
+			walletId:       walletID,
+			requestBody:    `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: overdraftLimit is required"}`,
+		},
+		{
+			name:           "Negative Limit",
+			walletId:       walletID,
+			requestBody:    `{"overdraftLimit": -1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: overdraftLimit cant be negative"}`,
+		},
+		{
+			name:        "Success",
+			walletId:    walletID,
+			requestBody: `{"overdraftLimit": 1000}`,
+			mockSetOverdraftLimit: func(ctx context.Context, id string, limit models.Amount) (*requests.WalletBalanceResponse, error) {
+				assert.Equal(t, models.Amount(1000), limit)
+				return &requests.WalletBalanceResponse{
+					WalletID:       id,
+					Balance:        -200,
+					Available:      -200,
+					Currency:       "USD",
+					OverdraftLimit: limit,
+					Status:         "ACTIVE",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":-200,"available":-200,"currency":"USD","overdraftLimit":1000,"status":"ACTIVE"}}`,
+		},
+		{
+			name:        "Wallet Not Found",
+			walletId:    walletID,
+			requestBody: `{"overdraftLimit": 0}`,
+			mockSetOverdraftLimit: func(ctx context.Context, id string, limit models.Amount) (*requests.WalletBalanceResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: no such a wallet"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockOverdraftLimitSetter{
+				SetOverdraftLimitFunc: tt.mockSetOverdraftLimit,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/admin/wallets/%s/overdraft", tt.walletId), bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "walletId", Value: tt.walletId}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleSetOverdraftLimit(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const maxDisplayNameLength = 200
+
+type OwnerCreator interface {
+	CreateOwner(ctx context.Context, req requests.CreateOwnerRequest) (*models.Owner, error)
+}
+
+type OwnerGetter interface {
+	GetOwner(ctx context.Context, ownerID string) (*models.Owner, error)
+	GetOwnerByExternalID(ctx context.Context, externalID string) (*models.Owner, error)
+}
+
+type WalletOwnerSetter interface {
+	SetWalletOwner(ctx context.Context, walletID, ownerID string) (*requests.WalletBalanceResponse, error)
+}
+
+type WalletsLister interface {
+	ListWallets(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error)
+}
+
+func HandleCreateOwner(logger *zap.Logger, creator OwnerCreator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.CreateOwnerRequest
+		const op = "api/v1/owners"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateCreateOwnerRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		ownerChan := make(chan *models.Owner, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			owner, err := creator.CreateOwner(c.Request.Context(), req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			ownerChan <- owner
+		}()
+		select {
+		case owner := <-ownerChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("ownerId", owner.ID))
+			c.JSON(http.StatusCreated, requests.WalletOperationResponseOK(ownerResponse(owner)))
+		case err := <-errChan:
+			var ownerExistsErr requests.OwnerExistsError
+			if errors.As(err, &ownerExistsErr) {
+				logError(c, logger, err, http.StatusConflict, "owner exists")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+func HandleGetOwner(logger *zap.Logger, getter OwnerGetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/owners/{ownerId}"
+		ownerID := c.Param("ownerId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("ownerId", ownerID))
+
+		if _, err := uuid.Parse(ownerID); err != nil {
+			logError(c, logger, errors.New("invalid owner id format"), http.StatusBadRequest, "invalid ownerId format")
+			return
+		}
+		getOwner(c, logger, func(ctx context.Context) (*models.Owner, error) {
+			return getter.GetOwner(ctx, ownerID)
+		})
+	}
+}
+
+// HandleGetOwnerByExternalID looks an owner up by the id the calling service knows it by.
+func HandleGetOwnerByExternalID(logger *zap.Logger, getter OwnerGetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/owners/by-external-id/{externalId}"
+		externalID := c.Param("externalId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("externalId", externalID))
+
+		getOwner(c, logger, func(ctx context.Context) (*models.Owner, error) {
+			return getter.GetOwnerByExternalID(ctx, externalID)
+		})
+	}
+}
+
+func getOwner(c *gin.Context, logger *zap.Logger, get func(ctx context.Context) (*models.Owner, error)) {
+	ownerChan := make(chan *models.Owner, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		owner, err := get(c.Request.Context())
+		if err != nil {
+			errChan <- err
+			return
+		}
+		ownerChan <- owner
+	}()
+	select {
+	case owner := <-ownerChan:
+		logRequest(c, logger, "request procceeded successfully", zap.String("ownerId", owner.ID))
+		c.JSON(http.StatusOK, requests.WalletOperationResponseOK(ownerResponse(owner)))
+	case err := <-errChan:
+		if errors.Is(err, sql.ErrNoRows) {
+			logError(c, logger, errors.New("no such an owner"), http.StatusNotFound, "owner not found")
+			return
+		}
+		logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// HandleListOwnerWallets pages through the wallets of one owner, with the same filters as the admin listing.
+func HandleListOwnerWallets(logger *zap.Logger, lister WalletsLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/owners/{ownerId}/wallets"
+		ownerID := c.Param("ownerId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("ownerId", ownerID))
+
+		if _, err := uuid.Parse(ownerID); err != nil {
+			logError(c, logger, errors.New("invalid owner id format"), http.StatusBadRequest, "invalid ownerId format")
+			return
+		}
+		filter, err := parseWalletsFilter(c)
+		if err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+		filter.OwnerID = ownerID
+		listWallets(c, logger, lister, filter)
+	}
+}
+
+// HandleListWallets pages through every wallet, for admins.
+func HandleListWallets(logger *zap.Logger, lister WalletsLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/admin/wallets"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		filter, err := parseWalletsFilter(c)
+		if err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+		if ownerID := c.Query("ownerId"); ownerID != "" {
+			if _, err := uuid.Parse(ownerID); err != nil {
+				logError(c, logger, errors.New("invalid owner id format"), http.StatusBadRequest, "bad request data")
+				return
+			}
+			filter.OwnerID = ownerID
+		}
+		listWallets(c, logger, lister, filter)
+	}
+}
+
+func listWallets(c *gin.Context, logger *zap.Logger, lister WalletsLister, filter requests.WalletsFilter) {
+	pageChan := make(chan *requests.WalletsPage, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		page, err := lister.ListWallets(c.Request.Context(), filter)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		pageChan <- page
+	}()
+	select {
+	case page := <-pageChan:
+		logRequest(c, logger, "request procceeded successfully", zap.Int("wallets", len(page.Wallets)))
+		c.JSON(http.StatusOK, requests.WalletOperationResponseOK(page))
+	case err := <-errChan:
+		var ownerNotFoundErr requests.OwnerNotFoundError
+		if errors.As(err, &ownerNotFoundErr) {
+			logError(c, logger, err, http.StatusNotFound, "owner not found")
+			return
+		}
+		logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func HandleSetWalletOwner(logger *zap.Logger, setter WalletOwnerSetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.SetWalletOwnerRequest
+		const op = "api/v1/admin/wallets/{walletId}/owner"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if _, err := uuid.Parse(req.OwnerID); err != nil {
+			logError(c, logger, errors.New("invalid owner id format"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		walletChan := make(chan *requests.WalletBalanceResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			wallet, err := setter.SetWalletOwner(c.Request.Context(), walletID, req.OwnerID)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			walletChan <- wallet
+		}()
+		select {
+		case wallet := <-walletChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.String("ownerId", req.OwnerID))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(wallet))
+		case err := <-errChan:
+			var ownerNotFoundErr requests.OwnerNotFoundError
+			if errors.As(err, &ownerNotFoundErr) {
+				logError(c, logger, err, http.StatusNotFound, "owner not found")
+				return
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+func validateCreateOwnerRequest(req requests.CreateOwnerRequest) error {
+	if strings.TrimSpace(req.ExternalID) == "" {
+		return errors.New("empty external id")
+	}
+	if len(req.ExternalID) > maxExternalReferenceLength {
+		return fmt.Errorf("externalId must be at most %d characters", maxExternalReferenceLength)
+	}
+	if strings.TrimSpace(req.DisplayName) == "" {
+		return errors.New("empty display name")
+	}
+	if len(req.DisplayName) > maxDisplayNameLength {
+		return fmt.Errorf("displayName must be at most %d characters", maxDisplayNameLength)
+	}
+	return validateMetadata(req.Metadata)
+}
+
+func parseWalletsFilter(c *gin.Context) (requests.WalletsFilter, error) {
+	filter := requests.WalletsFilter{
+		Sort:       requests.SortByCreatedAt,
+		Descending: true,
+		Limit:      requests.DefaultWalletsLimit,
+	}
+
+	if status := c.Query("status"); status != "" {
+		switch status {
+		case models.WalletActive, models.WalletFrozen, models.WalletClosed:
+		default:
+			return filter, errors.New("status must be ACTIVE, FROZEN or CLOSED")
+		}
+		filter.Status = status
+	}
+	if currency := c.Query("currency"); currency != "" {
+		if err := validateCurrency(currency); err != nil {
+			return filter, err
+		}
+		filter.Currency = currency
+	}
+	if tier := c.Query("tier"); tier != "" {
+		if err := models.ValidateTier(tier); err != nil {
+			return filter, err
+		}
+		filter.Tier = tier
+	}
+
+	var err error
+	if filter.MinBalance, err = parseBalanceQuery(c, "minBalance"); err != nil {
+		return filter, err
+	}
+	if filter.MaxBalance, err = parseBalanceQuery(c, "maxBalance"); err != nil {
+		return filter, err
+	}
+	if filter.MinBalance != nil && filter.MaxBalance != nil && *filter.MinBalance > *filter.MaxBalance {
+		return filter, errors.New("minBalance must not be greater than maxBalance")
+	}
+
+	switch sort := c.DefaultQuery("sort", requests.SortByCreatedAt); sort {
+	case requests.SortByCreatedAt, requests.SortByBalance:
+		filter.Sort = sort
+	default:
+		return filter, errors.New("sort must be createdAt or balance")
+	}
+	switch order := c.DefaultQuery("order", "desc"); order {
+	case "asc", "desc":
+		filter.Descending = order == "desc"
+	default:
+		return filter, errors.New("order must be asc or desc")
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		filter.Cursor, err = requests.DecodeWalletsCursor(cursor, filter.Sort)
+		if err != nil {
+			return filter, err
+		}
+		if _, err := uuid.Parse(filter.Cursor.ID); err != nil {
+			return filter, errors.New("invalid cursor")
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit <= 0 || filter.Limit > requests.MaxWalletsLimit {
+			return filter, errors.New("limit must be an integer between 1 and " + strconv.Itoa(requests.MaxWalletsLimit))
+		}
+	}
+
+	return filter, nil
+}
+
+// parseBalanceQuery reads a balance bound, which unlike operation amounts may be negative.
+func parseBalanceQuery(c *gin.Context, name string) (*models.Amount, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, errors.New(name + " must be an integer")
+	}
+	amount := models.Amount(parsed)
+	return &amount, nil
+}
+
+func ownerResponse(owner *models.Owner) requests.OwnerResponse {
+	return requests.OwnerResponse{
+		ID:          owner.ID,
+		ExternalID:  owner.ExternalID,
+		DisplayName: owner.DisplayName,
+		Metadata:    owner.Metadata,
+		CreatedAt:   owner.CreatedAt,
+	}
+}
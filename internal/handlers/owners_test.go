@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockOwnerCreator struct {
+	CreateOwnerFunc func(ctx context.Context, req requests.CreateOwnerRequest) (*models.Owner, error)
+}
+
+func (m *mockOwnerCreator) CreateOwner(ctx context.Context, req requests.CreateOwnerRequest) (*models.Owner, error) {
+	if m.CreateOwnerFunc != nil {
+		return m.CreateOwnerFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+type mockWalletsLister struct {
+	ListWalletsFunc func(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error)
+}
+
+func (m *mockWalletsLister) ListWallets(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error) {
+	if m.ListWalletsFunc != nil {
+		return m.ListWalletsFunc(ctx, filter)
+	}
+	return nil, nil
+}
+
+func TestHandleCreateOwner(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		requestBody     string
+		mockCreateOwner func(ctx context.Context, req requests.CreateOwnerRequest) (*models.Owner, error)
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:           "Empty External Id",
+			requestBody:    `{"displayName": "Ada"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: empty external id"}`,
+		},
+		{
+			name:           "Metadata Not An Object",
+			requestBody:    `{"externalId": "user-1", "displayName": "Ada", "metadata": [1]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: metadata must be a JSON object"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"externalId": "user-1", "displayName": "Ada", "metadata": {"plan": "pro"}}`,
+			mockCreateOwner: func(ctx context.Context, req requests.CreateOwnerRequest) (*models.Owner, error) {
+				return &models.Owner{
+					ID:          "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+					ExternalID:  req.ExternalID,
+					DisplayName: req.DisplayName,
+					Metadata:    req.Metadata,
+					CreatedAt:   createdAt,
+				}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":{"id":"a1b2c3d4-e5f6-7890-1234-567890abcdef","externalId":"user-1","displayName":"Ada","metadata":{"plan":"pro"},"createdAt":"2024-05-01T12:00:00Z"}}`,
+		},
+		{
+			name:        "Null Metadata",
+			requestBody: `{"externalId": "user-1", "displayName": "Ada", "metadata": null}`,
+			mockCreateOwner: func(ctx context.Context, req requests.CreateOwnerRequest) (*models.Owner, error) {
+				return &models.Owner{
+					ID:          "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+					ExternalID:  req.ExternalID,
+					DisplayName: req.DisplayName,
+					CreatedAt:   createdAt,
+				}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":{"id":"a1b2c3d4-e5f6-7890-1234-567890abcdef","externalId":"user-1","displayName":"Ada","createdAt":"2024-05-01T12:00:00Z"}}`,
+		},
+		{
+			name:        "External Id Taken",
+			requestBody: `{"externalId": "user-1", "displayName": "Ada"}`,
+			mockCreateOwner: func(ctx context.Context, req requests.CreateOwnerRequest) (*models.Owner, error) {
+				return nil, requests.OwnerExistsError{}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"owner exists: owner with this external id already exists"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCreator := &mockOwnerCreator{
+				CreateOwnerFunc: tt.mockCreateOwner,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/owners", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleCreateOwner(logger, mockCreator)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleListOwnerWallets(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const ownerID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+
+	tests := []struct {
+		name            string
+		ownerId         string
+		query           string
+		mockListWallets func(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error)
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:           "Invalid UUID",
+			ownerId:        "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid ownerId format: invalid owner id format"}`,
+		},
+		{
+			name:           "Bad Sort",
+			ownerId:        ownerID,
+			query:          "?sort=currency",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: sort must be createdAt or balance"}`,
+		},
+		{
+			name:           "Cursor Of Another Sort",
+			ownerId:        ownerID,
+			query:          "?sort=balance&cursor=" + requests.WalletsCursor{CreatedAt: time.Now(), ID: ownerID}.Encode(requests.SortByCreatedAt),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: invalid cursor"}`,
+		},
+		{
+			name:    "Success",
+			ownerId: ownerID,
+			query:   "?sort=balance&order=asc&status=ACTIVE&minBalance=-100&limit=1",
+			mockListWallets: func(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error) {
+				assert.Equal(t, ownerID, filter.OwnerID)
+				assert.Equal(t, requests.SortByBalance, filter.Sort)
+				assert.False(t, filter.Descending)
+				assert.Equal(t, models.WalletActive, filter.Status)
+				assert.Equal(t, models.Amount(-100), *filter.MinBalance)
+				assert.Equal(t, 1, filter.Limit)
+				return &requests.WalletsPage{
+					Wallets: []requests.WalletBalanceResponse{
+						{WalletID: "b2c3d4e5-f6a7-8901-2345-67890abcdef1", Balance: 500, Available: 400, Currency: "USD", Status: "ACTIVE", OwnerID: &filter.OwnerID},
+					},
+					NextCursor: "next",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"wallets":[{"walletId":"b2c3d4e5-f6a7-8901-2345-67890abcdef1","balance":500,"available":400,"currency":"USD","status":"ACTIVE","ownerId":"a1b2c3d4-e5f6-7890-1234-567890abcdef"}],"nextCursor":"next"}}`,
+		},
+		{
+			name:    "Owner Not Found",
+			ownerId: ownerID,
+			mockListWallets: func(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error) {
+				assert.Equal(t, requests.SortByCreatedAt, filter.Sort)
+				assert.True(t, filter.Descending)
+				return nil, requests.OwnerNotFoundError{OwnerID: filter.OwnerID}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"owner not found: owner with id a1b2c3d4-e5f6-7890-1234-567890abcdef not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLister := &mockWalletsLister{
+				ListWalletsFunc: tt.mockListWallets,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/owners/"+tt.ownerId+"/wallets"+tt.query, nil)
+			c.Params = []gin.Param{{Key: "ownerId", Value: tt.ownerId}}
+
+			HandleListOwnerWallets(logger, mockLister)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleListWallets(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name            string
+		query           string
+		mockListWallets func(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error)
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:           "Bad Balance Range",
+			query:          "?minBalance=10&maxBalance=5",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: minBalance must not be greater than maxBalance"}`,
+		},
+		{
+			name:           "Invalid Owner Id",
+			query:          "?ownerId=nope",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: invalid owner id format"}`,
+		},
+		{
+			name:  "Empty Page",
+			query: "?currency=EUR&tier=GOLD",
+			mockListWallets: func(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error) {
+				assert.Empty(t, filter.OwnerID)
+				assert.Equal(t, "EUR", filter.Currency)
+				assert.Equal(t, "GOLD", filter.Tier)
+				assert.Equal(t, requests.DefaultWalletsLimit, filter.Limit)
+				return &requests.WalletsPage{Wallets: []requests.WalletBalanceResponse{}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"wallets":[]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLister := &mockWalletsLister{
+				ListWalletsFunc: tt.mockListWallets,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/admin/wallets"+tt.query, nil)
+
+			HandleListWallets(logger, mockLister)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
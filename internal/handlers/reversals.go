@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type OperationReverser interface {
+	ReverseOperation(ctx context.Context, operationID string, amount models.Amount) (*requests.OperationResponse, error)
+}
+
+func HandleReverseOperation(logger *zap.Logger, reverser OperationReverser) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.ReverseOperationRequest
+		const op = "api/v1/operations/{id}/reverse"
+		operationID := c.Param("id")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("operationId", operationID))
+
+		if _, err := uuid.Parse(operationID); err != nil {
+			logError(c, logger, errors.New("invalid operation id format"), http.StatusBadRequest, "invalid operation id format")
+			return
+		}
+		// The body is optional: an empty body reverses whatever is left of the operation.
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if req.Amount < 0 {
+			logError(c, logger, errors.New("amount must be a positive integer"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		reversalChan := make(chan *requests.OperationResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			reversal, err := reverser.ReverseOperation(c.Request.Context(), operationID, req.Amount)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			reversalChan <- reversal
+		}()
+		select {
+		case reversal := <-reversalChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("operationId", operationID), zap.String("reversalId", reversal.ID))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(reversal))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such an operation"), http.StatusNotFound, "operation not found")
+				return
+			}
+			var insufficientFundsErr requests.InsufficientFundsError
+			if errors.As(err, &insufficientFundsErr) {
+				logError(c, logger, err, http.StatusForbidden, "balance cant become negative")
+				return
+			}
+			var walletNotActiveErr requests.WalletNotActiveError
+			if errors.As(err, &walletNotActiveErr) {
+				logError(c, logger, err, http.StatusConflict, "wallet not active")
+				return
+			}
+			var exceedsErr requests.ReversalExceedsOriginalError
+			if errors.As(err, &exceedsErr) {
+				logError(c, logger, err, http.StatusUnprocessableEntity, "bad reversal amount")
+				return
+			}
+			var notReversibleErr requests.OperationNotReversibleError
+			if errors.As(err, &notReversibleErr) {
+				logError(c, logger, err, http.StatusConflict, "bad reversal")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockOperationReverser struct {
+	ReverseOperationFunc func(ctx context.Context, operationID string, amount models.Amount) (*requests.OperationResponse, error)
+}
+
+func (m *mockOperationReverser) ReverseOperation(ctx context.Context, operationID string, amount models.Amount) (*requests.OperationResponse, error) {
+	if m.ReverseOperationFunc != nil {
+		return m.ReverseOperationFunc(ctx, operationID, amount)
+	}
+	return nil, nil
+}
+
+func TestHandleReverseOperation(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const operationID = "5c4a3b2e-9d1f-4f5a-8a6b-2c3d4e5f6a7b"
+
+	tests := []struct {
+		name                 string
+		operationId          string
+		requestBody          string
+		mockReverseOperation func(ctx context.Context, operationID string, amount models.Amount) (*requests.OperationResponse, error)
+		expectedStatus       int
+		expectedBody         string
+	}{
+		{
+			name:           "Invalid Operation Id",
+			operationId:    "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid operation id format: invalid operation id format"}`,
+		},
+		{
+			name:           "Invalid Amount",
+			operationId:    operationID,
+			requestBody:    `{"amount": -5}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: amount must be a positive integer"}`,
+		},
+		{
+			name:        "Partial Refund",
+			operationId: operationID,
+			requestBody: `{"amount": 40}`,
+			mockReverseOperation: func(ctx context.Context, id string, amount models.Amount) (*requests.OperationResponse, error) {
+				assert.Equal(t, operationID, id)
+				assert.Equal(t, models.Amount(40), amount)
+				original := id
+				return &requests.OperationResponse{
+					ID:            "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11",
+					WalletID:      "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+					OperationType: "DEPOSIT",
+					Amount:        amount,
+					Currency:      "USD",
+					Timestamp:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+					ReversalOf:    &original,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"id":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"DEPOSIT","amount":40,"currency":"USD","timestamp":"2024-05-01T12:00:00Z","reversalOf":"5c4a3b2e-9d1f-4f5a-8a6b-2c3d4e5f6a7b"}}`,
+		},
+		{
+			name:        "Exceeds Original",
+			operationId: operationID,
+			requestBody: `{"amount": 500}`,
+			mockReverseOperation: func(ctx context.Context, id string, amount models.Amount) (*requests.OperationResponse, error) {
+				return nil, requests.ReversalExceedsOriginalError{Remaining: 60}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status":"error","error":"bad reversal amount: reversal exceeds the 60 left to reverse"}`,
+		},
+		{
+			name:        "Reversal Of Reversal",
+			operationId: operationID,
+			mockReverseOperation: func(ctx context.Context, id string, amount models.Amount) (*requests.OperationResponse, error) {
+				return nil, requests.OperationNotReversibleError{Reason: "it is a reversal"}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"bad reversal: operation cannot be reversed: it is a reversal"}`,
+		},
+		{
+			name:        "Insufficient Funds",
+			operationId: operationID,
+			mockReverseOperation: func(ctx context.Context, id string, amount models.Amount) (*requests.OperationResponse, error) {
+				return nil, requests.InsufficientFundsError{}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"status":"error","error":"balance cant become negative: insufficient funds: 0 left to spend"}`,
+		},
+		{
+			name:        "Operation Not Found",
+			operationId: operationID,
+			mockReverseOperation: func(ctx context.Context, id string, amount models.Amount) (*requests.OperationResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"operation not found: no such an operation"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockOperationReverser{
+				ReverseOperationFunc: tt.mockReverseOperation,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/operations/"+tt.operationId+"/reverse", bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "id", Value: tt.operationId}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleReverseOperation(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
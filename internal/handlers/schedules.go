@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ScheduleHandler interface {
+	CreateSchedule(ctx context.Context, req requests.CreateScheduleRequest) (*models.Schedule, error)
+	GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error)
+	ListSchedules(ctx context.Context, walletID string) ([]models.Schedule, error)
+	UpdateSchedule(ctx context.Context, scheduleID string, req requests.UpdateScheduleRequest) (*models.Schedule, error)
+	CancelSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error)
+}
+
+func HandleCreateSchedule(logger *zap.Logger, handler ScheduleHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.CreateScheduleRequest
+		const op = "api/v1/schedules"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateCreateScheduleRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		runScheduleAction(c, logger, http.StatusCreated, func(ctx context.Context) (*models.Schedule, error) {
+			return handler.CreateSchedule(ctx, req)
+		})
+	}
+}
+
+func HandleGetSchedule(logger *zap.Logger, handler ScheduleHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/schedules/{scheduleId}"
+		scheduleID := c.Param("scheduleId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("scheduleId", scheduleID))
+
+		if _, err := uuid.Parse(scheduleID); err != nil {
+			logError(c, logger, errors.New("invalid schedule id format"), http.StatusBadRequest, "invalid scheduleId format")
+			return
+		}
+		runScheduleAction(c, logger, http.StatusOK, func(ctx context.Context) (*models.Schedule, error) {
+			return handler.GetSchedule(ctx, scheduleID)
+		})
+	}
+}
+
+func HandleListSchedules(logger *zap.Logger, handler ScheduleHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/wallets/{walletId}/schedules"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+
+		schedulesChan := make(chan []models.Schedule, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			schedules, err := handler.ListSchedules(c.Request.Context(), walletID)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			schedulesChan <- schedules
+		}()
+		select {
+		case schedules := <-schedulesChan:
+			resp := make([]requests.ScheduleResponse, len(schedules))
+			for i := range schedules {
+				resp[i] = scheduleResponse(&schedules[i])
+			}
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.Int("schedules", len(resp)))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(resp))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+	}
+}
+
+func HandleUpdateSchedule(logger *zap.Logger, handler ScheduleHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.UpdateScheduleRequest
+		const op = "api/v1/schedules/{scheduleId}"
+		scheduleID := c.Param("scheduleId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("scheduleId", scheduleID))
+
+		if _, err := uuid.Parse(scheduleID); err != nil {
+			logError(c, logger, errors.New("invalid schedule id format"), http.StatusBadRequest, "invalid scheduleId format")
+			return
+		}
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if req.Amount != nil && *req.Amount <= 0 {
+			logError(c, logger, errors.New("amount must be a positive integer"), http.StatusBadRequest, "bad request data")
+			return
+		}
+		if req.Status != nil && *req.Status != models.ScheduleActive && *req.Status != models.SchedulePaused {
+			logError(c, logger, errors.New("status must be ACTIVE or PAUSED"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		runScheduleAction(c, logger, http.StatusOK, func(ctx context.Context) (*models.Schedule, error) {
+			return handler.UpdateSchedule(ctx, scheduleID, req)
+		})
+	}
+}
+
+func HandleCancelSchedule(logger *zap.Logger, handler ScheduleHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/schedules/{scheduleId}"
+		scheduleID := c.Param("scheduleId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("scheduleId", scheduleID))
+
+		if _, err := uuid.Parse(scheduleID); err != nil {
+			logError(c, logger, errors.New("invalid schedule id format"), http.StatusBadRequest, "invalid scheduleId format")
+			return
+		}
+		runScheduleAction(c, logger, http.StatusOK, func(ctx context.Context) (*models.Schedule, error) {
+			return handler.CancelSchedule(ctx, scheduleID)
+		})
+	}
+}
+
+func runScheduleAction(c *gin.Context, logger *zap.Logger, status int, action func(ctx context.Context) (*models.Schedule, error)) {
+	scheduleChan := make(chan *models.Schedule, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		schedule, err := action(c.Request.Context())
+		if err != nil {
+			errChan <- err
+			return
+		}
+		scheduleChan <- schedule
+	}()
+	select {
+	case schedule := <-scheduleChan:
+		logRequest(c, logger, "request procceeded successfully", zap.String("scheduleId", schedule.ID), zap.String("status", schedule.Status))
+		c.JSON(status, requests.WalletOperationResponseOK(scheduleResponse(schedule)))
+	case err := <-errChan:
+		if errors.Is(err, sql.ErrNoRows) {
+			logError(c, logger, err, http.StatusNotFound, "not found")
+			return
+		}
+		var currencyMismatchErr requests.CurrencyMismatchError
+		if errors.As(err, &currencyMismatchErr) {
+			logError(c, logger, err, http.StatusUnprocessableEntity, "currency mismatch")
+			return
+		}
+		var walletNotActiveErr requests.WalletNotActiveError
+		if errors.As(err, &walletNotActiveErr) {
+			logError(c, logger, err, http.StatusConflict, "wallet not active")
+			return
+		}
+		var scheduleFinishedErr requests.ScheduleFinishedError
+		if errors.As(err, &scheduleFinishedErr) {
+			logError(c, logger, err, http.StatusConflict, "schedule cannot be changed")
+			return
+		}
+		logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func scheduleResponse(schedule *models.Schedule) requests.ScheduleResponse {
+	return requests.ScheduleResponse{
+		ID:              schedule.ID,
+		WalletID:        schedule.WalletID,
+		OperationType:   schedule.Type,
+		Amount:          schedule.Amount,
+		Currency:        schedule.Currency,
+		Interval:        schedule.Interval,
+		IntervalCount:   schedule.IntervalCount,
+		StartAt:         schedule.StartAt,
+		EndAt:           schedule.EndAt,
+		NextRunAt:       schedule.NextRunAt,
+		Occurrence:      schedule.Occurrence,
+		Status:          schedule.Status,
+		Attempts:        schedule.Attempts,
+		LastError:       schedule.LastError,
+		LastOperationID: schedule.LastOperationID,
+		CreatedAt:       schedule.CreatedAt,
+	}
+}
+
+func validateCreateScheduleRequest(req requests.CreateScheduleRequest) error {
+	err := validateRequest(requests.WalletOperationRequest{
+		WalletID:      req.WalletID,
+		OperationType: req.OperationType,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+	})
+	if err != nil {
+		return err
+	}
+	switch req.Interval {
+	case "", models.IntervalDay, models.IntervalWeek, models.IntervalMonth:
+	default:
+		return errors.New("interval must be DAY, WEEK or MONTH")
+	}
+	if req.IntervalCount < 0 || (req.Interval == "" && req.IntervalCount > 0) {
+		return errors.New("intervalCount must be a positive integer and needs an interval")
+	}
+	if req.EndAt != nil && req.StartAt != nil && req.EndAt.Before(*req.StartAt) {
+		return errors.New("endAt must not be before startAt")
+	}
+	return nil
+}
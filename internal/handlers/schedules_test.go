@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockScheduleHandler struct {
+	CreateScheduleFunc func(ctx context.Context, req requests.CreateScheduleRequest) (*models.Schedule, error)
+	GetScheduleFunc    func(ctx context.Context, scheduleID string) (*models.Schedule, error)
+	ListSchedulesFunc  func(ctx context.Context, walletID string) ([]models.Schedule, error)
+	UpdateScheduleFunc func(ctx context.Context, scheduleID string, req requests.UpdateScheduleRequest) (*models.Schedule, error)
+	CancelScheduleFunc func(ctx context.Context, scheduleID string) (*models.Schedule, error)
+}
+
+func (m *mockScheduleHandler) CreateSchedule(ctx context.Context, req requests.CreateScheduleRequest) (*models.Schedule, error) {
+	return m.CreateScheduleFunc(ctx, req)
+}
+
+func (m *mockScheduleHandler) GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	return m.GetScheduleFunc(ctx, scheduleID)
+}
+
+func (m *mockScheduleHandler) ListSchedules(ctx context.Context, walletID string) ([]models.Schedule, error) {
+	return m.ListSchedulesFunc(ctx, walletID)
+}
+
+func (m *mockScheduleHandler) UpdateSchedule(ctx context.Context, scheduleID string, req requests.UpdateScheduleRequest) (*models.Schedule, error) {
+	return m.UpdateScheduleFunc(ctx, scheduleID, req)
+}
+
+func (m *mockScheduleHandler) CancelSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	return m.CancelScheduleFunc(ctx, scheduleID)
+}
+
+func testSchedule() *models.Schedule {
+	month := models.IntervalMonth
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	return &models.Schedule{
+		ID:            "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11",
+		WalletID:      "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+		Type:          "WITHDRAW",
+		Amount:        999,
+		Currency:      "USD",
+		Interval:      &month,
+		IntervalCount: 1,
+		StartAt:       start,
+		NextRunAt:     start,
+		Status:        models.ScheduleActive,
+		CreatedAt:     start.Add(-time.Hour),
+	}
+}
+
+const testScheduleJSON = `{"id":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","operationType":"WITHDRAW",` +
+	`"amount":999,"currency":"USD","interval":"MONTH","intervalCount":1,"startAt":"2024-05-01T09:00:00Z","nextRunAt":"2024-05-01T09:00:00Z",` +
+	`"occurrence":0,"status":"ACTIVE","attempts":0,"createdAt":"2024-05-01T08:00:00Z"}`
+
+func TestHandleCreateSchedule(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name               string
+		requestBody        string
+		mockCreateSchedule func(ctx context.Context, req requests.CreateScheduleRequest) (*models.Schedule, error)
+		expectedStatus     int
+		expectedBody       string
+	}{
+		{
+			name:           "Bad Interval",
+			requestBody:    `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 999, "interval": "YEAR"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: interval must be DAY, WEEK or MONTH"}`,
+		},
+		{
+			name:           "Interval Count Without Interval",
+			requestBody:    `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 999, "intervalCount": 2}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: intervalCount must be a positive integer and needs an interval"}`,
+		},
+		{
+			name:           "Invalid Amount",
+			requestBody:    `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 0}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: amount must be a positive integer"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "WITHDRAW", "amount": 999, "startAt": "2024-05-01T09:00:00Z", "interval": "MONTH"}`,
+			mockCreateSchedule: func(ctx context.Context, req requests.CreateScheduleRequest) (*models.Schedule, error) {
+				assert.Equal(t, models.IntervalMonth, req.Interval)
+				return testSchedule(), nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":` + testScheduleJSON + `}`,
+		},
+		{
+			name:        "Wallet Not Found",
+			requestBody: `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "operationType": "DEPOSIT", "amount": 999}`,
+			mockCreateSchedule: func(ctx context.Context, req requests.CreateScheduleRequest) (*models.Schedule, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"not found: sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockScheduleHandler{
+				CreateScheduleFunc: tt.mockCreateSchedule,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/schedules", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleCreateSchedule(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleUpdateSchedule(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const scheduleID = "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11"
+
+	tests := []struct {
+		name               string
+		requestBody        string
+		mockUpdateSchedule func(ctx context.Context, scheduleID string, req requests.UpdateScheduleRequest) (*models.Schedule, error)
+		expectedStatus     int
+		expectedBody       string
+	}{
+		{
+			name:           "Bad Status",
+			requestBody:    `{"status": "COMPLETED"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: status must be ACTIVE or PAUSED"}`,
+		},
+		{
+			name:        "Pause",
+			requestBody: `{"status": "PAUSED"}`,
+			mockUpdateSchedule: func(ctx context.Context, id string, req requests.UpdateScheduleRequest) (*models.Schedule, error) {
+				schedule := testSchedule()
+				schedule.Status = *req.Status
+				return schedule, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":` + strings.Replace(testScheduleJSON, `"ACTIVE"`, `"PAUSED"`, 1) + `}`,
+		},
+		{
+			name:        "Cancelled",
+			requestBody: `{"amount": 5}`,
+			mockUpdateSchedule: func(ctx context.Context, id string, req requests.UpdateScheduleRequest) (*models.Schedule, error) {
+				return nil, requests.ScheduleFinishedError{Status: models.ScheduleCancelled}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"schedule cannot be changed: schedule is CANCELLED"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockScheduleHandler{
+				UpdateScheduleFunc: tt.mockUpdateSchedule,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPatch, "/api/v1/schedules/"+scheduleID, bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "scheduleId", Value: scheduleID}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleUpdateSchedule(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleListSchedules(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	mockHandler := &mockScheduleHandler{
+		ListSchedulesFunc: func(ctx context.Context, walletID string) ([]models.Schedule, error) {
+			return []models.Schedule{*testSchedule()}, nil
+		},
+	}
+	recorder := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/wallets/a1b2c3d4-e5f6-7890-1234-567890abcdef/schedules", nil)
+	c.Params = []gin.Param{{Key: "walletId", Value: "a1b2c3d4-e5f6-7890-1234-567890abcdef"}}
+
+	HandleListSchedules(logger, mockHandler)(c)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"OK","data":[`+testScheduleJSON+`]}`, recorder.Body.String())
+}
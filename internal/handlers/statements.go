@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/foreground-eclipse/wallet/internal/statements"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type StatementStreamer interface {
+	StreamStatement(ctx context.Context, walletID string, from, to time.Time,
+		begin func(models.Statement) error, line func(models.StatementLine) error) (*models.Statement, error)
+}
+
+// HandleGetStatement streams a wallet statement straight into the response. It runs inline rather than
+// in a goroutine because the body is written while rows are read; once the first byte is out, errors can
+// only be logged and the response is cut short.
+func HandleGetStatement(logger *zap.Logger, streamer StatementStreamer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/wallets/{walletId}/statement"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		from, to, err := parseStatementPeriod(c)
+		if err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+		format := c.DefaultQuery("format", statements.FormatJSON)
+		writer, err := statements.NewWriter(format, c.Writer)
+		if err != nil {
+			logError(c, logger, errors.New("format must be csv, json or pdf"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		started := false
+		begin := func(statement models.Statement) error {
+			started = true
+			c.Header("Content-Type", writer.ContentType())
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s-%s.%s"`, walletID, from.UTC().Format("20060102"), format))
+			c.Status(http.StatusOK)
+			return writer.Begin(statement)
+		}
+		statement, err := streamer.StreamStatement(c.Request.Context(), walletID, from, to, begin, writer.Line)
+		if err == nil {
+			err = writer.End(*statement)
+		}
+		if err != nil {
+			if started {
+				logger.Error("statement stream aborted", zap.String("walletId", walletID), zap.Error(err))
+				c.Abort()
+				return
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.String("format", format))
+	}
+}
+
+func parseStatementPeriod(c *gin.Context) (time.Time, time.Time, error) {
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if from == nil || to == nil {
+		return time.Time{}, time.Time{}, errors.New("from and to are required")
+	}
+	if !from.Before(*to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	return *from, *to, nil
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockStatementStreamer struct {
+	StreamStatementFunc func(ctx context.Context, walletID string, from, to time.Time,
+		begin func(models.Statement) error, line func(models.StatementLine) error) (*models.Statement, error)
+}
+
+func (m *mockStatementStreamer) StreamStatement(ctx context.Context, walletID string, from, to time.Time,
+	begin func(models.Statement) error, line func(models.StatementLine) error) (*models.Statement, error) {
+	if m.StreamStatementFunc != nil {
+		return m.StreamStatementFunc(ctx, walletID, from, to, begin, line)
+	}
+	return nil, nil
+}
+
+func TestHandleGetStatement(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const walletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+	const period = "from=2024-05-01T00:00:00Z&to=2024-06-01T00:00:00Z"
+
+	oneDeposit := func(ctx context.Context, id string, from, to time.Time,
+		begin func(models.Statement) error, line func(models.StatementLine) error) (*models.Statement, error) {
+		statement := models.Statement{WalletID: id, Currency: "USD", From: from, To: to, OpeningBalance: 100}
+		if err := begin(statement); err != nil {
+			return nil, err
+		}
+		err := line(models.StatementLine{
+			Operation: models.Operation{ID: "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11", Type: "DEPOSIT", Amount: 50, Timestamp: from.Add(time.Hour)},
+			Balance:   150,
+		})
+		if err != nil {
+			return nil, err
+		}
+		statement.ClosingBalance = 150
+		return &statement, nil
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		mockStreamStatement func(ctx context.Context, walletID string, from, to time.Time,
+			begin func(models.Statement) error, line func(models.StatementLine) error) (*models.Statement, error)
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:           "Missing Period",
+			query:          "from=2024-05-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: from and to are required"}`,
+		},
+		{
+			name:           "Inverted Period",
+			query:          "from=2024-06-01T00:00:00Z&to=2024-05-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: from must be before to"}`,
+		},
+		{
+			name:           "Unknown Format",
+			query:          period + "&format=xlsx",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: format must be csv, json or pdf"}`,
+		},
+		{
+			name:                "JSON",
+			query:               period,
+			mockStreamStatement: oneDeposit,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody: `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","currency":"USD","from":"2024-05-01T00:00:00Z","to":"2024-06-01T00:00:00Z","openingBalance":100,` +
+				`"operations":[{"id":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","operationType":"DEPOSIT","amount":50,"balance":150,"timestamp":"2024-05-01T01:00:00Z"}],"closingBalance":150}}`,
+		},
+		{
+			name:                "CSV",
+			query:               period + "&format=csv",
+			mockStreamStatement: oneDeposit,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+		{
+			name:  "Wallet Not Found",
+			query: period,
+			mockStreamStatement: func(ctx context.Context, id string, from, to time.Time,
+				begin func(models.Statement) error, line func(models.StatementLine) error) (*models.Statement, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: no such a wallet"}`,
+		},
+		{
+			name:  "Fails Midway",
+			query: period + "&format=csv",
+			mockStreamStatement: func(ctx context.Context, id string, from, to time.Time,
+				begin func(models.Statement) error, line func(models.StatementLine) error) (*models.Statement, error) {
+				if err := begin(models.Statement{WalletID: id, From: from, To: to}); err != nil {
+					return nil, err
+				}
+				return nil, errors.New("connection reset")
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockStatementStreamer{
+				StreamStatementFunc: tt.mockStreamStatement,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/wallets/"+walletID+"/statement?"+tt.query, nil)
+			c.Params = []gin.Param{{Key: "walletId", Value: walletID}}
+
+			HandleGetStatement(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, recorder.Header().Get("Content-Type"))
+			}
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SweepHandler interface {
+	Sweep(ctx context.Context, req requests.SweepRequest) (*requests.TransferResponse, error)
+}
+
+// HandleSweep moves money between a wallet and its parent or one of its children.
+func HandleSweep(logger *zap.Logger, handler SweepHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.SweepRequest
+		const op = "api/v1/sweeps"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateSweepRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		sweepChan := make(chan *requests.TransferResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			sweep, err := handler.Sweep(c.Request.Context(), req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			sweepChan <- sweep
+		}()
+		select {
+		case sweep := <-sweepChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("transferId", sweep.TransferID), zap.String("amount", sweep.Amount.String()))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(sweep))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, err, http.StatusNotFound, "wallet not found")
+				return
+			}
+			var notRelatedErr requests.WalletsNotRelatedError
+			if errors.As(err, &notRelatedErr) {
+				logError(c, logger, err, http.StatusUnprocessableEntity, "cannot sweep")
+				return
+			}
+			var insufficientFundsErr requests.InsufficientFundsError
+			if errors.As(err, &insufficientFundsErr) {
+				logError(c, logger, err, http.StatusForbidden, "balance cant become negative")
+				return
+			}
+			if errors.Is(err, models.ErrAmountOverflow) {
+				logError(c, logger, err, http.StatusUnprocessableEntity, "amount out of range")
+				return
+			}
+			var walletNotActiveErr requests.WalletNotActiveError
+			if errors.As(err, &walletNotActiveErr) {
+				logError(c, logger, err, http.StatusConflict, "wallet not active")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+func validateSweepRequest(req requests.SweepRequest) error {
+	if req.Amount < 0 {
+		return errors.New("amount must be a positive integer")
+	}
+	// The amount is checked above: zero sweeps the whole available balance.
+	return validateTransferRequest(requests.TransferRequest{FromWalletID: req.FromWalletID, ToWalletID: req.ToWalletID, Amount: 1})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockSweepHandler struct {
+	SweepFunc func(ctx context.Context, req requests.SweepRequest) (*requests.TransferResponse, error)
+}
+
+func (m *mockSweepHandler) Sweep(ctx context.Context, req requests.SweepRequest) (*requests.TransferResponse, error) {
+	if m.SweepFunc != nil {
+		return m.SweepFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func TestHandleSweep(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	const (
+		parentID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+		childID  = "b2c3d4e5-f6a7-8901-2345-67890abcdef1"
+	)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSweep      func(ctx context.Context, req requests.SweepRequest) (*requests.TransferResponse, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Same Wallet",
+			requestBody:    `{"fromWalletId": "` + childID + `", "toWalletId": "` + childID + `"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: cannot transfer to the same wallet"}`,
+		},
+		{
+			name:           "Negative Amount",
+			requestBody:    `{"fromWalletId": "` + childID + `", "toWalletId": "` + parentID + `", "amount": -1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: amount must be a positive integer"}`,
+		},
+		{
+			name:        "Whole Balance",
+			requestBody: `{"fromWalletId": "` + childID + `", "toWalletId": "` + parentID + `"}`,
+			mockSweep: func(ctx context.Context, req requests.SweepRequest) (*requests.TransferResponse, error) {
+				assert.Zero(t, req.Amount)
+				return &requests.TransferResponse{
+					TransferID:   "c3d4e5f6-a7b8-9012-3456-7890abcdef12",
+					FromWalletID: req.FromWalletID,
+					ToWalletID:   req.ToWalletID,
+					Amount:       300,
+					FromBalance:  0,
+					ToBalance:    1300,
+					Currency:     "USD",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"transferId":"c3d4e5f6-a7b8-9012-3456-7890abcdef12","fromWalletId":"b2c3d4e5-f6a7-8901-2345-67890abcdef1","toWalletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","amount":300,"fromBalance":0,"toBalance":1300,"currency":"USD"}}`,
+		},
+		{
+			name:        "Not Parent And Child",
+			requestBody: `{"fromWalletId": "` + childID + `", "toWalletId": "` + parentID + `", "amount": 100}`,
+			mockSweep: func(ctx context.Context, req requests.SweepRequest) (*requests.TransferResponse, error) {
+				return nil, requests.WalletsNotRelatedError{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status":"error","error":"cannot sweep: wallets are not parent and child"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockSweepHandler{
+				SweepFunc: tt.mockSweep,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/sweeps", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleSweep(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
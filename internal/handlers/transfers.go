@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type TransferHandler interface {
+	Transfer(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error)
+}
+
+func HandleTransfer(logger *zap.Logger, handler TransferHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.TransferRequest
+		const op = "api/v1/transfers"
+
+		logger.Info("proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+
+		if err := validateTransferRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		transferChan := make(chan *requests.TransferResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			transfer, err := handler.Transfer(c.Request.Context(), req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			transferChan <- transfer
+		}()
+		select {
+		case transfer := <-transferChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("transferId", transfer.TransferID))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(transfer))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, err, http.StatusNotFound, "wallet not found")
+				return
+			}
+			var insufficientFundsErr requests.InsufficientFundsError
+			if errors.As(err, &insufficientFundsErr) {
+				logError(c, logger, err, http.StatusForbidden, "balance cant become negative")
+				return
+			}
+			var withdrawalLimitErr requests.WithdrawalLimitError
+			if errors.As(err, &withdrawalLimitErr) {
+				logError(c, logger, err, http.StatusForbidden, "withdrawal limit exceeded")
+				return
+			}
+			var currencyMismatchErr requests.CurrencyMismatchError
+			if errors.As(err, &currencyMismatchErr) {
+				logError(c, logger, err, http.StatusUnprocessableEntity, "currency mismatch")
+				return
+			}
+			if errors.Is(err, models.ErrAmountOverflow) {
+				logError(c, logger, err, http.StatusUnprocessableEntity, "amount out of range")
+				return
+			}
+			var walletNotActiveErr requests.WalletNotActiveError
+			if errors.As(err, &walletNotActiveErr) {
+				logError(c, logger, err, http.StatusConflict, "wallet not active")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+	}
+}
+
+func validateTransferRequest(req requests.TransferRequest) error {
+	if req.FromWalletID == "" || req.ToWalletID == "" {
+		return errors.New("empty wallet id")
+	}
+	from, err := uuid.Parse(req.FromWalletID)
+	if err != nil {
+		return errors.New("invalid wallet id format")
+	}
+	to, err := uuid.Parse(req.ToWalletID)
+	if err != nil {
+		return errors.New("invalid wallet id format")
+	}
+	if from == to {
+		return errors.New("cannot transfer to the same wallet")
+	}
+	if req.Amount <= 0 {
+		return errors.New("amount must be a positive integer")
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockTransferHandler struct {
+	TransferFunc func(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error)
+}
+
+func (m *mockTransferHandler) Transfer(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error) {
+	if m.TransferFunc != nil {
+		return m.TransferFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func TestHandleTransfer(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockTransfer   func(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Invalid JSON",
+			requestBody:    "invalid json",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"failed to process request: invalid character 'i' looking for beginning of value"}`,
+		},
+		{
+			name:           "Empty WalletId",
+			requestBody:    `{"fromWalletId": "", "toWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "amount": 100}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: empty wallet id"}`,
+		},
+		{
+			name:           "Invalid WalletId Format",
+			requestBody:    `{"fromWalletId": "invalid-uuid", "toWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "amount": 100}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: invalid wallet id format"}`,
+		},
+		{
+			name:           "Same Wallet",
+			requestBody:    `{"fromWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "toWalletId": "A887E82A-433B-4484-B6EC-820D6451C8BD", "amount": 100}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: cannot transfer to the same wallet"}`,
+		},
+		{
+			name:           "Invalid amount",
+			requestBody:    `{"fromWalletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "toWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "amount": 0}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: amount must be a positive integer"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"fromWalletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "toWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "amount": 100}`,
+			mockTransfer: func(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error) {
+				return &requests.TransferResponse{
+					TransferID:   "0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11",
+					FromWalletID: req.FromWalletID,
+					ToWalletID:   req.ToWalletID,
+					Amount:       req.Amount,
+					FromBalance:  900,
+					ToBalance:    100,
+					Currency:     "USD",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"transferId":"0b0f5d1e-4a4b-4c43-9d4b-6f1f0f6a1c11","fromWalletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","toWalletId":"a887e82a-433b-4484-b6ec-820d6451c8bd","amount":100,"fromBalance":900,"toBalance":100,"currency":"USD"}}`,
+		},
+		{
+			name:        "Insufficient Funds Error",
+			requestBody: `{"fromWalletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "toWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "amount": 100}`,
+			mockTransfer: func(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error) {
+				return nil, requests.InsufficientFundsError{}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"status":"error","error":"balance cant become negative: insufficient funds: 0 left to spend"}`,
+		},
+		{
+			name:        "Currency Mismatch",
+			requestBody: `{"fromWalletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "toWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "amount": 100}`,
+			mockTransfer: func(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error) {
+				return nil, requests.CurrencyMismatchError{WalletCurrency: "EUR", Currency: "USD"}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status":"error","error":"currency mismatch: wallet holds EUR, not USD"}`,
+		},
+		{
+			name:        "Wallet Not Found",
+			requestBody: `{"fromWalletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "toWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "amount": 100}`,
+			mockTransfer: func(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: sql: no rows in result set"}`,
+		},
+		{
+			name:        "Internal Server Error",
+			requestBody: `{"fromWalletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "toWalletId": "a887e82a-433b-4484-b6ec-820d6451c8bd", "amount": 100}`,
+			mockTransfer: func(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error) {
+				return nil, errors.New("some other error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status":"error","error":"internal server error: some other error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockTransferHandler{
+				TransferFunc: tt.mockTransfer,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/transfers", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleTransfer(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
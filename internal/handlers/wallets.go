@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -14,6 +18,8 @@ import (
 
 type WalletBalanceGetter interface {
 	GetWalletBalance(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
+	GetWalletBalanceAt(ctx context.Context, walletID string, asOf time.Time) (*requests.HistoricalBalanceResponse, error)
+	GetWalletTree(ctx context.Context, walletID string) (*requests.WalletTreeResponse, error)
 }
 
 func HandleGetWalletBalance(logger *zap.Logger, balanceGetter WalletBalanceGetter) gin.HandlerFunc {
@@ -27,11 +33,34 @@ func HandleGetWalletBalance(logger *zap.Logger, balanceGetter WalletBalanceGette
 			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
 			return
 		}
+		asOf, err := parseTimeQuery(c, "asOf")
+		if err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+		tree, err := strconv.ParseBool(c.DefaultQuery("tree", "false"))
+		if err != nil {
+			logError(c, logger, errors.New("tree must be true or false"), http.StatusBadRequest, "bad request data")
+			return
+		}
+		if tree && asOf != nil {
+			logError(c, logger, errors.New("tree cant be combined with asOf"), http.StatusBadRequest, "bad request data")
+			return
+		}
 
-		balanceChan := make(chan *requests.WalletBalanceResponse, 1)
+		balanceChan := make(chan interface{}, 1)
 		errChan := make(chan error, 1)
 		go func() {
-			balance, err := balanceGetter.GetWalletBalance(c.Request.Context(), walletID)
+			var balance interface{}
+			var err error
+			switch {
+			case asOf != nil:
+				balance, err = balanceGetter.GetWalletBalanceAt(c.Request.Context(), walletID, *asOf)
+			case tree:
+				balance, err = balanceGetter.GetWalletTree(c.Request.Context(), walletID)
+			default:
+				balance, err = balanceGetter.GetWalletBalance(c.Request.Context(), walletID)
+			}
 			if err != nil {
 				errChan <- err
 				return
@@ -52,6 +81,164 @@ func HandleGetWalletBalance(logger *zap.Logger, balanceGetter WalletBalanceGette
 		}
 	}
 }
+
+type WalletCreator interface {
+	CreateWallet(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error)
+}
+
+func HandleCreateWallet(logger *zap.Logger, creator WalletCreator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.CreateWalletRequest
+		const op = "api/v1/wallets"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		// The body is optional: an empty body opens a wallet with a generated id in the default currency.
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if req.WalletID != "" {
+			if _, err := uuid.Parse(req.WalletID); err != nil {
+				logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "bad request data")
+				return
+			}
+		}
+		if req.Currency != "" {
+			if err := validateCurrency(req.Currency); err != nil {
+				logError(c, logger, err, http.StatusBadRequest, "bad request data")
+				return
+			}
+		}
+		if req.ParentID != "" {
+			if _, err := uuid.Parse(req.ParentID); err != nil {
+				logError(c, logger, errors.New("invalid parent id format"), http.StatusBadRequest, "bad request data")
+				return
+			}
+		}
+		if req.OwnerID != "" {
+			if _, err := uuid.Parse(req.OwnerID); err != nil {
+				logError(c, logger, errors.New("invalid owner id format"), http.StatusBadRequest, "bad request data")
+				return
+			}
+		}
+
+		walletChan := make(chan *requests.WalletBalanceResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			wallet, err := creator.CreateWallet(c.Request.Context(), req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			walletChan <- wallet
+		}()
+		select {
+		case wallet := <-walletChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", wallet.WalletID))
+			c.JSON(http.StatusCreated, requests.WalletOperationResponseOK(wallet))
+		case err := <-errChan:
+			var walletExistsErr requests.WalletExistsError
+			if errors.As(err, &walletExistsErr) {
+				logError(c, logger, err, http.StatusConflict, "wallet exists")
+				return
+			}
+			var ownerNotFoundErr requests.OwnerNotFoundError
+			if errors.As(err, &ownerNotFoundErr) {
+				logError(c, logger, err, http.StatusNotFound, "owner not found")
+				return
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "parent wallet not found")
+				return
+			}
+			var walletNotActiveErr requests.WalletNotActiveError
+			if errors.As(err, &walletNotActiveErr) {
+				logError(c, logger, err, http.StatusConflict, "parent wallet not active")
+				return
+			}
+			var currencyMismatchErr requests.CurrencyMismatchError
+			if errors.As(err, &currencyMismatchErr) {
+				logError(c, logger, err, http.StatusUnprocessableEntity, "currency mismatch")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+	}
+}
+
+type WalletStatusSetter interface {
+	SetWalletStatus(ctx context.Context, walletID, status string) (*requests.WalletBalanceResponse, error)
+}
+
+func HandleSetWalletStatus(logger *zap.Logger, setter WalletStatusSetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.SetWalletStatusRequest
+		const op = "api/v1/admin/wallets/{walletId}/status"
+		walletID := c.Param("walletId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("walletId", walletID))
+
+		if _, err := uuid.Parse(walletID); err != nil {
+			logError(c, logger, errors.New("invalid wallet id format"), http.StatusBadRequest, "invalid walletId format")
+			return
+		}
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		switch req.Status {
+		case models.WalletActive, models.WalletFrozen, models.WalletClosed:
+		default:
+			logError(c, logger, errors.New("status must be ACTIVE, FROZEN or CLOSED"), http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		walletChan := make(chan *requests.WalletBalanceResponse, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			wallet, err := setter.SetWalletStatus(c.Request.Context(), walletID, req.Status)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			walletChan <- wallet
+		}()
+		select {
+		case wallet := <-walletChan:
+			logRequest(c, logger, "request procceeded successfully", zap.String("walletId", walletID), zap.String("status", wallet.Status))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(wallet))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, errors.New("no such a wallet"), http.StatusNotFound, "wallet not found")
+				return
+			}
+			var transitionErr requests.WalletStatusTransitionError
+			if errors.As(err, &transitionErr) {
+				logError(c, logger, err, http.StatusConflict, "bad status change")
+				return
+			}
+			var notEmptyErr requests.WalletNotEmptyError
+			if errors.As(err, &notEmptyErr) {
+				logError(c, logger, err, http.StatusConflict, "bad status change")
+				return
+			}
+			var subWalletsErr requests.SubWalletsNotEmptyError
+			if errors.As(err, &subWalletsErr) {
+				logError(c, logger, err, http.StatusConflict, "bad status change")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+			return
+		}
+	}
+}
+
 func logRequest(c *gin.Context, logger *zap.Logger, message string, fields ...zap.Field) {
 	logger.Info(message, fields...)
 }
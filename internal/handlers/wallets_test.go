@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	requests "github.com/foreground-eclipse/wallet/internal/api"
 	"github.com/gin-gonic/gin"
@@ -16,7 +17,23 @@ import (
 )
 
 type mockWalletBalanceGetter struct {
-	GetWalletBalanceFunc func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
+	GetWalletBalanceFunc   func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
+	GetWalletBalanceAtFunc func(ctx context.Context, walletID string, asOf time.Time) (*requests.HistoricalBalanceResponse, error)
+	GetWalletTreeFunc      func(ctx context.Context, walletID string) (*requests.WalletTreeResponse, error)
+}
+
+func (m *mockWalletBalanceGetter) GetWalletTree(ctx context.Context, walletID string) (*requests.WalletTreeResponse, error) {
+	if m.GetWalletTreeFunc != nil {
+		return m.GetWalletTreeFunc(ctx, walletID)
+	}
+	return nil, nil
+}
+
+func (m *mockWalletBalanceGetter) GetWalletBalanceAt(ctx context.Context, walletID string, asOf time.Time) (*requests.HistoricalBalanceResponse, error) {
+	if m.GetWalletBalanceAtFunc != nil {
+		return m.GetWalletBalanceAtFunc(ctx, walletID, asOf)
+	}
+	return nil, nil
 }
 
 func (m *mockWalletBalanceGetter) GetWalletBalance(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
@@ -32,18 +49,16 @@ func (m *mockWalletBalanceGetter) GetWalletBalance(ctx context.Context, walletID
 
 func TestHandleGetWalletBalance(t *testing.T) {
 	logger, _ := zap.NewProduction()
-	defer func(logger *zap.Logger) {
-		err := logger.Sync()
-		if err != nil {
-			panic(err)
-		}
-	}(logger)
+	defer logger.Sync()
 	tests := []struct {
-		name                 string
-		walletId             string
-		mockGetWalletBalance func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
-		expectedStatus       int
-		expectedBody         string
+		name                   string
+		walletId               string
+		query                  string
+		mockGetWalletBalance   func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error)
+		mockGetWalletBalanceAt func(ctx context.Context, walletID string, asOf time.Time) (*requests.HistoricalBalanceResponse, error)
+		mockGetWalletTree      func(ctx context.Context, walletID string) (*requests.WalletTreeResponse, error)
+		expectedStatus         int
+		expectedBody           string
 	}{
 		{
 			name:           "Invalid UUID",
@@ -56,12 +71,80 @@ func TestHandleGetWalletBalance(t *testing.T) {
 			walletId: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
 			mockGetWalletBalance: func(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
 				return &requests.WalletBalanceResponse{
-					WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
-					Balance:  6000,
+					WalletID:  "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+					Balance:   6000,
+					Available: 5500,
+					Currency:  "USD",
+					Status:    "ACTIVE",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":6000,"available":5500,"currency":"USD","status":"ACTIVE"}}`,
+		},
+		{
+			name:     "As Of",
+			walletId: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+			query:    "?asOf=2024-05-01T00:00:00%2B03:00",
+			mockGetWalletBalanceAt: func(ctx context.Context, walletID string, asOf time.Time) (*requests.HistoricalBalanceResponse, error) {
+				assert.True(t, asOf.Equal(time.Date(2024, 4, 30, 21, 0, 0, 0, time.UTC)))
+				return &requests.HistoricalBalanceResponse{
+					WalletID: walletID,
+					Balance:  4200,
+					Currency: "USD",
+					AsOf:     asOf.UTC(),
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":4200,"currency":"USD","asOf":"2024-04-30T21:00:00Z"}}`,
+		},
+		{
+			name:           "Invalid As Of",
+			walletId:       "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+			query:          "?asOf=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: asOf must be an RFC3339 timestamp"}`,
+		},
+		{
+			name:     "Tree",
+			walletId: "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+			query:    "?tree=true",
+			mockGetWalletTree: func(ctx context.Context, walletID string) (*requests.WalletTreeResponse, error) {
+				parentID := walletID
+				child := &requests.WalletTreeResponse{
+					WalletBalanceResponse: requests.WalletBalanceResponse{
+						WalletID:  "b2c3d4e5-f6a7-8901-2345-67890abcdef1",
+						Balance:   300,
+						Available: 200,
+						Currency:  "USD",
+						Status:    "ACTIVE",
+						ParentID:  &parentID,
+					},
+					TotalBalance:   300,
+					TotalAvailable: 200,
+					Children:       []*requests.WalletTreeResponse{},
+				}
+				return &requests.WalletTreeResponse{
+					WalletBalanceResponse: requests.WalletBalanceResponse{
+						WalletID:  walletID,
+						Balance:   1000,
+						Available: 1000,
+						Currency:  "USD",
+						Status:    "ACTIVE",
+					},
+					TotalBalance:   1300,
+					TotalAvailable: 1200,
+					Children:       []*requests.WalletTreeResponse{child},
 				}, nil
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":6000}}`,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":1000,"available":1000,"currency":"USD","status":"ACTIVE","totalBalance":1300,"totalAvailable":1200,"children":[{"walletId":"b2c3d4e5-f6a7-8901-2345-67890abcdef1","balance":300,"available":200,"currency":"USD","status":"ACTIVE","parentId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","totalBalance":300,"totalAvailable":200,"children":[]}]}}`,
+		},
+		{
+			name:           "Tree As Of",
+			walletId:       "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+			query:          "?tree=true&asOf=2024-05-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: tree cant be combined with asOf"}`,
 		},
 		{
 			name:     "Wallet Not Found",
@@ -77,12 +160,14 @@ func TestHandleGetWalletBalance(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockHandler := &mockWalletBalanceGetter{
-				GetWalletBalanceFunc: tt.mockGetWalletBalance,
+				GetWalletBalanceFunc:   tt.mockGetWalletBalance,
+				GetWalletBalanceAtFunc: tt.mockGetWalletBalanceAt,
+				GetWalletTreeFunc:      tt.mockGetWalletTree,
 			}
 			recorder := httptest.NewRecorder()
 			gin.SetMode(gin.TestMode)
 			c, _ := gin.CreateTestContext(recorder)
-			c.Request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", tt.walletId), bytes.NewBufferString(""))
+			c.Request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s%s", tt.walletId, tt.query), bytes.NewBufferString(""))
 			c.Params = []gin.Param{{Key: "walletId", Value: tt.walletId}}
 			c.Request.Header.Set("Content-Type", "application/json")
 
@@ -95,3 +180,193 @@ func TestHandleGetWalletBalance(t *testing.T) {
 		})
 	}
 }
+
+type mockWalletLifecycle struct {
+	CreateWalletFunc    func(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error)
+	SetWalletStatusFunc func(ctx context.Context, walletID, status string) (*requests.WalletBalanceResponse, error)
+}
+
+func (m *mockWalletLifecycle) CreateWallet(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error) {
+	if m.CreateWalletFunc != nil {
+		return m.CreateWalletFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *mockWalletLifecycle) SetWalletStatus(ctx context.Context, walletID, status string) (*requests.WalletBalanceResponse, error) {
+	if m.SetWalletStatusFunc != nil {
+		return m.SetWalletStatusFunc(ctx, walletID, status)
+	}
+	return nil, nil
+}
+
+func TestHandleCreateWallet(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+	tests := []struct {
+		name             string
+		requestBody      string
+		mockCreateWallet func(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error)
+		expectedStatus   int
+		expectedBody     string
+	}{
+		{
+			name:        "Empty Body",
+			requestBody: "",
+			mockCreateWallet: func(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error) {
+				assert.Empty(t, req.WalletID)
+				return &requests.WalletBalanceResponse{WalletID: "a1b2c3d4-e5f6-7890-1234-567890abcdef", Currency: "USD", Status: "ACTIVE"}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":0,"available":0,"currency":"USD","status":"ACTIVE"}}`,
+		},
+		{
+			name:           "Invalid Wallet Id",
+			requestBody:    `{"walletId": "invalid-uuid"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: invalid wallet id format"}`,
+		},
+		{
+			name:           "Invalid Currency",
+			requestBody:    `{"currency": "usd"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: currency must be an ISO 4217 code"}`,
+		},
+		{
+			name:        "Sub-wallet",
+			requestBody: `{"parentId": "a1b2c3d4-e5f6-7890-1234-567890abcdef"}`,
+			mockCreateWallet: func(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error) {
+				return &requests.WalletBalanceResponse{WalletID: "b2c3d4e5-f6a7-8901-2345-67890abcdef1", Currency: "USD", Status: "ACTIVE", ParentID: &req.ParentID}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":{"walletId":"b2c3d4e5-f6a7-8901-2345-67890abcdef1","balance":0,"available":0,"currency":"USD","status":"ACTIVE","parentId":"a1b2c3d4-e5f6-7890-1234-567890abcdef"}}`,
+		},
+		{
+			name:        "Parent Not Found",
+			requestBody: `{"parentId": "a1b2c3d4-e5f6-7890-1234-567890abcdef"}`,
+			mockCreateWallet: func(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"parent wallet not found: no such a wallet"}`,
+		},
+		{
+			name:        "Owner Not Found",
+			requestBody: `{"ownerId": "b2c3d4e5-f6a7-8901-2345-67890abcdef1"}`,
+			mockCreateWallet: func(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error) {
+				return nil, requests.OwnerNotFoundError{OwnerID: req.OwnerID}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"owner not found: owner with id b2c3d4e5-f6a7-8901-2345-67890abcdef1 not found"}`,
+		},
+		{
+			name:        "Wallet Exists",
+			requestBody: `{"walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "currency": "EUR"}`,
+			mockCreateWallet: func(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error) {
+				return nil, requests.WalletExistsError{}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"wallet exists: wallet already exists"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWalletLifecycle{
+				CreateWalletFunc: tt.mockCreateWallet,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/wallets", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleCreateWallet(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleSetWalletStatus(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+	const walletID = "a1b2c3d4-e5f6-7890-1234-567890abcdef"
+	tests := []struct {
+		name                string
+		requestBody         string
+		mockSetWalletStatus func(ctx context.Context, walletID, status string) (*requests.WalletBalanceResponse, error)
+		expectedStatus      int
+		expectedBody        string
+	}{
+		{
+			name:           "Unknown Status",
+			requestBody:    `{"status": "frozen"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: status must be ACTIVE, FROZEN or CLOSED"}`,
+		},
+		{
+			name:        "Freeze",
+			requestBody: `{"status": "FROZEN"}`,
+			mockSetWalletStatus: func(ctx context.Context, id, status string) (*requests.WalletBalanceResponse, error) {
+				return &requests.WalletBalanceResponse{WalletID: id, Balance: 100, Available: 100, Currency: "USD", Status: status}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","balance":100,"available":100,"currency":"USD","status":"FROZEN"}}`,
+		},
+		{
+			name:        "Reopen Closed",
+			requestBody: `{"status": "ACTIVE"}`,
+			mockSetWalletStatus: func(ctx context.Context, id, status string) (*requests.WalletBalanceResponse, error) {
+				return nil, requests.WalletStatusTransitionError{From: "CLOSED", To: status}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"bad status change: wallet cannot go from CLOSED to ACTIVE"}`,
+		},
+		{
+			name:        "Close Non Empty",
+			requestBody: `{"status": "CLOSED"}`,
+			mockSetWalletStatus: func(ctx context.Context, id, status string) (*requests.WalletBalanceResponse, error) {
+				return nil, requests.WalletNotEmptyError{Balance: 100}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"bad status change: wallet still has a balance of 100 and 0 on hold"}`,
+		},
+		{
+			name:        "Close Parent Of Non Empty Sub-wallets",
+			requestBody: `{"status": "CLOSED"}`,
+			mockSetWalletStatus: func(ctx context.Context, id, status string) (*requests.WalletBalanceResponse, error) {
+				return nil, requests.SubWalletsNotEmptyError{Count: 2}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"bad status change: 2 sub-wallets are not empty"}`,
+		},
+		{
+			name:        "Wallet Not Found",
+			requestBody: `{"status": "CLOSED"}`,
+			mockSetWalletStatus: func(ctx context.Context, id, status string) (*requests.WalletBalanceResponse, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"wallet not found: no such a wallet"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWalletLifecycle{
+				SetWalletStatusFunc: tt.mockSetWalletStatus,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/admin/wallets/%s/status", walletID), bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "walletId", Value: walletID}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleSetWalletStatus(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxWebhookURLLength    = 2048
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 256
+)
+
+type WebhookHandler interface {
+	CreateWebhook(ctx context.Context, req requests.CreateWebhookRequest) (*models.WebhookSubscription, error)
+	GetWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, webhookID string, req requests.UpdateWebhookRequest) (*models.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error)
+}
+
+type WebhookDeliveryHandler interface {
+	ListWebhookDeliveries(ctx context.Context, webhookID string, filter requests.WebhookDeliveriesFilter) ([]models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+	ListWebhookAttempts(ctx context.Context, deliveryID string) ([]models.WebhookAttempt, error)
+	RedeliverWebhook(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+}
+
+// HandleCreateWebhook subscribes a URL to events. The response is the only one carrying the signing secret.
+func HandleCreateWebhook(logger *zap.Logger, handler WebhookHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.CreateWebhookRequest
+		const op = "api/v1/webhooks"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateCreateWebhookRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		runWebhookAction(c, logger, http.StatusCreated, true, func(ctx context.Context) (*models.WebhookSubscription, error) {
+			return handler.CreateWebhook(ctx, req)
+		})
+	}
+}
+
+func HandleListWebhooks(logger *zap.Logger, handler WebhookHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/webhooks"
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op))
+
+		webhooksChan := make(chan []models.WebhookSubscription, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			subscriptions, err := handler.ListWebhooks(c.Request.Context())
+			if err != nil {
+				errChan <- err
+				return
+			}
+			webhooksChan <- subscriptions
+		}()
+		select {
+		case subscriptions := <-webhooksChan:
+			resp := make([]requests.WebhookResponse, len(subscriptions))
+			for i := range subscriptions {
+				resp[i] = webhookResponse(&subscriptions[i], false)
+			}
+			logRequest(c, logger, "request procceeded successfully", zap.Int("webhooks", len(resp)))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(resp))
+		case err := <-errChan:
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+func HandleGetWebhook(logger *zap.Logger, handler WebhookHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/webhooks/{webhookId}"
+		webhookID := c.Param("webhookId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("webhookId", webhookID))
+
+		if _, err := uuid.Parse(webhookID); err != nil {
+			logError(c, logger, errors.New("invalid webhook id format"), http.StatusBadRequest, "invalid webhookId format")
+			return
+		}
+		runWebhookAction(c, logger, http.StatusOK, false, func(ctx context.Context) (*models.WebhookSubscription, error) {
+			return handler.GetWebhook(ctx, webhookID)
+		})
+	}
+}
+
+// HandleUpdateWebhook changes the URL or event types of a subscription, or pauses and resumes it.
+func HandleUpdateWebhook(logger *zap.Logger, handler WebhookHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.UpdateWebhookRequest
+		const op = "api/v1/webhooks/{webhookId}"
+		webhookID := c.Param("webhookId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("webhookId", webhookID))
+
+		if _, err := uuid.Parse(webhookID); err != nil {
+			logError(c, logger, errors.New("invalid webhook id format"), http.StatusBadRequest, "invalid webhookId format")
+			return
+		}
+		if err := c.BindJSON(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				logError(c, logger, errors.New("empty json"), http.StatusBadRequest, "failed to process request")
+				return
+			}
+			logError(c, logger, err, http.StatusBadRequest, "failed to process request")
+			return
+		}
+		if err := validateUpdateWebhookRequest(req); err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		runWebhookAction(c, logger, http.StatusOK, false, func(ctx context.Context) (*models.WebhookSubscription, error) {
+			return handler.UpdateWebhook(ctx, webhookID, req)
+		})
+	}
+}
+
+// HandleDeleteWebhook disables a subscription. Its delivery log stays readable.
+func HandleDeleteWebhook(logger *zap.Logger, handler WebhookHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/webhooks/{webhookId}"
+		webhookID := c.Param("webhookId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("webhookId", webhookID))
+
+		if _, err := uuid.Parse(webhookID); err != nil {
+			logError(c, logger, errors.New("invalid webhook id format"), http.StatusBadRequest, "invalid webhookId format")
+			return
+		}
+		runWebhookAction(c, logger, http.StatusOK, false, func(ctx context.Context) (*models.WebhookSubscription, error) {
+			return handler.DeleteWebhook(ctx, webhookID)
+		})
+	}
+}
+
+// HandleListWebhookDeliveries returns the delivery log of a subscription, newest first.
+func HandleListWebhookDeliveries(logger *zap.Logger, handler WebhookDeliveryHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/webhooks/{webhookId}/deliveries"
+		webhookID := c.Param("webhookId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("webhookId", webhookID))
+
+		if _, err := uuid.Parse(webhookID); err != nil {
+			logError(c, logger, errors.New("invalid webhook id format"), http.StatusBadRequest, "invalid webhookId format")
+			return
+		}
+		filter, err := parseWebhookDeliveriesFilter(c)
+		if err != nil {
+			logError(c, logger, err, http.StatusBadRequest, "bad request data")
+			return
+		}
+
+		deliveriesChan := make(chan []models.WebhookDelivery, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			deliveries, err := handler.ListWebhookDeliveries(c.Request.Context(), webhookID, filter)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			deliveriesChan <- deliveries
+		}()
+		select {
+		case deliveries := <-deliveriesChan:
+			resp := make([]requests.WebhookDeliveryResponse, len(deliveries))
+			for i := range deliveries {
+				resp[i] = webhookDeliveryResponse(&deliveries[i], nil)
+			}
+			logRequest(c, logger, "request procceeded successfully", zap.String("webhookId", webhookID), zap.Int("deliveries", len(resp)))
+			c.JSON(http.StatusOK, requests.WalletOperationResponseOK(resp))
+		case err := <-errChan:
+			if errors.Is(err, sql.ErrNoRows) {
+				logError(c, logger, err, http.StatusNotFound, "webhook not found")
+				return
+			}
+			logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+		}
+	}
+}
+
+// HandleGetWebhookDelivery returns a delivery with what was sent and every attempt at sending it.
+func HandleGetWebhookDelivery(logger *zap.Logger, handler WebhookDeliveryHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/webhooks/{webhookId}/deliveries/{deliveryId}"
+		webhookID, deliveryID := c.Param("webhookId"), c.Param("deliveryId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("webhookId", webhookID), zap.String("deliveryId", deliveryID))
+
+		if !validDeliveryPath(c, logger, webhookID, deliveryID) {
+			return
+		}
+		runDeliveryAction(c, logger, func(ctx context.Context) (*models.WebhookDelivery, error) {
+			return handler.GetWebhookDelivery(ctx, webhookID, deliveryID)
+		}, handler)
+	}
+}
+
+// HandleRedeliverWebhook sends a delivery again with a fresh set of attempts, including one that went DEAD.
+func HandleRedeliverWebhook(logger *zap.Logger, handler WebhookDeliveryHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const op = "api/v1/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver"
+		webhookID, deliveryID := c.Param("webhookId"), c.Param("deliveryId")
+
+		logRequest(c, logger, "proceeding new request", zap.String("op", op), zap.String("webhookId", webhookID), zap.String("deliveryId", deliveryID))
+
+		if !validDeliveryPath(c, logger, webhookID, deliveryID) {
+			return
+		}
+		runDeliveryAction(c, logger, func(ctx context.Context) (*models.WebhookDelivery, error) {
+			return handler.RedeliverWebhook(ctx, webhookID, deliveryID)
+		}, nil)
+	}
+}
+
+func validDeliveryPath(c *gin.Context, logger *zap.Logger, webhookID, deliveryID string) bool {
+	if _, err := uuid.Parse(webhookID); err != nil {
+		logError(c, logger, errors.New("invalid webhook id format"), http.StatusBadRequest, "invalid webhookId format")
+		return false
+	}
+	if _, err := uuid.Parse(deliveryID); err != nil {
+		logError(c, logger, errors.New("invalid delivery id format"), http.StatusBadRequest, "invalid deliveryId format")
+		return false
+	}
+	return true
+}
+
+func runWebhookAction(c *gin.Context, logger *zap.Logger, status int, withSecret bool, action func(ctx context.Context) (*models.WebhookSubscription, error)) {
+	webhookChan := make(chan *models.WebhookSubscription, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		subscription, err := action(c.Request.Context())
+		if err != nil {
+			errChan <- err
+			return
+		}
+		webhookChan <- subscription
+	}()
+	select {
+	case subscription := <-webhookChan:
+		logRequest(c, logger, "request procceeded successfully", zap.String("webhookId", subscription.ID), zap.String("status", subscription.Status))
+		c.JSON(status, requests.WalletOperationResponseOK(webhookResponse(subscription, withSecret)))
+	case err := <-errChan:
+		if errors.Is(err, sql.ErrNoRows) {
+			logError(c, logger, err, http.StatusNotFound, "not found")
+			return
+		}
+		var ownerNotFoundErr requests.OwnerNotFoundError
+		if errors.As(err, &ownerNotFoundErr) {
+			logError(c, logger, err, http.StatusNotFound, "owner not found")
+			return
+		}
+		var webhookDisabledErr requests.WebhookDisabledError
+		if errors.As(err, &webhookDisabledErr) {
+			logError(c, logger, err, http.StatusConflict, "webhook disabled")
+			return
+		}
+		logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// runDeliveryAction responds with the delivery action returns, with its attempts when attempts is set.
+func runDeliveryAction(c *gin.Context, logger *zap.Logger, action func(ctx context.Context) (*models.WebhookDelivery, error), attempts WebhookDeliveryHandler) {
+	respChan := make(chan requests.WebhookDeliveryResponse, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		delivery, err := action(c.Request.Context())
+		if err != nil {
+			errChan <- err
+			return
+		}
+		var log []models.WebhookAttempt
+		if attempts != nil {
+			if log, err = attempts.ListWebhookAttempts(c.Request.Context(), delivery.ID); err != nil {
+				errChan <- err
+				return
+			}
+		}
+		resp := webhookDeliveryResponse(delivery, log)
+		resp.Body = delivery.Body
+		respChan <- resp
+	}()
+	select {
+	case resp := <-respChan:
+		logRequest(c, logger, "request procceeded successfully", zap.String("deliveryId", resp.ID), zap.String("status", resp.Status))
+		c.JSON(http.StatusOK, requests.WalletOperationResponseOK(resp))
+	case err := <-errChan:
+		if errors.Is(err, sql.ErrNoRows) {
+			logError(c, logger, err, http.StatusNotFound, "not found")
+			return
+		}
+		var webhookDisabledErr requests.WebhookDisabledError
+		if errors.As(err, &webhookDisabledErr) {
+			logError(c, logger, err, http.StatusConflict, "webhook disabled")
+			return
+		}
+		var deliveryPendingErr requests.DeliveryPendingError
+		if errors.As(err, &deliveryPendingErr) {
+			logError(c, logger, err, http.StatusConflict, "delivery pending")
+			return
+		}
+		logError(c, logger, err, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func webhookResponse(subscription *models.WebhookSubscription, withSecret bool) requests.WebhookResponse {
+	resp := requests.WebhookResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		WalletID:   subscription.WalletID,
+		OwnerID:    subscription.OwnerID,
+		Status:     subscription.Status,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+	if withSecret {
+		resp.Secret = subscription.Secret
+	}
+	return resp
+}
+
+func webhookDeliveryResponse(delivery *models.WebhookDelivery, attempts []models.WebhookAttempt) requests.WebhookDeliveryResponse {
+	resp := requests.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		WalletID:       delivery.WalletID,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == models.DeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		resp.NextAttemptAt = &nextAttemptAt
+	}
+	for _, attempt := range attempts {
+		resp.AttemptLog = append(resp.AttemptLog, requests.WebhookAttemptResponse{
+			StatusCode:   attempt.StatusCode,
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			DurationMs:   attempt.DurationMs,
+			CreatedAt:    attempt.CreatedAt,
+		})
+	}
+	return resp
+}
+
+func validateCreateWebhookRequest(req requests.CreateWebhookRequest) error {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return err
+	}
+	if err := validateEventTypes(req.EventTypes); err != nil {
+		return err
+	}
+	if req.WalletID != nil && req.OwnerID != nil {
+		return errors.New("walletId and ownerId cannot both be set")
+	}
+	if req.WalletID != nil {
+		if _, err := uuid.Parse(*req.WalletID); err != nil {
+			return errors.New("invalid wallet id format")
+		}
+	}
+	if req.OwnerID != nil {
+		if _, err := uuid.Parse(*req.OwnerID); err != nil {
+			return errors.New("invalid owner id format")
+		}
+	}
+	if req.Secret != "" && (len(req.Secret) < minWebhookSecretLength || len(req.Secret) > maxWebhookSecretLength) {
+		return fmt.Errorf("secret must be between %d and %d characters", minWebhookSecretLength, maxWebhookSecretLength)
+	}
+	return nil
+}
+
+func validateUpdateWebhookRequest(req requests.UpdateWebhookRequest) error {
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return err
+		}
+	}
+	if req.EventTypes != nil {
+		if err := validateEventTypes(*req.EventTypes); err != nil {
+			return err
+		}
+	}
+	if req.Status != nil && *req.Status != models.WebhookActive && *req.Status != models.WebhookPaused {
+		return errors.New("status must be ACTIVE or PAUSED")
+	}
+	return nil
+}
+
+func validateWebhookURL(raw string) error {
+	if len(raw) > maxWebhookURLLength {
+		return fmt.Errorf("url must be at most %d characters", maxWebhookURLLength)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+func validateEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return errors.New("eventTypes must not be empty")
+	}
+	for _, eventType := range eventTypes {
+		known := false
+		for _, knownType := range requests.EventTypes {
+			known = known || eventType == knownType
+		}
+		if !known {
+			return fmt.Errorf("unknown event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+func parseWebhookDeliveriesFilter(c *gin.Context) (requests.WebhookDeliveriesFilter, error) {
+	filter := requests.WebhookDeliveriesFilter{
+		Status: c.Query("status"),
+		Limit:  requests.DefaultDeliveriesLimit,
+	}
+	switch filter.Status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return filter, errors.New("status must be PENDING, DELIVERED or DEAD")
+	}
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit <= 0 || filter.Limit > requests.MaxDeliveriesLimit {
+			return filter, errors.New("limit must be an integer between 1 and " + strconv.Itoa(requests.MaxDeliveriesLimit))
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockWebhookHandler struct {
+	CreateWebhookFunc         func(ctx context.Context, req requests.CreateWebhookRequest) (*models.WebhookSubscription, error)
+	GetWebhookFunc            func(ctx context.Context, webhookID string) (*models.WebhookSubscription, error)
+	ListWebhooksFunc          func(ctx context.Context) ([]models.WebhookSubscription, error)
+	UpdateWebhookFunc         func(ctx context.Context, webhookID string, req requests.UpdateWebhookRequest) (*models.WebhookSubscription, error)
+	DeleteWebhookFunc         func(ctx context.Context, webhookID string) (*models.WebhookSubscription, error)
+	ListWebhookDeliveriesFunc func(ctx context.Context, webhookID string, filter requests.WebhookDeliveriesFilter) ([]models.WebhookDelivery, error)
+	GetWebhookDeliveryFunc    func(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+	ListWebhookAttemptsFunc   func(ctx context.Context, deliveryID string) ([]models.WebhookAttempt, error)
+	RedeliverWebhookFunc      func(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+}
+
+func (m *mockWebhookHandler) CreateWebhook(ctx context.Context, req requests.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	return m.CreateWebhookFunc(ctx, req)
+}
+
+func (m *mockWebhookHandler) GetWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error) {
+	return m.GetWebhookFunc(ctx, webhookID)
+}
+
+func (m *mockWebhookHandler) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return m.ListWebhooksFunc(ctx)
+}
+
+func (m *mockWebhookHandler) UpdateWebhook(ctx context.Context, webhookID string, req requests.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	return m.UpdateWebhookFunc(ctx, webhookID, req)
+}
+
+func (m *mockWebhookHandler) DeleteWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error) {
+	return m.DeleteWebhookFunc(ctx, webhookID)
+}
+
+func (m *mockWebhookHandler) ListWebhookDeliveries(ctx context.Context, webhookID string, filter requests.WebhookDeliveriesFilter) ([]models.WebhookDelivery, error) {
+	return m.ListWebhookDeliveriesFunc(ctx, webhookID, filter)
+}
+
+func (m *mockWebhookHandler) GetWebhookDelivery(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	return m.GetWebhookDeliveryFunc(ctx, webhookID, deliveryID)
+}
+
+func (m *mockWebhookHandler) ListWebhookAttempts(ctx context.Context, deliveryID string) ([]models.WebhookAttempt, error) {
+	return m.ListWebhookAttemptsFunc(ctx, deliveryID)
+}
+
+func (m *mockWebhookHandler) RedeliverWebhook(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	return m.RedeliverWebhookFunc(ctx, webhookID, deliveryID)
+}
+
+const (
+	testWebhookID  = "6f1c2a7e-3b0d-4c55-8e0e-2f5b9c1d7a01"
+	testDeliveryID = "9a8b7c6d-5e4f-4a3b-9c2d-1e0f9a8b7c6d"
+)
+
+func testWebhook() *models.WebhookSubscription {
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	return &models.WebhookSubscription{
+		ID:         testWebhookID,
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{requests.EventOperationApplied},
+		Secret:     "0123456789abcdef",
+		Status:     models.WebhookActive,
+		CreatedAt:  created,
+		UpdatedAt:  created,
+	}
+}
+
+func testDelivery() *models.WebhookDelivery {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	lastError := "receiver responded 503 Service Unavailable"
+	statusCode := http.StatusServiceUnavailable
+	return &models.WebhookDelivery{
+		ID:             testDeliveryID,
+		SubscriptionID: testWebhookID,
+		EventID:        "1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a",
+		EventType:      requests.EventOperationApplied,
+		WalletID:       "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+		Body:           `{"id":"1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a"}`,
+		Status:         models.DeliveryDead,
+		Attempts:       10,
+		NextAttemptAt:  created.Add(time.Hour),
+		LastStatusCode: &statusCode,
+		LastError:      &lastError,
+		CreatedAt:      created,
+		UpdatedAt:      created.Add(time.Hour),
+	}
+}
+
+const testWebhookJSON = `{"id":"6f1c2a7e-3b0d-4c55-8e0e-2f5b9c1d7a01","url":"https://example.com/hooks","eventTypes":["OperationApplied"],` +
+	`"status":"ACTIVE","createdAt":"2024-05-01T09:00:00Z","updatedAt":"2024-05-01T09:00:00Z"}`
+
+const testDeliveryJSON = `{"id":"9a8b7c6d-5e4f-4a3b-9c2d-1e0f9a8b7c6d","webhookId":"6f1c2a7e-3b0d-4c55-8e0e-2f5b9c1d7a01",` +
+	`"eventId":"1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a","eventType":"OperationApplied","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef",` +
+	`"status":"DEAD","attempts":10,"lastStatusCode":503,"lastError":"receiver responded 503 Service Unavailable","createdAt":"2024-05-01T10:00:00Z"}`
+
+func TestHandleCreateWebhook(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name              string
+		requestBody       string
+		mockCreateWebhook func(ctx context.Context, req requests.CreateWebhookRequest) (*models.WebhookSubscription, error)
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:           "Relative URL",
+			requestBody:    `{"url": "/hooks", "eventTypes": ["OperationApplied"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: url must be an absolute http or https URL"}`,
+		},
+		{
+			name:           "Unknown Event Type",
+			requestBody:    `{"url": "https://example.com/hooks", "eventTypes": ["WalletDeleted"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: unknown event type \"WalletDeleted\""}`,
+		},
+		{
+			name:           "No Event Types",
+			requestBody:    `{"url": "https://example.com/hooks", "eventTypes": []}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: eventTypes must not be empty"}`,
+		},
+		{
+			name:           "Short Secret",
+			requestBody:    `{"url": "https://example.com/hooks", "eventTypes": ["OperationApplied"], "secret": "hunter2"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: secret must be between 16 and 256 characters"}`,
+		},
+		{
+			name:           "Wallet And Owner",
+			requestBody:    `{"url": "https://example.com/hooks", "eventTypes": ["OperationApplied"], "walletId": "a1b2c3d4-e5f6-7890-1234-567890abcdef", "ownerId": "a1b2c3d4-e5f6-7890-1234-567890abcdef"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: walletId and ownerId cannot both be set"}`,
+		},
+		{
+			name:        "Success",
+			requestBody: `{"url": "https://example.com/hooks", "eventTypes": ["OperationApplied"]}`,
+			mockCreateWebhook: func(ctx context.Context, req requests.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+				assert.Empty(t, req.Secret)
+				return testWebhook(), nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"OK","data":` + testWebhookJSON[:len(testWebhookJSON)-1] + `,"secret":"0123456789abcdef"}}`,
+		},
+		{
+			name:        "Owner Not Found",
+			requestBody: `{"url": "https://example.com/hooks", "eventTypes": ["OperationApplied"], "ownerId": "a1b2c3d4-e5f6-7890-1234-567890abcdef"}`,
+			mockCreateWebhook: func(ctx context.Context, req requests.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+				return nil, requests.OwnerNotFoundError{OwnerID: *req.OwnerID}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"owner not found: owner with id a1b2c3d4-e5f6-7890-1234-567890abcdef not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWebhookHandler{
+				CreateWebhookFunc: tt.mockCreateWebhook,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewBufferString(tt.requestBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleCreateWebhook(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleUpdateWebhook(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name              string
+		requestBody       string
+		mockUpdateWebhook func(ctx context.Context, webhookID string, req requests.UpdateWebhookRequest) (*models.WebhookSubscription, error)
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:           "Bad Status",
+			requestBody:    `{"status": "DISABLED"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: status must be ACTIVE or PAUSED"}`,
+		},
+		{
+			name:        "Pause",
+			requestBody: `{"status": "PAUSED"}`,
+			mockUpdateWebhook: func(ctx context.Context, webhookID string, req requests.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+				webhook := testWebhook()
+				webhook.Status = *req.Status
+				return webhook, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"status":"OK","data":{"id":"6f1c2a7e-3b0d-4c55-8e0e-2f5b9c1d7a01","url":"https://example.com/hooks","eventTypes":["OperationApplied"],` +
+				`"status":"PAUSED","createdAt":"2024-05-01T09:00:00Z","updatedAt":"2024-05-01T09:00:00Z"}}`,
+		},
+		{
+			name:        "Disabled",
+			requestBody: `{"url": "https://example.com/other"}`,
+			mockUpdateWebhook: func(ctx context.Context, webhookID string, req requests.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+				return nil, requests.WebhookDisabledError{}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"webhook disabled: webhook subscription is disabled"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWebhookHandler{
+				UpdateWebhookFunc: tt.mockUpdateWebhook,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPatch, "/api/v1/webhooks/"+testWebhookID, bytes.NewBufferString(tt.requestBody))
+			c.Params = []gin.Param{{Key: "webhookId", Value: testWebhookID}}
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleUpdateWebhook(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleListWebhookDeliveries(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name                      string
+		webhookID                 string
+		query                     string
+		mockListWebhookDeliveries func(ctx context.Context, webhookID string, filter requests.WebhookDeliveriesFilter) ([]models.WebhookDelivery, error)
+		expectedStatus            int
+		expectedBody              string
+	}{
+		{
+			name:           "Invalid Webhook ID",
+			webhookID:      "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid webhookId format: invalid webhook id format"}`,
+		},
+		{
+			name:           "Bad Status",
+			webhookID:      testWebhookID,
+			query:          "?status=FAILED",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: status must be PENDING, DELIVERED or DEAD"}`,
+		},
+		{
+			name:           "Limit Too Large",
+			webhookID:      testWebhookID,
+			query:          "?limit=501",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"bad request data: limit must be an integer between 1 and 500"}`,
+		},
+		{
+			name:      "Dead Letters",
+			webhookID: testWebhookID,
+			query:     "?status=DEAD&limit=10",
+			mockListWebhookDeliveries: func(ctx context.Context, webhookID string, filter requests.WebhookDeliveriesFilter) ([]models.WebhookDelivery, error) {
+				assert.Equal(t, requests.WebhookDeliveriesFilter{Status: models.DeliveryDead, Limit: 10}, filter)
+				return []models.WebhookDelivery{*testDelivery()}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"OK","data":[` + testDeliveryJSON + `]}`,
+		},
+		{
+			name:      "Webhook Not Found",
+			webhookID: testWebhookID,
+			mockListWebhookDeliveries: func(ctx context.Context, webhookID string, filter requests.WebhookDeliveriesFilter) ([]models.WebhookDelivery, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"webhook not found: sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWebhookHandler{
+				ListWebhookDeliveriesFunc: tt.mockListWebhookDeliveries,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/webhooks/"+tt.webhookID+"/deliveries"+tt.query, nil)
+			c.Params = []gin.Param{{Key: "webhookId", Value: tt.webhookID}}
+
+			HandleListWebhookDeliveries(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleGetWebhookDelivery(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	mockHandler := &mockWebhookHandler{
+		GetWebhookDeliveryFunc: func(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+			return testDelivery(), nil
+		},
+		ListWebhookAttemptsFunc: func(ctx context.Context, deliveryID string) ([]models.WebhookAttempt, error) {
+			statusCode := http.StatusServiceUnavailable
+			return []models.WebhookAttempt{
+				{ID: "a1", DeliveryID: deliveryID, StatusCode: &statusCode, DurationMs: 12, CreatedAt: time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC)},
+			}, nil
+		},
+	}
+	recorder := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/webhooks/"+testWebhookID+"/deliveries/"+testDeliveryID, nil)
+	c.Params = []gin.Param{{Key: "webhookId", Value: testWebhookID}, {Key: "deliveryId", Value: testDeliveryID}}
+
+	HandleGetWebhookDelivery(logger, mockHandler)(c)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"OK","data":`+testDeliveryJSON[:len(testDeliveryJSON)-1]+
+		`,"body":"{\"id\":\"1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a\"}","attemptLog":[{"statusCode":503,"durationMs":12,"createdAt":"2024-05-01T10:00:01Z"}]}}`,
+		recorder.Body.String())
+}
+
+func TestHandleRedeliverWebhook(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	tests := []struct {
+		name                 string
+		deliveryID           string
+		mockRedeliverWebhook func(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+		expectedStatus       int
+		expectedBody         string
+	}{
+		{
+			name:           "Invalid Delivery ID",
+			deliveryID:     "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status":"error","error":"invalid deliveryId format: invalid delivery id format"}`,
+		},
+		{
+			name:       "Success",
+			deliveryID: testDeliveryID,
+			mockRedeliverWebhook: func(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+				delivery := testDelivery()
+				delivery.Status = models.DeliveryPending
+				delivery.Attempts = 0
+				return delivery, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"status":"OK","data":{"id":"9a8b7c6d-5e4f-4a3b-9c2d-1e0f9a8b7c6d","webhookId":"6f1c2a7e-3b0d-4c55-8e0e-2f5b9c1d7a01",` +
+				`"eventId":"1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a","eventType":"OperationApplied","walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef",` +
+				`"status":"PENDING","attempts":0,"nextAttemptAt":"2024-05-01T11:00:00Z","lastStatusCode":503,` +
+				`"lastError":"receiver responded 503 Service Unavailable","createdAt":"2024-05-01T10:00:00Z",` +
+				`"body":"{\"id\":\"1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a\"}"}}`,
+		},
+		{
+			name:       "Webhook Disabled",
+			deliveryID: testDeliveryID,
+			mockRedeliverWebhook: func(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+				return nil, requests.WebhookDisabledError{}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"webhook disabled: webhook subscription is disabled"}`,
+		},
+		{
+			name:       "Delivery Pending",
+			deliveryID: testDeliveryID,
+			mockRedeliverWebhook: func(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+				return nil, requests.DeliveryPendingError{NextAttemptAt: time.Date(2024, 5, 1, 12, 1, 0, 0, time.UTC)}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status":"error","error":"delivery pending: delivery is pending until 2024-05-01T12:01:00Z"}`,
+		},
+		{
+			name:       "Delivery Not Found",
+			deliveryID: testDeliveryID,
+			mockRedeliverWebhook: func(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+				return nil, sql.ErrNoRows
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status":"error","error":"not found: sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &mockWebhookHandler{
+				RedeliverWebhookFunc: tt.mockRedeliverWebhook,
+			}
+			recorder := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/webhooks/"+testWebhookID+"/deliveries/"+tt.deliveryID+"/redeliver", nil)
+			c.Params = []gin.Param{{Key: "webhookId", Value: testWebhookID}, {Key: "deliveryId", Value: tt.deliveryID}}
+
+			HandleRedeliverWebhook(logger, mockHandler)(c)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS operations_transfer_id_idx;
+ALTER TABLE operations DROP COLUMN IF EXISTS transfer_id;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE operations ADD COLUMN transfer_id UUID;
+CREATE INDEX operations_transfer_id_idx ON operations (transfer_id) WHERE transfer_id IS NOT NULL;
+COMMIT;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS operations_wallet_id_timestamp_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS operations_wallet_id_timestamp_id_idx ON operations (wallet_id, timestamp DESC, id DESC);
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE operations DROP COLUMN IF EXISTS currency;
+ALTER TABLE wallets DROP COLUMN IF EXISTS currency;
+COMMIT;
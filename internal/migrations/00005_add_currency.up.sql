@@ -0,0 +1,5 @@
+-- Existing rows are backfilled, and the columns made NOT NULL, by 00025_currency_not_null.
+BEGIN;
+ALTER TABLE wallets ADD COLUMN currency CHAR(3);
+ALTER TABLE operations ADD COLUMN currency CHAR(3);
+COMMIT;
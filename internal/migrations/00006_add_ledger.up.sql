@@ -0,0 +1,45 @@
+-- Double-entry ledger. Every balance change is a journal entry whose postings sum to zero per currency.
+-- A positive posting increases the account balance, a negative one decreases it.
+-- Wallet accounts are named wallet:<wallet_id>, system accounts system:<name>:<currency>.
+-- Opening entries for wallets that existed before the ledger are posted by the migrator.
+BEGIN;
+CREATE TABLE ledger_accounts (
+    account_id TEXT PRIMARY KEY,
+    wallet_id UUID REFERENCES wallets(wallet_id),
+    currency CHAR(3) NOT NULL
+);
+CREATE TABLE journal_entries (
+    id UUID PRIMARY KEY,
+    kind TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+CREATE TABLE postings (
+    id BIGSERIAL PRIMARY KEY,
+    entry_id UUID NOT NULL REFERENCES journal_entries(id),
+    account_id TEXT NOT NULL REFERENCES ledger_accounts(account_id),
+    operation_id UUID REFERENCES operations(id),
+    amount INTEGER NOT NULL,
+    currency CHAR(3) NOT NULL
+);
+CREATE INDEX postings_entry_id_idx ON postings (entry_id);
+CREATE INDEX postings_account_id_idx ON postings (account_id);
+
+CREATE FUNCTION check_journal_entry_balanced() RETURNS trigger AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM postings
+        WHERE entry_id = NEW.entry_id
+        GROUP BY currency
+        HAVING SUM(amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'journal entry % is not balanced', NEW.entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER postings_balanced
+    AFTER INSERT ON postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balanced();
+COMMIT;
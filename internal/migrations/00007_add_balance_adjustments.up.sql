@@ -0,0 +1,9 @@
+-- Audit trail of wallet balances corrected by cmd/reconcile.
+CREATE TABLE balance_adjustments (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(wallet_id),
+    old_balance INTEGER NOT NULL,
+    new_balance INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,16 @@
+BEGIN;
+CREATE TABLE holds (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(wallet_id),
+    amount INTEGER NOT NULL,
+    captured_amount INTEGER NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL,
+    status TEXT NOT NULL,
+    operation_id UUID REFERENCES operations(id),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+CREATE INDEX holds_active_wallet_id_idx ON holds (wallet_id) WHERE status = 'ACTIVE';
+CREATE INDEX holds_active_expires_at_idx ON holds (expires_at) WHERE status = 'ACTIVE';
+COMMIT;
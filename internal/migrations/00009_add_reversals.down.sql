@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS operations_reversal_of_idx;
+ALTER TABLE operations DROP COLUMN IF EXISTS reversal_of;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE operations ADD COLUMN reversal_of UUID REFERENCES operations(id);
+CREATE INDEX operations_reversal_of_idx ON operations (reversal_of) WHERE reversal_of IS NOT NULL;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE wallets DROP COLUMN IF EXISTS overdraft_limit;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE wallets ADD COLUMN overdraft_limit INTEGER NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0);
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+CREATE TABLE withdrawal_limits (
+    wallet_id UUID PRIMARY KEY REFERENCES wallets(wallet_id),
+    per_operation INTEGER CHECK (per_operation > 0),
+    daily INTEGER CHECK (daily > 0),
+    monthly INTEGER CHECK (monthly > 0),
+    hourly_count INTEGER CHECK (hourly_count > 0)
+);
+COMMIT;
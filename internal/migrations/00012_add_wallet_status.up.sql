@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE wallets ADD COLUMN status TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'FROZEN', 'CLOSED'));
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+CREATE TABLE balance_snapshots (
+    wallet_id UUID NOT NULL REFERENCES wallets(wallet_id),
+    taken_at TIMESTAMP NOT NULL,
+    balance INTEGER NOT NULL,
+    PRIMARY KEY (wallet_id, taken_at)
+);
+COMMIT;
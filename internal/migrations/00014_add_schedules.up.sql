@@ -0,0 +1,23 @@
+BEGIN;
+CREATE TABLE schedules (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(wallet_id),
+    type TEXT NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    interval_unit TEXT,
+    interval_count INTEGER NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP,
+    next_run_at TIMESTAMP NOT NULL,
+    occurrence INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_operation_id UUID REFERENCES operations(id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+CREATE INDEX schedules_due_idx ON schedules (next_run_at) WHERE status = 'ACTIVE';
+CREATE INDEX schedules_wallet_id_idx ON schedules (wallet_id, created_at);
+COMMIT;
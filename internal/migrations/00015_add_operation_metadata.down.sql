@@ -0,0 +1,7 @@
+BEGIN;
+DROP INDEX IF EXISTS operations_external_reference_key;
+ALTER TABLE operations
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS external_reference,
+    DROP COLUMN IF EXISTS metadata;
+COMMIT;
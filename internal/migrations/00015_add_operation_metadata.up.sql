@@ -0,0 +1,7 @@
+BEGIN;
+ALTER TABLE operations
+    ADD COLUMN description TEXT,
+    ADD COLUMN external_reference TEXT,
+    ADD COLUMN metadata JSONB;
+CREATE UNIQUE INDEX operations_external_reference_key ON operations (wallet_id, external_reference) WHERE external_reference IS NOT NULL;
+COMMIT;
@@ -0,0 +1,20 @@
+-- Fails if any amount no longer fits in INTEGER.
+BEGIN;
+ALTER TABLE schedules ALTER COLUMN amount TYPE INTEGER;
+ALTER TABLE balance_snapshots ALTER COLUMN balance TYPE INTEGER;
+ALTER TABLE withdrawal_limits
+    ALTER COLUMN per_operation TYPE INTEGER,
+    ALTER COLUMN daily TYPE INTEGER,
+    ALTER COLUMN monthly TYPE INTEGER;
+ALTER TABLE holds
+    ALTER COLUMN amount TYPE INTEGER,
+    ALTER COLUMN captured_amount TYPE INTEGER;
+ALTER TABLE balance_adjustments
+    ALTER COLUMN old_balance TYPE INTEGER,
+    ALTER COLUMN new_balance TYPE INTEGER;
+ALTER TABLE postings ALTER COLUMN amount TYPE INTEGER;
+ALTER TABLE operations ALTER COLUMN amount TYPE INTEGER;
+ALTER TABLE wallets
+    ALTER COLUMN balance TYPE INTEGER,
+    ALTER COLUMN overdraft_limit TYPE INTEGER;
+COMMIT;
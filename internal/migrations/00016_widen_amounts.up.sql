@@ -0,0 +1,21 @@
+-- Amounts and balances are integers in minor units of the wallet currency. INTEGER capped them at about
+-- 21 million dollars' worth of cents; BIGINT matches the int64 used in Go.
+BEGIN;
+ALTER TABLE wallets
+    ALTER COLUMN balance TYPE BIGINT,
+    ALTER COLUMN overdraft_limit TYPE BIGINT;
+ALTER TABLE operations ALTER COLUMN amount TYPE BIGINT;
+ALTER TABLE postings ALTER COLUMN amount TYPE BIGINT;
+ALTER TABLE balance_adjustments
+    ALTER COLUMN old_balance TYPE BIGINT,
+    ALTER COLUMN new_balance TYPE BIGINT;
+ALTER TABLE holds
+    ALTER COLUMN amount TYPE BIGINT,
+    ALTER COLUMN captured_amount TYPE BIGINT;
+ALTER TABLE withdrawal_limits
+    ALTER COLUMN per_operation TYPE BIGINT,
+    ALTER COLUMN daily TYPE BIGINT,
+    ALTER COLUMN monthly TYPE BIGINT;
+ALTER TABLE balance_snapshots ALTER COLUMN balance TYPE BIGINT;
+ALTER TABLE schedules ALTER COLUMN amount TYPE BIGINT;
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+ALTER TABLE operations
+    DROP COLUMN IF EXISTS fee_for,
+    DROP COLUMN IF EXISTS fee;
+DROP TABLE IF EXISTS fee_rules;
+ALTER TABLE wallets DROP COLUMN IF EXISTS tier;
+COMMIT;
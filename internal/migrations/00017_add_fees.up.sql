@@ -0,0 +1,21 @@
+-- Fee rules price an operation type, optionally only for wallets of one tier or currency.
+-- A fee is booked as a WITHDRAW from the wallet and a DEPOSIT to the fee-revenue wallet, both pointing
+-- at the operation they were charged on through fee_for. That operation records the total in fee.
+BEGIN;
+ALTER TABLE wallets ADD COLUMN tier TEXT NOT NULL DEFAULT 'STANDARD';
+CREATE TABLE fee_rules (
+    id UUID PRIMARY KEY,
+    operation_type TEXT NOT NULL,
+    tier TEXT,
+    currency CHAR(3),
+    fixed BIGINT NOT NULL DEFAULT 0 CHECK (fixed >= 0),
+    rate_bps INTEGER NOT NULL DEFAULT 0 CHECK (rate_bps BETWEEN 0 AND 10000),
+    min_fee BIGINT CHECK (min_fee >= 0),
+    max_fee BIGINT CHECK (max_fee >= 0),
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX fee_rules_operation_type_idx ON fee_rules (operation_type);
+ALTER TABLE operations
+    ADD COLUMN fee BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN fee_for UUID REFERENCES operations(id);
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS interest_accruals;
+ALTER TABLE wallets DROP COLUMN IF EXISTS interest_rate_bps;
+COMMIT;
@@ -0,0 +1,17 @@
+-- Interest accrues daily on end-of-day balances at the wallet's annual rate and is paid out monthly
+-- as an INTEREST operation. The accrual ledger keeps one row per wallet and day, so re-running a day is a no-op.
+BEGIN;
+ALTER TABLE wallets ADD COLUMN interest_rate_bps INTEGER NOT NULL DEFAULT 0 CHECK (interest_rate_bps BETWEEN 0 AND 10000);
+CREATE TABLE interest_accruals (
+    wallet_id UUID NOT NULL REFERENCES wallets(wallet_id),
+    day DATE NOT NULL,
+    balance BIGINT NOT NULL,
+    rate_bps INTEGER NOT NULL,
+    accrued_micros BIGINT NOT NULL,
+    paid_at TIMESTAMP,
+    operation_id UUID REFERENCES operations(id),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (wallet_id, day)
+);
+CREATE INDEX interest_accruals_unpaid_idx ON interest_accruals (day) WHERE paid_at IS NULL;
+COMMIT;
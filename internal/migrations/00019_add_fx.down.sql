@@ -0,0 +1,7 @@
+BEGIN;
+ALTER TABLE operations
+    DROP COLUMN IF EXISTS spread_bps,
+    DROP COLUMN IF EXISTS exchange_rate;
+DROP TABLE IF EXISTS fx_quotes;
+DROP TABLE IF EXISTS fx_rates;
+COMMIT;
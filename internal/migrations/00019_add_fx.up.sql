@@ -0,0 +1,30 @@
+-- FX rates convert minor units of one currency into minor units of another and are valid over a window.
+-- A quote locks a rate and spread until it expires and is used by at most one exchange. Both legs of an
+-- exchange share a transfer_id and record the rate and spread they were converted at.
+BEGIN;
+CREATE TABLE fx_rates (
+    id UUID PRIMARY KEY,
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    rate NUMERIC NOT NULL CHECK (rate > 0),
+    spread_bps INTEGER NOT NULL DEFAULT 0 CHECK (spread_bps BETWEEN 0 AND 10000),
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP CHECK (valid_to > valid_from),
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX fx_rates_pair_idx ON fx_rates (from_currency, to_currency, valid_from);
+CREATE TABLE fx_quotes (
+    id UUID PRIMARY KEY,
+    rate_id UUID NOT NULL REFERENCES fx_rates(id),
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    rate NUMERIC NOT NULL,
+    spread_bps INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+ALTER TABLE operations
+    ADD COLUMN exchange_rate NUMERIC,
+    ADD COLUMN spread_bps INTEGER;
+COMMIT;
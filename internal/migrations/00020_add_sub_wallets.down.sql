@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS wallets_parent_id_idx;
+ALTER TABLE wallets DROP COLUMN IF EXISTS parent_id;
+COMMIT;
@@ -0,0 +1,6 @@
+-- A wallet can be opened under a parent wallet of the same currency. Balances roll up the tree
+-- when read and money moves between a parent and its children through sweeps.
+BEGIN;
+ALTER TABLE wallets ADD COLUMN parent_id UUID REFERENCES wallets(wallet_id) CHECK (parent_id <> wallet_id);
+CREATE INDEX wallets_parent_id_idx ON wallets (parent_id) WHERE parent_id IS NOT NULL;
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+DROP INDEX IF EXISTS wallets_balance_idx;
+DROP INDEX IF EXISTS wallets_created_at_idx;
+DROP INDEX IF EXISTS wallets_owner_id_idx;
+ALTER TABLE wallets DROP COLUMN IF EXISTS created_at;
+ALTER TABLE wallets DROP COLUMN IF EXISTS owner_id;
+DROP TABLE IF EXISTS owners;
+COMMIT;
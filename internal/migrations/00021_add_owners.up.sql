@@ -0,0 +1,22 @@
+-- Owners are the users of the services wallets are opened for, kept under the id those services know them by.
+-- Wallets also get a creation time so listings can be sorted by it; existing wallets date from their first operation.
+BEGIN;
+CREATE TABLE owners (
+    id UUID PRIMARY KEY,
+    external_id TEXT NOT NULL UNIQUE,
+    display_name TEXT NOT NULL,
+    metadata JSONB,
+    created_at TIMESTAMP NOT NULL
+);
+ALTER TABLE wallets
+    ADD COLUMN owner_id UUID REFERENCES owners(id),
+    ADD COLUMN created_at TIMESTAMP;
+UPDATE wallets SET created_at = COALESCE(
+    (SELECT MIN(timestamp) FROM operations WHERE operations.wallet_id = wallets.wallet_id),
+    NOW() AT TIME ZONE 'UTC'
+);
+ALTER TABLE wallets ALTER COLUMN created_at SET DEFAULT (NOW() AT TIME ZONE 'UTC'), ALTER COLUMN created_at SET NOT NULL;
+CREATE INDEX wallets_owner_id_idx ON wallets (owner_id) WHERE owner_id IS NOT NULL;
+CREATE INDEX wallets_created_at_idx ON wallets (created_at, wallet_id);
+CREATE INDEX wallets_balance_idx ON wallets (balance, wallet_id);
+COMMIT;
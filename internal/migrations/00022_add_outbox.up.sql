@@ -0,0 +1,18 @@
+-- Events about wallets are written to the outbox in the transaction that changes them and published
+-- from there by the dispatcher, so an event is neither lost nor published for a change that rolled back.
+BEGIN;
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY,
+    sequence BIGSERIAL NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    wallet_id UUID NOT NULL REFERENCES wallets(wallet_id),
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    delivered_at TIMESTAMP
+);
+CREATE INDEX outbox_events_pending_idx ON outbox_events (next_attempt_at, sequence) WHERE delivered_at IS NULL;
+COMMIT;
@@ -0,0 +1,44 @@
+-- Webhook subscriptions get the outbox events of the wallets they cover POSTed to their URL, signed with their
+-- secret. Each event becomes one delivery per subscription, retried until it succeeds or runs out of attempts.
+BEGIN;
+CREATE TABLE webhook_subscriptions (
+    id UUID PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL CHECK (cardinality(event_types) > 0),
+    wallet_id UUID REFERENCES wallets(wallet_id),
+    owner_id UUID REFERENCES owners(id),
+    secret TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id),
+    event_id UUID NOT NULL REFERENCES outbox_events(id),
+    event_type TEXT NOT NULL,
+    wallet_id UUID NOT NULL REFERENCES wallets(wallet_id),
+    body TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_status_code INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (subscription_id, event_id)
+);
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, created_at DESC, id DESC);
+CREATE TABLE webhook_delivery_attempts (
+    id UUID PRIMARY KEY,
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id),
+    status_code INTEGER,
+    error TEXT,
+    response_body TEXT,
+    duration_ms BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX webhook_delivery_attempts_delivery_idx ON webhook_delivery_attempts (delivery_id, created_at);
+COMMIT;
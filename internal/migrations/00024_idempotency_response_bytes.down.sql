@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE idempotency_keys ALTER COLUMN response_body TYPE JSONB USING convert_from(response_body, 'UTF8')::jsonb;
+COMMIT;
@@ -0,0 +1,5 @@
+-- Idempotent replays must return the original response byte for byte. JSONB reorders keys and drops
+-- whitespace, so stored responses are kept as the raw bytes that were sent.
+BEGIN;
+ALTER TABLE idempotency_keys ALTER COLUMN response_body TYPE BYTEA USING convert_to(response_body::text, 'UTF8');
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE operations ALTER COLUMN currency DROP NOT NULL;
+ALTER TABLE wallets ALTER COLUMN currency DROP NOT NULL;
+COMMIT;
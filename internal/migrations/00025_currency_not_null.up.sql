@@ -0,0 +1,12 @@
+-- Wallets and operations from before multi-currency support get the default currency: the
+-- wallet.default_currency setting of the migrating session when it has one, as the server's migrator
+-- passes WALLET_DEFAULT_CURRENCY, or USD otherwise. Operations take the currency of their wallet.
+BEGIN;
+UPDATE wallets SET currency = COALESCE(NULLIF(current_setting('wallet.default_currency', true), ''), 'USD')
+WHERE currency IS NULL;
+UPDATE operations SET currency = wallets.currency
+FROM wallets
+WHERE operations.wallet_id = wallets.wallet_id AND operations.currency IS NULL;
+ALTER TABLE wallets ALTER COLUMN currency SET NOT NULL;
+ALTER TABLE operations ALTER COLUMN currency SET NOT NULL;
+COMMIT;
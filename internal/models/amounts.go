@@ -0,0 +1,50 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// Amount is a sum of money in minor units of its currency, e.g. cents for USD.
+// It is read from JSON as either a number or a string of decimal digits, so clients that
+// parse numbers as doubles can send amounts above 2^53 without losing precision.
+type Amount int64
+
+// amountPattern is what an amount may look like, quoted or not: ParseInt alone would also take a leading +.
+var amountPattern = regexp.MustCompile(`^-?[0-9]+$`)
+
+// ErrAmountOverflow is returned when a sum of amounts does not fit in an Amount.
+var ErrAmountOverflow = errors.New("amount overflows 64 bits")
+
+// Add returns a + b, or ErrAmountOverflow if the sum does not fit.
+func (a Amount) Add(b Amount) (Amount, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, ErrAmountOverflow
+	}
+	return a + b, nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	if !amountPattern.Match(data) {
+		return errors.New("amount must be an integer number of minor units within 64 bits")
+	}
+	parsed, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return errors.New("amount must be an integer number of minor units within 64 bits")
+	}
+	*a = Amount(parsed)
+	return nil
+}
+
+func (a Amount) String() string {
+	return strconv.FormatInt(int64(a), 10)
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+// DefaultTier is the tier of wallets that were never put in another one.
+const DefaultTier = "STANDARD"
+
+// MaxRateBps is a fee rate of 100%, in hundredths of a percent.
+const MaxRateBps = 10000
+
+var tierPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,31}$`)
+
+// ValidateTier checks that tier is a name fee rules can match wallets on.
+func ValidateTier(tier string) error {
+	if !tierPattern.MatchString(tier) {
+		return errors.New("tier must be 1 to 32 uppercase letters, digits or underscores")
+	}
+	return nil
+}
+
+// FeeRule prices an operation type, optionally only for wallets of one tier or currency.
+// The fee is Fixed plus RateBps hundredths of a percent of the amount rounded half up,
+// then raised to MinFee and capped at MaxFee.
+type FeeRule struct {
+	ID            string    `db:"id"`
+	OperationType string    `db:"operation_type"`
+	Tier          *string   `db:"tier"`     // Any tier when nil
+	Currency      *string   `db:"currency"` // Any currency when nil
+	Fixed         Amount    `db:"fixed"`
+	RateBps       int64     `db:"rate_bps"`
+	MinFee        *Amount   `db:"min_fee"`
+	MaxFee        *Amount   `db:"max_fee"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func (r FeeRule) Validate() error {
+	if r.OperationType != "DEPOSIT" && r.OperationType != "WITHDRAW" {
+		return errors.New("operationType must be DEPOSIT or WITHDRAW")
+	}
+	if r.Tier != nil {
+		if err := ValidateTier(*r.Tier); err != nil {
+			return err
+		}
+	}
+	if r.Fixed < 0 {
+		return errors.New("fixed cant be negative")
+	}
+	if r.RateBps < 0 || r.RateBps > MaxRateBps {
+		return errors.New("rateBps must be between 0 and 10000")
+	}
+	if r.MinFee != nil && *r.MinFee < 0 {
+		return errors.New("minFee cant be negative")
+	}
+	if r.MaxFee != nil && *r.MaxFee < 0 {
+		return errors.New("maxFee cant be negative")
+	}
+	if r.MinFee != nil && r.MaxFee != nil && *r.MaxFee < *r.MinFee {
+		return errors.New("maxFee cant be below minFee")
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to an operation of operationType on wallet.
+func (r FeeRule) Matches(operationType string, wallet *Wallets) bool {
+	return r.OperationType == operationType &&
+		(r.Tier == nil || *r.Tier == wallet.Tier) &&
+		(r.Currency == nil || *r.Currency == wallet.Currency)
+}
+
+// specificity ranks matching rules: a rule for the wallet's tier beats one for its currency,
+// which beats a rule for every wallet.
+func (r FeeRule) specificity() int {
+	rank := 0
+	if r.Tier != nil {
+		rank += 2
+	}
+	if r.Currency != nil {
+		rank++
+	}
+	return rank
+}
+
+// SelectFeeRule returns the most specific of rules that applies to an operation of operationType
+// on wallet, the earliest one winning ties, or nil if none applies.
+func SelectFeeRule(rules []FeeRule, operationType string, wallet *Wallets) *FeeRule {
+	var selected *FeeRule
+	for i := range rules {
+		if !rules[i].Matches(operationType, wallet) {
+			continue
+		}
+		if selected == nil || rules[i].specificity() > selected.specificity() {
+			selected = &rules[i]
+		}
+	}
+	return selected
+}
+
+// Fee returns the fee the rule charges on amount, or ErrAmountOverflow if it does not fit in an Amount.
+func (r FeeRule) Fee(amount Amount) (Amount, error) {
+	// Split the amount so that multiplying by the rate cannot overflow.
+	whole, rest := int64(amount)/MaxRateBps, int64(amount)%MaxRateBps
+	percentage := Amount(whole*r.RateBps + (rest*r.RateBps+MaxRateBps/2)/MaxRateBps)
+	fee, err := r.Fixed.Add(percentage)
+	if err != nil {
+		return 0, err
+	}
+	if r.MinFee != nil && fee < *r.MinFee {
+		fee = *r.MinFee
+	}
+	if r.MaxFee != nil && fee > *r.MaxFee {
+		fee = *r.MaxFee
+	}
+	return fee, nil
+}
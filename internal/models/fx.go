@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxRateDecimals is the number of decimal places an FX rate can have.
+const MaxRateDecimals = 12
+
+// ErrConvertedTooSmall is returned when an exchanged amount would convert to less than one minor unit.
+var ErrConvertedTooSmall = errors.New("amount converts to less than one minor unit")
+
+var (
+	ratePattern     = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// FXRate converts minor units of FromCurrency into minor units of ToCurrency from ValidFrom until ValidTo.
+// Rate is in minor units, so with USD cents and JPY yen a dollar at 150 yen is a rate of 1.5.
+// SpreadBps is kept by the house on every exchange at the rate.
+type FXRate struct {
+	ID           string     `db:"id"`
+	FromCurrency string     `db:"from_currency"`
+	ToCurrency   string     `db:"to_currency"`
+	Rate         string     `db:"rate"` // Decimal, as NormalizeRate writes it
+	SpreadBps    int64      `db:"spread_bps"`
+	ValidFrom    time.Time  `db:"valid_from"`
+	ValidTo      *time.Time `db:"valid_to"` // Open-ended when nil
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (r FXRate) Validate() error {
+	if !currencyPattern.MatchString(r.FromCurrency) || !currencyPattern.MatchString(r.ToCurrency) {
+		return errors.New("currency must be an ISO 4217 code")
+	}
+	if r.FromCurrency == r.ToCurrency {
+		return errors.New("fromCurrency and toCurrency must differ")
+	}
+	if _, err := NormalizeRate(r.Rate); err != nil {
+		return err
+	}
+	if r.SpreadBps < 0 || r.SpreadBps > MaxRateBps {
+		return errors.New("spreadBps must be between 0 and 10000")
+	}
+	if r.ValidTo != nil && !r.ValidTo.After(r.ValidFrom) {
+		return errors.New("validTo must be after validFrom")
+	}
+	return nil
+}
+
+// FXQuote locks the rate and spread of an FXRate until ExpiresAt. It can be used by one exchange.
+type FXQuote struct {
+	ID           string     `db:"id"`
+	RateID       string     `db:"rate_id"`
+	FromCurrency string     `db:"from_currency"`
+	ToCurrency   string     `db:"to_currency"`
+	Rate         string     `db:"rate"`
+	SpreadBps    int64      `db:"spread_bps"`
+	ExpiresAt    time.Time  `db:"expires_at"`
+	UsedAt       *time.Time `db:"used_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+// NormalizeRate checks that rate is a positive decimal with at most MaxRateDecimals decimal places
+// and returns it without leading or trailing zeros, so equal rates are written the same way.
+func NormalizeRate(rate string) (string, error) {
+	if !ratePattern.MatchString(rate) {
+		return "", errors.New("rate must be a positive decimal number")
+	}
+	whole, frac, _ := strings.Cut(rate, ".")
+	if len(frac) > MaxRateDecimals {
+		return "", errors.New("rate can have at most 12 decimal places")
+	}
+	whole = strings.TrimLeft(whole, "0")
+	frac = strings.TrimRight(frac, "0")
+	if whole == "" && frac == "" {
+		return "", errors.New("rate must be a positive decimal number")
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if frac == "" {
+		return whole, nil
+	}
+	return whole + "." + frac, nil
+}
+
+// Convert returns amount in the other currency at rate less spreadBps, rounded down: the house never gives
+// away a fraction of a minor unit. It fails with ErrConvertedTooSmall if nothing would be left and with
+// ErrAmountOverflow if the result does not fit in 64 bits.
+func Convert(amount Amount, rate string, spreadBps int64) (Amount, error) {
+	parsed, ok := new(big.Rat).SetString(rate)
+	if !ok || parsed.Sign() <= 0 {
+		return 0, errors.New("rate must be a positive decimal number")
+	}
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(amount)), parsed)
+	converted.Mul(converted, big.NewRat(MaxRateBps-spreadBps, MaxRateBps))
+	result := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !result.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+	if result.Sign() <= 0 {
+		return 0, ErrConvertedTooSmall
+	}
+	return Amount(result.Int64()), nil
+}
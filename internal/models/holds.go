@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Hold statuses. Only ACTIVE holds that have not expired reduce the available balance.
+const (
+	HoldActive   = "ACTIVE"
+	HoldCaptured = "CAPTURED"
+	HoldVoided   = "VOIDED"
+	HoldExpired  = "EXPIRED"
+)
+
+type Hold struct {
+	ID             string    `db:"id"`
+	WalletID       string    `db:"wallet_id"`
+	Amount         Amount    `db:"amount"`
+	CapturedAmount Amount    `db:"captured_amount"`
+	Currency       string    `db:"currency"`
+	Status         string    `db:"status"`
+	OperationID    *string   `db:"operation_id"` // WITHDRAW created by the capture
+	ExpiresAt      time.Time `db:"expires_at"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"math/big"
+	"time"
+)
+
+// MicrosPerUnit is the number of accrual units in a minor unit: daily interest is kept to a millionth of a cent.
+const MicrosPerUnit = 1000000
+
+// DaysPerYear is the day count of the annual rate. Every day accrues 1/365 of it, leap years included.
+const DaysPerYear = 365
+
+// InterestAccrual is the interest a wallet earned on one day, on its balance at the end of that day.
+type InterestAccrual struct {
+	WalletID      string     `db:"wallet_id"`
+	Day           time.Time  `db:"day"`
+	Balance       Amount     `db:"balance"`
+	RateBps       int64      `db:"rate_bps"`       // Annual rate in hundredths of a percent
+	AccruedMicros int64      `db:"accrued_micros"` // In millionths of a minor unit
+	PaidAt        *time.Time `db:"paid_at"`
+	OperationID   *string    `db:"operation_id"` // INTEREST operation that paid it out, if it came to at least a minor unit
+	CreatedAt     time.Time  `db:"created_at"`
+}
+
+// DailyInterest returns what balance earns in a day at an annual rate of rateBps, in millionths of a minor unit,
+// rounded half to even. Balances at or below zero earn nothing. The result is ErrAmountOverflow if it does not
+// fit in 64 bits.
+func DailyInterest(balance Amount, rateBps int64) (int64, error) {
+	if balance <= 0 || rateBps <= 0 {
+		return 0, nil
+	}
+	num := new(big.Int).Mul(big.NewInt(int64(balance)), big.NewInt(rateBps*MicrosPerUnit))
+	den := big.NewInt(MaxRateBps * DaysPerYear)
+	micros := roundHalfEven(num, den)
+	if !micros.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+	return micros.Int64(), nil
+}
+
+// MicrosToAmount rounds an accrued sum to minor units, half to even.
+func MicrosToAmount(micros int64) Amount {
+	return Amount(roundHalfEven(big.NewInt(micros), big.NewInt(MicrosPerUnit)).Int64())
+}
+
+// roundHalfEven divides num by a positive den, rounding ties to the even quotient.
+func roundHalfEven(num, den *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	// Compare twice the remainder with the divisor to tell below, at and above half.
+	twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+	cmp := twice.Cmp(den)
+	if cmp > 0 || (cmp == 0 && quo.Bit(0) == 1) {
+		if num.Sign() < 0 {
+			return quo.Sub(quo, big.NewInt(1))
+		}
+		return quo.Add(quo, big.NewInt(1))
+	}
+	return quo
+}
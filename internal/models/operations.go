@@ -1,11 +1,34 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Operation struct {
-	ID        string    `db:"id"`
-	WalletID  string    `db:"wallet_id"`
-	Type      string    `db:"type"`
-	Amount    int       `db:"amount"`
-	Timestamp time.Time `db:"timestamp"`
+	ID         string    `db:"id"`
+	WalletID   string    `db:"wallet_id"`
+	Type       string    `db:"type"`
+	Amount     Amount    `db:"amount"`
+	Timestamp  time.Time `db:"timestamp"`
+	TransferID *string   `db:"transfer_id"` // Set on both legs of a transfer
+	Currency   string    `db:"currency"`
+	ReversalOf *string   `db:"reversal_of"` // Operation this one compensates
+	Fee        Amount    `db:"fee"`         // Fee charged on this operation, booked as separate operations
+	FeeFor     *string   `db:"fee_for"`     // Set on both legs of a fee: the operation it was charged on
+
+	ExchangeRate *string `db:"exchange_rate"` // Set on both legs of an exchange: the rate it was converted at
+	SpreadBps    *int64  `db:"spread_bps"`    // and the spread kept on it
+
+	Description       *string         `db:"description"`
+	ExternalReference *string         `db:"external_reference"` // Caller's own id, unique per wallet
+	Metadata          json.RawMessage `db:"metadata"`           // Free-form JSON object
+}
+
+// SignedAmount is the effect of the operation on its wallet's balance.
+func (o Operation) SignedAmount() Amount {
+	if o.Type == "WITHDRAW" {
+		return -o.Amount
+	}
+	return o.Amount
 }
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Owner is the user a wallet belongs to, as known to the service that opened it.
+type Owner struct {
+	ID          string          `db:"id"`
+	ExternalID  string          `db:"external_id"` // The owner's id in the calling service, unique
+	DisplayName string          `db:"display_name"`
+	Metadata    json.RawMessage `db:"metadata"` // Free-form JSON object
+	CreatedAt   time.Time       `db:"created_at"`
+}
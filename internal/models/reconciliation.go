@@ -0,0 +1,13 @@
+package models
+
+// BalanceMismatch is a wallet whose stored balance differs from the balance recomputed from its operations.
+type BalanceMismatch struct {
+	WalletID        string `db:"wallet_id"`
+	Currency        string `db:"currency"`
+	Balance         Amount `db:"balance"`
+	ComputedBalance Amount `db:"computed_balance"`
+}
+
+func (m BalanceMismatch) Difference() Amount {
+	return m.Balance - m.ComputedBalance
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Schedule statuses. Only ACTIVE schedules are run; COMPLETED and CANCELLED ones can no longer change.
+const (
+	ScheduleActive    = "ACTIVE"
+	SchedulePaused    = "PAUSED"
+	ScheduleCompleted = "COMPLETED"
+	ScheduleFailed    = "FAILED"
+	ScheduleCancelled = "CANCELLED"
+)
+
+// Schedule intervals. A schedule without an interval runs once.
+const (
+	IntervalDay   = "DAY"
+	IntervalWeek  = "WEEK"
+	IntervalMonth = "MONTH"
+)
+
+type Schedule struct {
+	ID              string     `db:"id"`
+	WalletID        string     `db:"wallet_id"`
+	Type            string     `db:"type"`
+	Amount          Amount     `db:"amount"`
+	Currency        string     `db:"currency"`
+	Interval        *string    `db:"interval_unit"`
+	IntervalCount   int        `db:"interval_count"`
+	StartAt         time.Time  `db:"start_at"`
+	EndAt           *time.Time `db:"end_at"`      // No occurrence after this time is run
+	NextRunAt       time.Time  `db:"next_run_at"` // Next occurrence, or the next retry of a failed one
+	Occurrence      int        `db:"occurrence"`  // Number of occurrences already run
+	Status          string     `db:"status"`
+	Attempts        int        `db:"attempts"` // Failed attempts at the current occurrence
+	LastError       *string    `db:"last_error"`
+	LastOperationID *string    `db:"last_operation_id"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+}
+
+// OccurrenceAt returns when the n-th occurrence (counting from zero) is due. Monthly schedules started
+// late in a month fall on the last day of shorter months.
+func (s Schedule) OccurrenceAt(n int) time.Time {
+	if s.Interval == nil {
+		return s.StartAt
+	}
+	steps := n * s.IntervalCount
+	switch *s.Interval {
+	case IntervalDay:
+		return s.StartAt.AddDate(0, 0, steps)
+	case IntervalWeek:
+		return s.StartAt.AddDate(0, 0, 7*steps)
+	default:
+		first := time.Date(s.StartAt.Year(), s.StartAt.Month()+time.Month(steps), 1,
+			s.StartAt.Hour(), s.StartAt.Minute(), s.StartAt.Second(), s.StartAt.Nanosecond(), s.StartAt.Location())
+		day := s.StartAt.Day()
+		if last := first.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		return first.AddDate(0, 0, day-1)
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Statement summarises a wallet over the period [From, To).
+type Statement struct {
+	WalletID       string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance Amount // Balance just before From
+	ClosingBalance Amount // Balance just before To
+}
+
+// StatementLine is an operation on a statement with the wallet balance right after it.
+type StatementLine struct {
+	Operation
+	Balance Amount
+}
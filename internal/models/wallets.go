@@ -1,6 +1,23 @@
 package models
 
+import "time"
+
+// Wallet statuses. FROZEN wallets accept credits only, CLOSED wallets accept nothing.
+const (
+	WalletActive = "ACTIVE"
+	WalletFrozen = "FROZEN"
+	WalletClosed = "CLOSED"
+)
+
 type Wallets struct {
-	WalletID string `db:"wallet_id"`
-	Balance  int    `db:"balance"`
+	WalletID       string    `db:"wallet_id"`
+	Balance        Amount    `db:"balance"`
+	Currency       string    `db:"currency"`        // ISO 4217 code
+	OverdraftLimit Amount    `db:"overdraft_limit"` // How far below zero the balance may go
+	Status         string    `db:"status"`
+	Tier           string    `db:"tier"`              // Fee rules can be limited to a tier
+	InterestRate   int64     `db:"interest_rate_bps"` // Annual rate in hundredths of a percent
+	ParentID       *string   `db:"parent_id"`         // Set on sub-wallets
+	OwnerID        *string   `db:"owner_id"`
+	CreatedAt      time.Time `db:"created_at"`
 }
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Webhook subscription statuses. PAUSED subscriptions keep collecting deliveries and send them once resumed;
+// DISABLED ones are gone for good.
+const (
+	WebhookActive   = "ACTIVE"
+	WebhookPaused   = "PAUSED"
+	WebhookDisabled = "DISABLED"
+)
+
+// Webhook delivery statuses. DEAD deliveries ran out of attempts, or their subscription was disabled,
+// and are only sent again when redelivered by hand.
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
+)
+
+// WebhookSubscription sends the events of the given types about the wallets it covers to URL:
+// one wallet, every wallet of an owner or, with neither set, every wallet.
+type WebhookSubscription struct {
+	ID         string    `db:"id"`
+	URL        string    `db:"url"`
+	EventTypes []string  `db:"event_types"`
+	WalletID   *string   `db:"wallet_id"`
+	OwnerID    *string   `db:"owner_id"`
+	Secret     string    `db:"secret"` // Key of the HMAC-SHA256 signature on every delivery
+	Status     string    `db:"status"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             string     `db:"id"`
+	SubscriptionID string     `db:"subscription_id"`
+	EventID        string     `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	WalletID       string     `db:"wallet_id"`
+	Body           string     `db:"body"` // The event as POSTed
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"` // Attempts since the delivery was queued or last redelivered
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastStatusCode *int       `db:"last_status_code"`
+	LastError      *string    `db:"last_error"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+// WebhookAttempt is one POST of a delivery, as logged.
+type WebhookAttempt struct {
+	ID           string    `db:"id"`
+	DeliveryID   string    `db:"delivery_id"`
+	StatusCode   *int      `db:"status_code"` // Unset when no response came back
+	Error        *string   `db:"error"`
+	ResponseBody *string   `db:"response_body"` // Start of the response body
+	DurationMs   int64     `db:"duration_ms"`
+	CreatedAt    time.Time `db:"created_at"`
+}
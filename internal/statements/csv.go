@@ -0,0 +1,72 @@
+package statements
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// csvFlushEvery is how many rows are buffered before they are written out.
+const csvFlushEvery = 100
+
+var csvHeader = []string{"timestamp", "operation_id", "type", "amount", "balance", "transfer_id", "reversal_of"}
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *csvWriter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (cw *csvWriter) Begin(statement models.Statement) error {
+	if err := cw.w.Write(csvHeader); err != nil {
+		return err
+	}
+	return cw.w.Write([]string{statement.From.UTC().Format(time.RFC3339Nano), "", "OPENING_BALANCE", "", statement.OpeningBalance.String(), "", ""})
+}
+
+func (cw *csvWriter) Line(line models.StatementLine) error {
+	_, signed := entry(line)
+	err := cw.w.Write([]string{
+		line.Timestamp.UTC().Format(time.RFC3339Nano),
+		line.ID,
+		line.Type,
+		signed.String(),
+		line.Balance.String(),
+		optional(line.TransferID),
+		optional(line.ReversalOf),
+	})
+	if err != nil {
+		return err
+	}
+	cw.rows++
+	if cw.rows%csvFlushEvery == 0 {
+		cw.w.Flush()
+		return cw.w.Error()
+	}
+	return nil
+}
+
+func (cw *csvWriter) End(statement models.Statement) error {
+	err := cw.w.Write([]string{statement.To.UTC().Format(time.RFC3339Nano), "", "CLOSING_BALANCE", "", statement.ClosingBalance.String(), "", ""})
+	if err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func optional(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,80 @@
+package statements
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+type jsonLine struct {
+	ID            string        `json:"id"`
+	OperationType string        `json:"operationType"`
+	Amount        models.Amount `json:"amount"` // Signed effect on the balance
+	Balance       models.Amount `json:"balance"`
+	Timestamp     time.Time     `json:"timestamp"`
+	TransferID    *string       `json:"transferId,omitempty"`
+	ReversalOf    *string       `json:"reversalOf,omitempty"`
+}
+
+// jsonWriter writes the same envelope as the other endpoints, with the operations array written element by element.
+type jsonWriter struct {
+	w     io.Writer
+	lines int
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: w}
+}
+
+func (jw *jsonWriter) ContentType() string {
+	return "application/json; charset=utf-8"
+}
+
+func (jw *jsonWriter) Begin(statement models.Statement) error {
+	head, err := json.Marshal(struct {
+		WalletID       string        `json:"walletId"`
+		Currency       string        `json:"currency"`
+		From           time.Time     `json:"from"`
+		To             time.Time     `json:"to"`
+		OpeningBalance models.Amount `json:"openingBalance"`
+	}{statement.WalletID, statement.Currency, statement.From.UTC(), statement.To.UTC(), statement.OpeningBalance})
+	if err != nil {
+		return err
+	}
+	// Reopen the object to append the operations array.
+	_, err = fmt.Fprintf(jw.w, `{"status":%q,"data":%s,"operations":[`, requests.StatusOK, head[:len(head)-1])
+	return err
+}
+
+func (jw *jsonWriter) Line(line models.StatementLine) error {
+	_, signed := entry(line)
+	body, err := json.Marshal(jsonLine{
+		ID:            line.ID,
+		OperationType: line.Type,
+		Amount:        signed,
+		Balance:       line.Balance,
+		Timestamp:     line.Timestamp.UTC(),
+		TransferID:    line.TransferID,
+		ReversalOf:    line.ReversalOf,
+	})
+	if err != nil {
+		return err
+	}
+	if jw.lines > 0 {
+		if _, err = io.WriteString(jw.w, ","); err != nil {
+			return err
+		}
+	}
+	jw.lines++
+	_, err = jw.w.Write(body)
+	return err
+}
+
+func (jw *jsonWriter) End(statement models.Statement) error {
+	_, err := fmt.Fprintf(jw.w, `],"closingBalance":%d}}`, statement.ClosingBalance)
+	return err
+}
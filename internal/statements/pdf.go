@@ -0,0 +1,158 @@
+package statements
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// A4 page layout in points, set in a monospaced standard font so no font has to be embedded.
+const (
+	pdfPageWidth    = 595
+	pdfPageHeight   = 842
+	pdfMargin       = 40
+	pdfFontSize     = 8
+	pdfLeading      = 12
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// Objects written before the pages and referenced by them. The catalog and page tree
+// are only written at the end, once all page numbers are known.
+const (
+	pdfCatalogObj = 1
+	pdfPagesObj   = 2
+	pdfFontObj    = 3
+)
+
+// pdfWriter writes a PDF one page at a time, keeping only the current page's text and the byte offset
+// of every object written so far for the cross-reference table.
+type pdfWriter struct {
+	w       *countingWriter
+	offsets map[int]int64
+	nextObj int
+	pages   []int
+	lines   []string
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) printf(format string, args ...interface{}) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+func newPDFWriter(w io.Writer) *pdfWriter {
+	return &pdfWriter{
+		w:       &countingWriter{w: w},
+		offsets: make(map[int]int64),
+		nextObj: pdfFontObj + 1,
+	}
+}
+
+func (pw *pdfWriter) ContentType() string {
+	return "application/pdf"
+}
+
+func (pw *pdfWriter) Begin(statement models.Statement) error {
+	pw.w.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	pw.object(pdfFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	pw.lines = append(pw.lines,
+		"Statement for wallet "+statement.WalletID,
+		fmt.Sprintf("Period %s to %s, %s", statement.From.UTC().Format(time.RFC3339), statement.To.UTC().Format(time.RFC3339), statement.Currency),
+		"",
+		fmt.Sprintf("%-20s  %-36s  %-22s  %12s  %12s", "Timestamp", "Operation", "Type", "Amount", "Balance"),
+		fmt.Sprintf("%-20s  %-36s  %-22s  %12s  %12d", statement.From.UTC().Format(time.RFC3339), "", "Opening balance", "", statement.OpeningBalance),
+	)
+	return pw.w.err
+}
+
+func (pw *pdfWriter) Line(line models.StatementLine) error {
+	kind, signed := entry(line)
+	pw.addLine(fmt.Sprintf("%-20s  %-36s  %-22s  %12d  %12d", line.Timestamp.UTC().Format(time.RFC3339), line.ID, kind, signed, line.Balance))
+	return pw.w.err
+}
+
+func (pw *pdfWriter) End(statement models.Statement) error {
+	pw.addLine(fmt.Sprintf("%-20s  %-36s  %-22s  %12s  %12d", statement.To.UTC().Format(time.RFC3339), "", "Closing balance", "", statement.ClosingBalance))
+	pw.flushPage()
+
+	kids := make([]string, len(pw.pages))
+	for i, page := range pw.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	pw.object(pdfPagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pw.pages)))
+	pw.object(pdfCatalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObj))
+
+	xref := pw.w.n
+	pw.w.printf("xref\n0 %d\n0000000000 65535 f \n", pw.nextObj)
+	for obj := 1; obj < pw.nextObj; obj++ {
+		pw.w.printf("%010d 00000 n \n", pw.offsets[obj])
+	}
+	pw.w.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", pw.nextObj, pdfCatalogObj, xref)
+	return pw.w.err
+}
+
+func (pw *pdfWriter) addLine(text string) {
+	if len(pw.lines) == pdfLinesPerPage {
+		pw.flushPage()
+	}
+	pw.lines = append(pw.lines, text)
+}
+
+// flushPage writes the current page's content stream and page object.
+func (pw *pdfWriter) flushPage() {
+	var content strings.Builder
+	fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+	for _, line := range pw.lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdfEscape(line))
+	}
+	content.WriteString("ET")
+	pw.lines = pw.lines[:0]
+
+	contentObj := pw.allocate()
+	pw.object(contentObj, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	pageObj := pw.allocate()
+	pw.object(pageObj, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObj, pdfPageWidth, pdfPageHeight, pdfFontObj, contentObj))
+	pw.pages = append(pw.pages, pageObj)
+}
+
+func (pw *pdfWriter) allocate() int {
+	obj := pw.nextObj
+	pw.nextObj++
+	return obj
+}
+
+func (pw *pdfWriter) object(obj int, body string) {
+	pw.offsets[obj] = pw.w.n
+	pw.w.printf("%d 0 obj\n%s\nendobj\n", obj, body)
+}
+
+// pdfEscape makes text safe inside a PDF string literal, replacing anything outside printable ASCII.
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
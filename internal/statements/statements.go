@@ -0,0 +1,51 @@
+// Package statements renders account statements as CSV, JSON or PDF while they are streamed from storage.
+package statements
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// Formats a statement can be rendered in.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatPDF  = "pdf"
+)
+
+// Writer renders a statement piece by piece: Begin once with the opening balance, Line for every operation
+// and End with the closing balance. Nothing but the current page is kept in memory.
+type Writer interface {
+	Begin(statement models.Statement) error
+	Line(line models.StatementLine) error
+	End(statement models.Statement) error
+	ContentType() string
+}
+
+// NewWriter returns a Writer rendering format to w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	case FormatPDF:
+		return newPDFWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unknown statement format %q", format)
+	}
+}
+
+// entry is how a statement line reads in every format.
+func entry(line models.StatementLine) (kind string, signed models.Amount) {
+	kind = line.Type
+	if line.TransferID != nil {
+		kind += " (transfer)"
+	}
+	if line.ReversalOf != nil {
+		kind += " (reversal)"
+	}
+	return kind, line.SignedAmount()
+}
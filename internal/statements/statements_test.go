@@ -0,0 +1,109 @@
+package statements
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, format string, lines int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	require.NoError(t, err)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	statement := models.Statement{
+		WalletID:       "a1b2c3d4-e5f6-7890-1234-567890abcdef",
+		Currency:       "USD",
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 1000,
+	}
+	require.NoError(t, w.Begin(statement))
+	balance := statement.OpeningBalance
+	for i := 0; i < lines; i++ {
+		line := models.StatementLine{Operation: models.Operation{
+			ID:        fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+			WalletID:  statement.WalletID,
+			Type:      "DEPOSIT",
+			Amount:    10,
+			Timestamp: from.Add(time.Duration(i) * time.Minute),
+		}}
+		if i%2 == 1 {
+			line.Type = "WITHDRAW"
+		}
+		balance += line.SignedAmount()
+		line.Balance = balance
+		require.NoError(t, w.Line(line))
+	}
+	statement.ClosingBalance = balance
+	require.NoError(t, w.End(statement))
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	out := string(render(t, FormatCSV, 3))
+	assert.Equal(t, strings.Join([]string{
+		"timestamp,operation_id,type,amount,balance,transfer_id,reversal_of",
+		"2024-05-01T00:00:00Z,,OPENING_BALANCE,,1000,,",
+		"2024-05-01T00:00:00Z,00000000-0000-0000-0000-000000000000,DEPOSIT,10,1010,,",
+		"2024-05-01T00:01:00Z,00000000-0000-0000-0000-000000000001,WITHDRAW,-10,1000,,",
+		"2024-05-01T00:02:00Z,00000000-0000-0000-0000-000000000002,DEPOSIT,10,1010,,",
+		"2024-06-01T00:00:00Z,,CLOSING_BALANCE,,1010,,",
+		"",
+	}, "\n"), out)
+}
+
+func TestJSONWriter(t *testing.T) {
+	out := render(t, FormatJSON, 2)
+	assert.JSONEq(t, `{"status":"OK","data":{"walletId":"a1b2c3d4-e5f6-7890-1234-567890abcdef","currency":"USD",
+		"from":"2024-05-01T00:00:00Z","to":"2024-06-01T00:00:00Z","openingBalance":1000,
+		"operations":[
+			{"id":"00000000-0000-0000-0000-000000000000","operationType":"DEPOSIT","amount":10,"balance":1010,"timestamp":"2024-05-01T00:00:00Z"},
+			{"id":"00000000-0000-0000-0000-000000000001","operationType":"WITHDRAW","amount":-10,"balance":1000,"timestamp":"2024-05-01T00:01:00Z"}
+		],
+		"closingBalance":1000}}`, string(out))
+
+	empty := render(t, FormatJSON, 0)
+	assert.True(t, json.Valid(empty))
+}
+
+func TestPDFWriter(t *testing.T) {
+	out := render(t, FormatPDF, 3*pdfLinesPerPage)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 4")
+
+	// Every cross-reference entry must point at the start of its object.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, startxref)
+	xrefAt, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[xrefAt:], []byte("xref\n")))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xrefAt:], -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+}
+
+func TestPDFEscape(t *testing.T) {
+	assert.Equal(t, `refund \(partial\) \\ ?`, pdfEscape(`refund (partial) \ é`))
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter("xlsx", &bytes.Buffer{})
+	assert.Error(t, err)
+}
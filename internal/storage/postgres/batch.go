@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// ProcessBatch applies a list of operations in one transaction, in request order.
+// Every wallet of the batch is locked up front in ascending id order, so concurrent batches
+// touching the same wallets queue behind each other instead of deadlocking.
+// In ATOMIC mode the first failing operation rolls the whole batch back and is returned as a
+// BatchItemError. In BEST_EFFORT mode each operation runs under its own savepoint: one that is
+// rejected is reported in its result and the rest still commit.
+func (s *Storage) ProcessBatch(ctx context.Context, req requests.BatchOperationRequest) (*requests.BatchOperationResponse, error) {
+	op := "database.ProcessBatch"
+
+	mode := req.Mode
+	if mode == "" {
+		mode = requests.BatchAtomic
+	}
+	resp := &requests.BatchOperationResponse{Mode: mode}
+
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		resp.Results = make([]requests.BatchItemResult, 0, len(req.Operations))
+		resp.Succeeded, resp.Failed = 0, 0
+
+		if err := s.lockBatchWallets(ctx, tx, req.Operations); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		for i, item := range req.Operations {
+			result := requests.BatchItemResult{
+				Index:         i,
+				WalletID:      item.WalletID,
+				OperationType: item.OperationType,
+				Amount:        item.Amount,
+			}
+			if mode == requests.BatchBestEffort {
+				if _, err := tx.ExecContext(ctx, "SAVEPOINT batch_item"); err != nil {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+			}
+			wallet, operation, err := s.processOperation(ctx, tx, item)
+			if err != nil {
+				if mode != requests.BatchBestEffort {
+					return requests.BatchItemError{Index: i, Err: err}
+				}
+				if !rejectedOperation(err) {
+					return fmt.Errorf("%s: operation %d: %w", op, i, err)
+				}
+				if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_item"); rbErr != nil {
+					return fmt.Errorf("%s: %w", op, rbErr)
+				}
+				result.Status = requests.StatusError
+				result.Err = err
+				resp.Failed++
+			} else {
+				balance := wallet.Balance
+				result.Status = requests.StatusOK
+				result.OperationID = operation.ID
+				result.Balance = &balance
+				result.Currency = wallet.Currency
+				result.Fee = operation.Fee
+				resp.Succeeded++
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// lockBatchWallets creates the missing wallets of a batch when auto-create is on and locks them all,
+// along with the fee wallets, in ascending id order. A new wallet takes the currency of its first operation.
+func (s *Storage) lockBatchWallets(ctx context.Context, tx *sql.Tx, operations []requests.WalletOperationRequest) error {
+	currencies := make(map[string]string)
+	ids := make([]string, 0, len(operations))
+	for _, item := range operations {
+		if _, ok := currencies[item.WalletID]; ok {
+			continue
+		}
+		currency := item.Currency
+		if currency == "" {
+			currency = s.defaultCurrency
+		}
+		currencies[item.WalletID] = currency
+		ids = append(ids, item.WalletID)
+	}
+
+	if s.autoCreate {
+		for _, id := range sortedWalletIDs(ids) {
+			if err := autoCreateWallet(ctx, tx, id, currencies[id]); err != nil {
+				return fmt.Errorf("create wallet error: %w", err)
+			}
+		}
+	}
+	// Fee wallets are locked along with the rest, or charging a fee would take a lock out of order.
+	for _, id := range s.feeWallets {
+		ids = append(ids, id)
+	}
+	if _, err := lockWallets(ctx, tx, ids...); err != nil {
+		return fmt.Errorf("lock wallets error: %w", err)
+	}
+	return nil
+}
+
+// rejectedOperation reports whether err is a business rule turning an operation down,
+// as opposed to a failure of the database itself.
+func rejectedOperation(err error) bool {
+	var (
+		insufficientFundsErr requests.InsufficientFundsError
+		withdrawalLimitErr   requests.WithdrawalLimitError
+		currencyMismatchErr  requests.CurrencyMismatchError
+		walletNotActiveErr   requests.WalletNotActiveError
+		externalRefErr       requests.ExternalReferenceConflictError
+	)
+	return errors.Is(err, sql.ErrNoRows) ||
+		errors.Is(err, models.ErrAmountOverflow) ||
+		errors.As(err, &insufficientFundsErr) ||
+		errors.As(err, &withdrawalLimitErr) ||
+		errors.As(err, &currencyMismatchErr) ||
+		errors.As(err, &walletNotActiveErr) ||
+		errors.As(err, &externalRefErr)
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBatchAtomic(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	first, second := uuid.New().String(), uuid.New().String()
+
+	resp, err := storage.ProcessBatch(ctx, requests.BatchOperationRequest{
+		Operations: []requests.WalletOperationRequest{
+			{WalletID: first, OperationType: "DEPOSIT", Amount: 500},
+			{WalletID: second, OperationType: "DEPOSIT", Amount: 300},
+			{WalletID: first, OperationType: "WITHDRAW", Amount: 200},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, requests.BatchAtomic, resp.Mode)
+	assert.Equal(t, 3, resp.Succeeded)
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, models.Amount(300), *resp.Results[2].Balance)
+
+	_, err = storage.ProcessBatch(ctx, requests.BatchOperationRequest{
+		Mode: requests.BatchAtomic,
+		Operations: []requests.WalletOperationRequest{
+			{WalletID: second, OperationType: "WITHDRAW", Amount: 100},
+			{WalletID: first, OperationType: "WITHDRAW", Amount: 1000},
+		},
+	})
+	var batchItemErr requests.BatchItemError
+	require.True(t, errors.As(err, &batchItemErr))
+	assert.Equal(t, 1, batchItemErr.Index)
+	var insufficientFundsErr requests.InsufficientFundsError
+	assert.True(t, errors.As(err, &insufficientFundsErr))
+
+	firstBalance, firstSum, _ := walletState(t, storage, first)
+	secondBalance, secondSum, secondCount := walletState(t, storage, second)
+	assert.Equal(t, 300, firstBalance)
+	assert.Equal(t, firstSum, firstBalance)
+	assert.Equal(t, 300, secondBalance)
+	assert.Equal(t, secondSum, secondBalance)
+	assert.Equal(t, 1, secondCount)
+}
+
+func TestProcessBatchBestEffort(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	resp, err := storage.ProcessBatch(ctx, requests.BatchOperationRequest{
+		Mode: requests.BatchBestEffort,
+		Operations: []requests.WalletOperationRequest{
+			{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100},
+			{WalletID: walletID, OperationType: "WITHDRAW", Amount: 150},
+			{WalletID: walletID, OperationType: "DEPOSIT", Amount: 50, Currency: "EUR"},
+			{WalletID: walletID, OperationType: "WITHDRAW", Amount: 100},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 2, resp.Failed)
+	require.Len(t, resp.Results, 4)
+	assert.Equal(t, requests.StatusOK, resp.Results[0].Status)
+	assert.Equal(t, requests.StatusError, resp.Results[1].Status)
+	assert.ErrorAs(t, resp.Results[1].Err, &requests.InsufficientFundsError{})
+	assert.ErrorAs(t, resp.Results[2].Err, &requests.CurrencyMismatchError{})
+	assert.Equal(t, requests.StatusOK, resp.Results[3].Status)
+	assert.Equal(t, models.Amount(0), *resp.Results[3].Balance)
+
+	balance, operationsSum, operationsCount := walletState(t, storage, walletID)
+	assert.Equal(t, 0, balance)
+	assert.Equal(t, operationsSum, balance)
+	assert.Equal(t, 2, operationsCount)
+}
+
+func TestProcessBatchConcurrentOppositeOrder(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	first, second := uuid.New().String(), uuid.New().String()
+
+	const calls = 200
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			operations := []requests.WalletOperationRequest{
+				{WalletID: first, OperationType: "DEPOSIT", Amount: 1},
+				{WalletID: second, OperationType: "DEPOSIT", Amount: 1},
+			}
+			if i%2 == 1 {
+				operations[0], operations[1] = operations[1], operations[0]
+			}
+			if _, err := storage.ProcessBatch(ctx, requests.BatchOperationRequest{Operations: operations}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, walletID := range []string{first, second} {
+		balance, operationsSum, _ := walletState(t, storage, walletID)
+		assert.Equal(t, calls, balance)
+		assert.Equal(t, operationsSum, balance)
+	}
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// walletState returns the stored balance of a wallet with the sum and count of its operations.
+// It also checks that the wallet's ledger account agrees with the stored balance.
+func walletState(t *testing.T, s *Storage, walletID string) (balance int, operationsSum int, operationsCount int) {
+	t.Helper()
+
+	err := s.db.QueryRow(`SELECT balance FROM wallets WHERE wallet_id = $1`, walletID).Scan(&balance)
+	require.NoError(t, err)
+	var ledgerBalance int
+	err = s.db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = $1`, walletAccount(walletID)).Scan(&ledgerBalance)
+	require.NoError(t, err)
+	assert.Equal(t, balance, ledgerBalance, "ledger balance of wallet %s", walletID)
+	err = s.db.QueryRow(`
+    SELECT COALESCE(SUM(CASE type WHEN 'WITHDRAW' THEN -amount ELSE amount END), 0), COUNT(*)
+    FROM operations WHERE wallet_id = $1
+    `, walletID).Scan(&operationsSum, &operationsCount)
+	require.NoError(t, err)
+	return balance, operationsSum, operationsCount
+}
+
+func TestProcessOperationConcurrent(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	const calls = 4000
+	var succeeded, rejected atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := requests.WalletOperationRequest{
+				WalletID:      walletID,
+				OperationType: "DEPOSIT",
+				Amount:        models.Amount(1 + i%7),
+			}
+			if i%2 == 1 {
+				req.OperationType = "WITHDRAW"
+			}
+			_, err := storage.ProcessOperation(ctx, req)
+			var insufficientFundsErr requests.InsufficientFundsError
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case errors.As(err, &insufficientFundsErr):
+				rejected.Add(1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	balance, operationsSum, operationsCount := walletState(t, storage, walletID)
+	assert.Equal(t, operationsSum, balance)
+	assert.GreaterOrEqual(t, balance, 0)
+	assert.Equal(t, int(succeeded.Load()), operationsCount)
+	assert.Equal(t, int64(calls), succeeded.Load()+rejected.Load())
+}
+
+func TestProcessOperationConcurrentWithdrawNeverOverdraws(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{
+		WalletID:      walletID,
+		OperationType: "DEPOSIT",
+		Amount:        1000,
+	})))
+
+	const calls = 2000
+	var succeeded atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{
+				WalletID:      walletID,
+				OperationType: "WITHDRAW",
+				Amount:        1,
+			})
+			if err == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	balance, operationsSum, _ := walletState(t, storage, walletID)
+	assert.Equal(t, int64(1000), succeeded.Load())
+	assert.Equal(t, 0, balance)
+	assert.Equal(t, operationsSum, balance)
+}
+
+func TestTransferConcurrentOppositeDirections(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	first, second := uuid.New().String(), uuid.New().String()
+
+	for _, walletID := range []string{first, second} {
+		require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{
+			WalletID:      walletID,
+			OperationType: "DEPOSIT",
+			Amount:        500,
+		})))
+	}
+
+	const calls = 1000
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := requests.TransferRequest{FromWalletID: first, ToWalletID: second, Amount: 3}
+			if i%2 == 1 {
+				req = requests.TransferRequest{FromWalletID: second, ToWalletID: first, Amount: 3}
+			}
+			_, err := storage.Transfer(ctx, req)
+			var insufficientFundsErr requests.InsufficientFundsError
+			if err != nil && !errors.As(err, &insufficientFundsErr) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	firstBalance, firstSum, _ := walletState(t, storage, first)
+	secondBalance, secondSum, _ := walletState(t, storage, second)
+	assert.Equal(t, firstSum, firstBalance)
+	assert.Equal(t, secondSum, secondBalance)
+	assert.Equal(t, 1000, firstBalance+secondBalance)
+}
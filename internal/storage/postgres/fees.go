@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+const feeRuleColumns = `id, operation_type, tier, currency, fixed, rate_bps, min_fee, max_fee, created_at`
+
+// loadFeeRules reads a JSON array of fee rules from path, in the format POST /api/v1/admin/fee-rules accepts.
+// Earlier rules win ties between equally specific ones.
+func loadFeeRules(path string) ([]models.FeeRule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fee rules: %w", err)
+	}
+	var reqs []requests.FeeRuleRequest
+	if err = json.Unmarshal(raw, &reqs); err != nil {
+		return nil, fmt.Errorf("parse fee rules %s: %w", path, err)
+	}
+	rules := make([]models.FeeRule, 0, len(reqs))
+	for i, req := range reqs {
+		rule := req.Rule()
+		if err = rule.Validate(); err != nil {
+			return nil, fmt.Errorf("fee rule %d in %s: %w", i, path, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// feeRulesFor returns the fee rules for an operation type, in the order ties between them are broken in.
+func (s *Storage) feeRulesFor(ctx context.Context, tx *sql.Tx, operationType string) ([]models.FeeRule, error) {
+	if s.feeRulesFile != "" {
+		var rules []models.FeeRule
+		for _, rule := range s.feeRules {
+			if rule.OperationType == operationType {
+				rules = append(rules, rule)
+			}
+		}
+		return rules, nil
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT `+feeRuleColumns+` FROM fee_rules WHERE operation_type = $1 ORDER BY created_at, id`, operationType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanFeeRules(rows)
+}
+
+// feeWalletFor returns the fee-revenue wallet for the currency of the wallet req operates on,
+// or "" if no fee wallet is configured for it. The wallet's currency is read without a lock: it never changes.
+func (s *Storage) feeWalletFor(ctx context.Context, tx *sql.Tx, req requests.WalletOperationRequest) (string, error) {
+	currency := req.Currency
+	if currency == "" {
+		err := tx.QueryRowContext(ctx, "SELECT currency FROM wallets WHERE wallet_id = $1", req.WalletID).Scan(&currency)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", nil
+			}
+			return "", fmt.Errorf("get wallet currency error: %w", err)
+		}
+	}
+	return s.feeWallets[currency], nil
+}
+
+// operationFee returns the fee the most specific matching rule charges on req, or zero if no rule matches.
+func operationFee(rules []models.FeeRule, req requests.WalletOperationRequest, wallet *models.Wallets) (models.Amount, error) {
+	rule := models.SelectFeeRule(rules, req.OperationType, wallet)
+	if rule == nil {
+		return 0, nil
+	}
+	return rule.Fee(req.Amount)
+}
+
+// checkFeeWallet returns the locked fee-revenue wallet if it can be credited with a fee charged on payer.
+// A missing or unusable fee wallet is a configuration error, not the payer's fault.
+func checkFeeWallet(feeWallet, payer *models.Wallets) (*models.Wallets, error) {
+	if feeWallet == nil {
+		return nil, fmt.Errorf("no fee wallet for %s", payer.Currency)
+	}
+	if feeWallet.Currency != payer.Currency {
+		return nil, fmt.Errorf("fee wallet %s holds %s, not %s", feeWallet.WalletID, feeWallet.Currency, payer.Currency)
+	}
+	if checkWalletStatus(feeWallet, false) != nil {
+		return nil, fmt.Errorf("fee wallet %s is %s", feeWallet.WalletID, feeWallet.Status)
+	}
+	return feeWallet, nil
+}
+
+// chargeFee moves the fee of operation from payer to feeWallet as a WITHDRAW and a DEPOSIT that both point
+// back at operation, posted to the ledger as one FEE entry. Both wallets must be locked by the caller,
+// which is also in charge of the funds checks.
+func chargeFee(ctx context.Context, tx *sql.Tx, payer, feeWallet *models.Wallets, operation *models.Operation) error {
+	now, err := stampTime(ctx, tx)
+	if err != nil {
+		return err
+	}
+	legs := []struct {
+		wallet    *models.Wallets
+		operation models.Operation
+	}{
+		{payer, models.Operation{Type: "WITHDRAW"}},
+		{feeWallet, models.Operation{Type: "DEPOSIT"}},
+	}
+	for i := range legs {
+		leg := &legs[i]
+		leg.operation.ID = genUUID()
+		leg.operation.WalletID = leg.wallet.WalletID
+		leg.operation.Amount = operation.Fee
+		leg.operation.Timestamp = now
+		leg.operation.Currency = leg.wallet.Currency
+		leg.operation.FeeFor = &operation.ID
+
+		balance, err := leg.wallet.Balance.Add(leg.operation.SignedAmount())
+		if err != nil {
+			return err
+		}
+		leg.wallet.Balance = balance
+		_, err = tx.ExecContext(ctx, "UPDATE wallets SET balance = $1 WHERE wallet_id = $2", balance, leg.wallet.WalletID)
+		if err != nil {
+			return fmt.Errorf("update wallet error: %w", err)
+		}
+		if err = insertOperation(ctx, tx, leg.operation); err != nil {
+			return fmt.Errorf("insert operation error: %w", err)
+		}
+	}
+	err = postJournalEntry(ctx, tx, "FEE",
+		walletPosting(payer.WalletID, -operation.Fee, payer.Currency, legs[0].operation.ID),
+		walletPosting(feeWallet.WalletID, operation.Fee, feeWallet.Currency, legs[1].operation.ID),
+	)
+	if err != nil {
+		return fmt.Errorf("post journal entry error: %w", err)
+	}
+	return nil
+}
+
+// ListFeeRules returns the fee rules in force, from the rules file if one is configured.
+func (s *Storage) ListFeeRules(ctx context.Context) ([]models.FeeRule, error) {
+	op := "database.ListFeeRules"
+
+	if s.feeRulesFile != "" {
+		return s.feeRules, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+feeRuleColumns+` FROM fee_rules ORDER BY operation_type, created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+	rules, err := scanFeeRules(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return rules, nil
+}
+
+// CreateFeeRule adds a rule to the fee_rules table. It takes effect on the next operation.
+func (s *Storage) CreateFeeRule(ctx context.Context, req requests.FeeRuleRequest) (*models.FeeRule, error) {
+	op := "database.CreateFeeRule"
+
+	if s.feeRulesFile != "" {
+		return nil, requests.FeeRulesReadOnlyError{File: s.feeRulesFile}
+	}
+	rule := req.Rule()
+	rule.ID = genUUID()
+	rule.CreatedAt = time.Now().UTC()
+	_, err := s.db.ExecContext(ctx, `
+    INSERT INTO fee_rules (id, operation_type, tier, currency, fixed, rate_bps, min_fee, max_fee, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `, rule.ID, rule.OperationType, rule.Tier, rule.Currency, rule.Fixed, rule.RateBps, rule.MinFee, rule.MaxFee, rule.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &rule, nil
+}
+
+// DeleteFeeRule removes a rule from the fee_rules table and returns it.
+func (s *Storage) DeleteFeeRule(ctx context.Context, ruleID string) (*models.FeeRule, error) {
+	op := "database.DeleteFeeRule"
+
+	if s.feeRulesFile != "" {
+		return nil, requests.FeeRulesReadOnlyError{File: s.feeRulesFile}
+	}
+	row := s.db.QueryRowContext(ctx, `DELETE FROM fee_rules WHERE id = $1 RETURNING `+feeRuleColumns, ruleID)
+	rule, err := scanFeeRule(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: fee rule with id %s not found: %w", op, ruleID, err)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return rule, nil
+}
+
+// SetWalletTier moves a wallet to another tier, changing the fee rules that apply to it.
+func (s *Storage) SetWalletTier(ctx context.Context, walletID, tier string) (*requests.WalletBalanceResponse, error) {
+	op := "database.SetWalletTier"
+
+	res, err := s.db.ExecContext(ctx, "UPDATE wallets SET tier = $1 WHERE wallet_id = $2", tier, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if updated == 0 {
+		return nil, fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, sql.ErrNoRows)
+	}
+	return s.GetWalletBalance(ctx, walletID)
+}
+
+func scanFeeRules(rows *sql.Rows) ([]models.FeeRule, error) {
+	var rules []models.FeeRule
+	for rows.Next() {
+		rule, err := scanFeeRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func scanFeeRule(row scanner) (*models.FeeRule, error) {
+	var rule models.FeeRule
+	err := row.Scan(&rule.ID, &rule.OperationType, &rule.Tier, &rule.Currency, &rule.Fixed, &rule.RateBps,
+		&rule.MinFee, &rule.MaxFee, &rule.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
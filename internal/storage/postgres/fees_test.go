@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeRuleFee(t *testing.T) {
+	minFee, maxFee := models.Amount(50), models.Amount(500)
+	tests := []struct {
+		name     string
+		rule     models.FeeRule
+		amount   models.Amount
+		expected models.Amount
+	}{
+		{"fixed", models.FeeRule{Fixed: 25}, 1000, 25},
+		{"percentage rounds half up", models.FeeRule{RateBps: 150}, 1100, 17},
+		{"percentage rounds down below half", models.FeeRule{RateBps: 150}, 1030, 15},
+		{"fixed plus percentage", models.FeeRule{Fixed: 10, RateBps: 100}, 2000, 30},
+		{"raised to minimum", models.FeeRule{RateBps: 100, MinFee: &minFee}, 1000, 50},
+		{"capped at maximum", models.FeeRule{RateBps: 100, MaxFee: &maxFee}, 1000000, 500},
+		{"full rate on the largest amount", models.FeeRule{RateBps: models.MaxRateBps}, 1<<63 - 1, 1<<63 - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, err := tt.rule.Fee(tt.amount)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fee)
+		})
+	}
+
+	_, err := models.FeeRule{Fixed: 1, RateBps: models.MaxRateBps}.Fee(1<<63 - 1)
+	assert.ErrorIs(t, err, models.ErrAmountOverflow)
+}
+
+func TestSelectFeeRule(t *testing.T) {
+	gold, eur := "GOLD", "EUR"
+	rules := []models.FeeRule{
+		{ID: "any", OperationType: "WITHDRAW", Fixed: 30},
+		{ID: "any-later", OperationType: "WITHDRAW", Fixed: 40},
+		{ID: "eur", OperationType: "WITHDRAW", Currency: &eur, Fixed: 20},
+		{ID: "gold", OperationType: "WITHDRAW", Tier: &gold, Fixed: 10},
+		{ID: "gold-eur", OperationType: "WITHDRAW", Tier: &gold, Currency: &eur},
+	}
+	selected := func(tier, currency string) string {
+		rule := models.SelectFeeRule(rules, "WITHDRAW", &models.Wallets{Tier: tier, Currency: currency})
+		if rule == nil {
+			return ""
+		}
+		return rule.ID
+	}
+	assert.Equal(t, "any", selected(models.DefaultTier, "USD"))
+	assert.Equal(t, "eur", selected(models.DefaultTier, "EUR"))
+	assert.Equal(t, "gold", selected("GOLD", "USD"))
+	assert.Equal(t, "gold-eur", selected("GOLD", "EUR"))
+	assert.Nil(t, models.SelectFeeRule(rules, "DEPOSIT", &models.Wallets{Tier: models.DefaultTier, Currency: "USD"}))
+}
+
+func TestProcessOperationChargesFee(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	feeWallet, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	gold := "GOLD"
+	storage.feeWallets = map[string]string{"USD": feeWallet.WalletID}
+	storage.feeRulesFile = "fees.json"
+	storage.feeRules = []models.FeeRule{
+		{OperationType: "WITHDRAW", Fixed: 10, RateBps: 100},
+		{OperationType: "WITHDRAW", Tier: &gold},
+	}
+	walletID := uuid.New().String()
+
+	deposit, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(0), deposit.Fee)
+
+	withdrawal, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 200})
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(12), withdrawal.Fee)
+
+	// The fee has to be covered on top of the amount.
+	_, err = storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 788})
+	assert.ErrorAs(t, err, &requests.InsufficientFundsError{})
+
+	_, err = storage.SetWalletTier(ctx, walletID, gold)
+	require.NoError(t, err)
+	free, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 88})
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(0), free.Fee)
+
+	page, err := storage.ListOperations(ctx, requests.OperationsFilter{WalletID: feeWallet.WalletID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Operations, 1)
+	feeLeg := page.Operations[0]
+	assert.Equal(t, "DEPOSIT", feeLeg.OperationType)
+	assert.Equal(t, models.Amount(12), feeLeg.Amount)
+	assert.Equal(t, withdrawal.ID, *feeLeg.FeeFor)
+
+	_, err = storage.ReverseOperation(ctx, feeLeg.ID, 0)
+	assert.ErrorAs(t, err, &requests.OperationNotReversibleError{})
+
+	balance, operationsSum, operationsCount := walletState(t, storage, walletID)
+	assert.Equal(t, 700, balance)
+	assert.Equal(t, operationsSum, balance)
+	assert.Equal(t, 4, operationsCount)
+	balance, operationsSum, _ = walletState(t, storage, feeWallet.WalletID)
+	assert.Equal(t, 12, balance)
+	assert.Equal(t, operationsSum, balance)
+}
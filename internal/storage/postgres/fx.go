@@ -0,0 +1,300 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+const fxAccount = "fx_position" // Currency the house bought and sold through exchanges
+
+const fxRateColumns = `id, from_currency, to_currency, rate, spread_bps, valid_from, valid_to, created_at`
+
+const fxQuoteColumns = `id, rate_id, from_currency, to_currency, rate, spread_bps, expires_at, used_at, created_at`
+
+// ImportFXRates validates rates and stores them all in one transaction, or none if any is invalid.
+// Rates without a start are valid from now. A later rate for the same pair takes over from where it starts.
+func (s *Storage) ImportFXRates(ctx context.Context, rates []models.FXRate) ([]models.FXRate, error) {
+	op := "database.ImportFXRates"
+
+	now := time.Now().UTC()
+	imported := make([]models.FXRate, 0, len(rates))
+	for i, rate := range rates {
+		if rate.ValidFrom.IsZero() {
+			rate.ValidFrom = now
+		}
+		if err := rate.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: rate %d: %w", op, i, err)
+		}
+		rate.Rate, _ = models.NormalizeRate(rate.Rate)
+		rate.ID = genUUID()
+		rate.CreatedAt = now
+		imported = append(imported, rate)
+	}
+
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		for _, rate := range imported {
+			_, err := tx.ExecContext(ctx, `
+    INSERT INTO fx_rates (id, from_currency, to_currency, rate, spread_bps, valid_from, valid_to, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    `, rate.ID, rate.FromCurrency, rate.ToCurrency, rate.Rate, rate.SpreadBps, rate.ValidFrom.UTC(), rate.ValidTo, rate.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// ListFXRates returns the FX rates loaded for a pair, or for every pair when the currencies are empty,
+// latest first.
+func (s *Storage) ListFXRates(ctx context.Context, fromCurrency, toCurrency string) ([]models.FXRate, error) {
+	op := "database.ListFXRates"
+
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if fromCurrency != "" {
+		args = append(args, fromCurrency)
+		conditions = append(conditions, fmt.Sprintf("from_currency = $%d", len(args)))
+	}
+	if toCurrency != "" {
+		args = append(args, toCurrency)
+		conditions = append(conditions, fmt.Sprintf("to_currency = $%d", len(args)))
+	}
+	rows, err := s.db.QueryContext(ctx, `
+    SELECT `+fxRateColumns+` FROM fx_rates
+    WHERE `+strings.Join(conditions, " AND ")+`
+    ORDER BY from_currency, to_currency, valid_from DESC, created_at DESC
+    `, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	rates := make([]models.FXRate, 0)
+	for rows.Next() {
+		rate, err := scanFXRate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		rates = append(rates, *rate)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return rates, nil
+}
+
+// CreateFXQuote locks the rate in force now between two currencies for the quote TTL.
+func (s *Storage) CreateFXQuote(ctx context.Context, req requests.FXQuoteRequest) (*requests.FXQuoteResponse, error) {
+	op := "database.CreateFXQuote"
+
+	now := time.Now().UTC()
+	// Of the rates valid now, the one that started last is in force.
+	row := s.db.QueryRowContext(ctx, `
+    SELECT `+fxRateColumns+` FROM fx_rates
+    WHERE from_currency = $1 AND to_currency = $2 AND valid_from <= $3 AND (valid_to IS NULL OR valid_to > $3)
+    ORDER BY valid_from DESC, created_at DESC
+    LIMIT 1
+    `, req.FromCurrency, req.ToCurrency, now)
+	rate, err := scanFXRate(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, requests.FXRateNotFoundError{FromCurrency: req.FromCurrency, ToCurrency: req.ToCurrency}
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp := &requests.FXQuoteResponse{
+		QuoteID:      genUUID(),
+		FromCurrency: rate.FromCurrency,
+		ToCurrency:   rate.ToCurrency,
+		Rate:         rate.Rate,
+		SpreadBps:    rate.SpreadBps,
+		Amount:       req.Amount,
+		ExpiresAt:    now.Add(s.fxQuoteTTL),
+	}
+	if req.Amount > 0 {
+		if resp.ConvertedAmount, err = models.Convert(req.Amount, rate.Rate, rate.SpreadBps); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	_, err = s.db.ExecContext(ctx, `
+    INSERT INTO fx_quotes (id, rate_id, from_currency, to_currency, rate, spread_bps, expires_at, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    `, resp.QuoteID, rate.ID, rate.FromCurrency, rate.ToCurrency, rate.Rate, rate.SpreadBps, resp.ExpiresAt, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+// Exchange converts req.Amount from one wallet into another in a different currency at the rate and spread
+// of req.QuoteID, in a single transaction that also uses up the quote. Both legs are recorded in operations
+// as a WITHDRAW and a DEPOSIT sharing the exchange id as transfer id and carrying the rate and spread.
+// The ledger routes them through the FX position account of each currency.
+func (s *Storage) Exchange(ctx context.Context, req requests.ExchangeRequest) (*requests.ExchangeResponse, error) {
+	op := "database.Exchange"
+
+	var resp *requests.ExchangeResponse
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		wallets, err := lockWallets(ctx, tx, req.FromWalletID, req.ToWalletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallets error: %w", op, err)
+		}
+		from, ok := wallets[req.FromWalletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, req.FromWalletID, sql.ErrNoRows)
+		}
+		to, ok := wallets[req.ToWalletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, req.ToWalletID, sql.ErrNoRows)
+		}
+
+		quote, err := scanFXQuote(tx.QueryRowContext(ctx, `SELECT `+fxQuoteColumns+` FROM fx_quotes WHERE id = $1 FOR UPDATE`, req.QuoteID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return requests.QuoteNotUsableError{Reason: "no such quote"}
+			}
+			return fmt.Errorf("%s: get quote error: %w", op, err)
+		}
+		now, err := stampTime(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		switch {
+		case quote.UsedAt != nil:
+			return requests.QuoteNotUsableError{Reason: "it was already used"}
+		case !now.Before(quote.ExpiresAt):
+			return requests.QuoteNotUsableError{Reason: "it expired"}
+		case quote.FromCurrency != from.Currency || quote.ToCurrency != to.Currency:
+			return requests.QuoteNotUsableError{Reason: fmt.Sprintf("it is from %s to %s", quote.FromCurrency, quote.ToCurrency)}
+		}
+
+		if err = checkWalletStatus(from, true); err != nil {
+			return err
+		}
+		if err = checkWalletStatus(to, false); err != nil {
+			return err
+		}
+		if err = s.checkWithdrawalLimits(ctx, tx, req.FromWalletID, req.Amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		headroom, err := spendable(ctx, tx, from)
+		if err != nil {
+			return fmt.Errorf("%s: sum holds error: %w", op, err)
+		}
+		if headroom < req.Amount {
+			return requests.InsufficientFundsError{Headroom: headroom}
+		}
+		converted, err := models.Convert(req.Amount, quote.Rate, quote.SpreadBps)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if to.Balance, err = to.Balance.Add(converted); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		from.Balance -= req.Amount
+
+		for _, wallet := range []*models.Wallets{from, to} {
+			_, err = tx.ExecContext(ctx, "UPDATE wallets SET balance = $1 WHERE wallet_id = $2", wallet.Balance, wallet.WalletID)
+			if err != nil {
+				return fmt.Errorf("%s: update wallet error: %w", op, err)
+			}
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE fx_quotes SET used_at = $1 WHERE id = $2", now, quote.ID)
+		if err != nil {
+			return fmt.Errorf("%s: use quote error: %w", op, err)
+		}
+
+		exchangeID := genUUID()
+		legs := []models.Operation{
+			{
+				ID:           genUUID(),
+				WalletID:     req.FromWalletID,
+				Type:         "WITHDRAW",
+				Amount:       req.Amount,
+				Timestamp:    now,
+				TransferID:   &exchangeID,
+				Currency:     from.Currency,
+				ExchangeRate: &quote.Rate,
+				SpreadBps:    &quote.SpreadBps,
+			},
+			{
+				ID:           genUUID(),
+				WalletID:     req.ToWalletID,
+				Type:         "DEPOSIT",
+				Amount:       converted,
+				Timestamp:    now,
+				TransferID:   &exchangeID,
+				Currency:     to.Currency,
+				ExchangeRate: &quote.Rate,
+				SpreadBps:    &quote.SpreadBps,
+			},
+		}
+		for _, leg := range legs {
+			if err = insertOperation(ctx, tx, leg); err != nil {
+				return fmt.Errorf("%s: insert operation error: %w", op, err)
+			}
+		}
+		err = postJournalEntry(ctx, tx, "EXCHANGE",
+			walletPosting(from.WalletID, -req.Amount, from.Currency, legs[0].ID),
+			systemPosting(fxAccount, req.Amount, from.Currency, legs[0].ID),
+			systemPosting(fxAccount, -converted, to.Currency, legs[1].ID),
+			walletPosting(to.WalletID, converted, to.Currency, legs[1].ID),
+		)
+		if err != nil {
+			return fmt.Errorf("%s: post journal entry error: %w", op, err)
+		}
+
+		resp = &requests.ExchangeResponse{
+			ExchangeID:      exchangeID,
+			QuoteID:         quote.ID,
+			FromWalletID:    req.FromWalletID,
+			ToWalletID:      req.ToWalletID,
+			Amount:          req.Amount,
+			ConvertedAmount: converted,
+			FromCurrency:    from.Currency,
+			ToCurrency:      to.Currency,
+			Rate:            quote.Rate,
+			SpreadBps:       quote.SpreadBps,
+			FromBalance:     from.Balance,
+			ToBalance:       to.Balance,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func scanFXRate(row scanner) (*models.FXRate, error) {
+	var rate models.FXRate
+	err := row.Scan(&rate.ID, &rate.FromCurrency, &rate.ToCurrency, &rate.Rate, &rate.SpreadBps, &rate.ValidFrom,
+		&rate.ValidTo, &rate.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func scanFXQuote(row scanner) (*models.FXQuote, error) {
+	var quote models.FXQuote
+	err := row.Scan(&quote.ID, &quote.RateID, &quote.FromCurrency, &quote.ToCurrency, &quote.Rate, &quote.SpreadBps,
+		&quote.ExpiresAt, &quote.UsedAt, &quote.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeRate(t *testing.T) {
+	valid := map[string]string{
+		"1":              "1",
+		"0.9200":         "0.92",
+		"007.5":          "7.5",
+		"1.000000000001": "1.000000000001",
+	}
+	for rate, expected := range valid {
+		normalized, err := models.NormalizeRate(rate)
+		require.NoError(t, err, rate)
+		assert.Equal(t, expected, normalized)
+	}
+	for _, rate := range []string{"", "0", "0.000", "-1", "1e3", ".5", "1.0000000000001"} {
+		_, err := models.NormalizeRate(rate)
+		assert.Error(t, err, rate)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    models.Amount
+		rate      string
+		spreadBps int64
+		expected  models.Amount
+	}{
+		{"exact", 10000, "0.92", 0, 9200},
+		{"spread", 10000, "0.92", 50, 9154},
+		{"rounds down", 333, "1.5", 0, 499},
+		{"into a currency without minor units", 100, "1.5", 0, 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := models.Convert(tt.amount, tt.rate, tt.spreadBps)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, converted)
+		})
+	}
+
+	_, err := models.Convert(1, "0.5", 0)
+	assert.ErrorIs(t, err, models.ErrConvertedTooSmall)
+	_, err = models.Convert(1<<62, "4", 0)
+	assert.ErrorIs(t, err, models.ErrAmountOverflow)
+}
+
+func TestExchange(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	usd, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{Currency: "USD"})
+	require.NoError(t, err)
+	eur, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{Currency: "EUR"})
+	require.NoError(t, err)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: usd.WalletID, OperationType: "DEPOSIT", Amount: 10000})))
+
+	ended := time.Now().UTC().Add(-time.Hour)
+	_, err = storage.ImportFXRates(ctx, []models.FXRate{
+		{FromCurrency: "USD", ToCurrency: "EUR", Rate: "0.5", ValidFrom: ended.Add(-time.Hour), ValidTo: &ended},
+		{FromCurrency: "USD", ToCurrency: "EUR", Rate: "0.9200", SpreadBps: 50},
+	})
+	require.NoError(t, err)
+
+	quote, err := storage.CreateFXQuote(ctx, requests.FXQuoteRequest{FromCurrency: "USD", ToCurrency: "EUR", Amount: 10000})
+	require.NoError(t, err)
+	assert.Equal(t, "0.92", quote.Rate)
+	assert.Equal(t, models.Amount(9154), quote.ConvertedAmount)
+
+	_, err = storage.CreateFXQuote(ctx, requests.FXQuoteRequest{FromCurrency: "EUR", ToCurrency: "XTS"})
+	assert.ErrorAs(t, err, &requests.FXRateNotFoundError{})
+
+	// The quote is for USD to EUR, not the other way round.
+	_, err = storage.Exchange(ctx, requests.ExchangeRequest{FromWalletID: eur.WalletID, ToWalletID: usd.WalletID, Amount: 1, QuoteID: quote.QuoteID})
+	assert.ErrorAs(t, err, &requests.QuoteNotUsableError{})
+
+	exchange, err := storage.Exchange(ctx, requests.ExchangeRequest{FromWalletID: usd.WalletID, ToWalletID: eur.WalletID, Amount: 10000, QuoteID: quote.QuoteID})
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(9154), exchange.ConvertedAmount)
+	assert.Equal(t, models.Amount(0), exchange.FromBalance)
+	assert.Equal(t, models.Amount(9154), exchange.ToBalance)
+
+	_, err = storage.Exchange(ctx, requests.ExchangeRequest{FromWalletID: usd.WalletID, ToWalletID: eur.WalletID, Amount: 1, QuoteID: quote.QuoteID})
+	assert.ErrorAs(t, err, &requests.QuoteNotUsableError{})
+
+	for _, walletID := range []string{usd.WalletID, eur.WalletID} {
+		page, err := storage.ListOperations(ctx, requests.OperationsFilter{WalletID: walletID, Limit: 10})
+		require.NoError(t, err)
+		leg := page.Operations[0]
+		require.NotNil(t, leg.ExchangeRate)
+		require.NotNil(t, leg.SpreadBps)
+		assert.Equal(t, "0.92", *leg.ExchangeRate)
+		assert.Equal(t, int64(50), *leg.SpreadBps)
+		assert.Equal(t, exchange.ExchangeID, *leg.TransferID)
+
+		balance, operationsSum, _ := walletState(t, storage, walletID)
+		assert.Equal(t, operationsSum, balance)
+	}
+
+	report, err := storage.VerifyLedger(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, report.Totals["USD"])
+	assert.Zero(t, report.Totals["EUR"])
+}
@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+const holdColumns = `id, wallet_id, amount, captured_amount, currency, status, operation_id, expires_at, created_at, updated_at`
+
+// heldAmount returns the sum of a wallet's active holds that have not expired yet.
+// Inside a transaction, callers must hold the wallet row lock for the result to stay valid.
+func heldAmount(ctx context.Context, q queryer, walletID string) (models.Amount, error) {
+	var held models.Amount
+	err := q.QueryRowContext(ctx, `
+    SELECT COALESCE(SUM(amount), 0) FROM holds
+    WHERE wallet_id = $1 AND status = $2 AND expires_at > $3
+    `, walletID, models.HoldActive, time.Now().UTC()).Scan(&held)
+	return held, err
+}
+
+// CreateHold reserves req.Amount on an existing wallet, reducing its available balance until the hold
+// is captured, voided or expires.
+func (s *Storage) CreateHold(ctx context.Context, req requests.CreateHoldRequest) (*models.Hold, error) {
+	op := "database.CreateHold"
+
+	ttl := s.holdTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	var hold *models.Hold
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		wallets, err := lockWallets(ctx, tx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallet error: %w", op, err)
+		}
+		wallet, ok := wallets[req.WalletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, req.WalletID, sql.ErrNoRows)
+		}
+		if req.Currency != "" && req.Currency != wallet.Currency {
+			return requests.CurrencyMismatchError{WalletCurrency: wallet.Currency, Currency: req.Currency}
+		}
+		if err = checkWalletStatus(wallet, true); err != nil {
+			return err
+		}
+
+		headroom, err := spendable(ctx, tx, wallet)
+		if err != nil {
+			return fmt.Errorf("%s: sum holds error: %w", op, err)
+		}
+		if headroom < req.Amount {
+			return requests.InsufficientFundsError{Headroom: headroom}
+		}
+
+		now := time.Now().UTC()
+		hold = &models.Hold{
+			ID:        genUUID(),
+			WalletID:  wallet.WalletID,
+			Amount:    req.Amount,
+			Currency:  wallet.Currency,
+			Status:    models.HoldActive,
+			ExpiresAt: now.Add(ttl),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		_, err = tx.ExecContext(ctx, `
+    INSERT INTO holds (`+holdColumns+`)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `, hold.ID, hold.WalletID, hold.Amount, hold.CapturedAmount, hold.Currency, hold.Status, hold.OperationID, hold.ExpiresAt, hold.CreatedAt, hold.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("%s: insert hold error: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (s *Storage) GetHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	op := "database.GetHold"
+
+	hold, err := scanHold(s.db.QueryRowContext(ctx, `SELECT `+holdColumns+` FROM holds WHERE id = $1`, holdID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return hold, nil
+}
+
+// CaptureHold turns amount of an active hold into a WITHDRAW; zero captures the full hold.
+// Any remainder of a partial capture is released.
+func (s *Storage) CaptureHold(ctx context.Context, holdID string, amount models.Amount) (*models.Hold, error) {
+	op := "database.CaptureHold"
+
+	var hold *models.Hold
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var err error
+		hold, err = lockHold(ctx, tx, holdID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return requests.CaptureExceedsHoldError{Held: hold.Amount}
+		}
+
+		// The hold itself no longer counts against the balance once it is being captured.
+		wallets, err := lockWallets(ctx, tx, hold.WalletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallet error: %w", op, err)
+		}
+		wallet := wallets[hold.WalletID]
+		if err = checkWalletStatus(wallet, true); err != nil {
+			return err
+		}
+		if err = s.checkWithdrawalLimits(ctx, tx, hold.WalletID, amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		// The hold being captured already reserves its amount, so it counts towards the headroom.
+		headroom, err := spendable(ctx, tx, wallet)
+		if err != nil {
+			return fmt.Errorf("%s: sum holds error: %w", op, err)
+		}
+		headroom += hold.Amount
+		if headroom < amount {
+			return requests.InsufficientFundsError{Headroom: headroom}
+		}
+
+		operation := &models.Operation{Type: "WITHDRAW", Amount: amount}
+		if err = applyOperation(ctx, tx, wallet, operation, externalCashAccount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		hold.Status = models.HoldCaptured
+		hold.CapturedAmount = amount
+		hold.OperationID = &operation.ID
+		hold.UpdatedAt = time.Now().UTC()
+		_, err = tx.ExecContext(ctx, `
+    UPDATE holds SET status = $1, captured_amount = $2, operation_id = $3, updated_at = $4 WHERE id = $5
+    `, hold.Status, hold.CapturedAmount, hold.OperationID, hold.UpdatedAt, hold.ID)
+		if err != nil {
+			return fmt.Errorf("%s: update hold error: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// VoidHold releases an active hold without moving money.
+func (s *Storage) VoidHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	op := "database.VoidHold"
+
+	var hold *models.Hold
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var err error
+		hold, err = lockHold(ctx, tx, holdID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		hold.Status = models.HoldVoided
+		hold.UpdatedAt = time.Now().UTC()
+		_, err = tx.ExecContext(ctx, "UPDATE holds SET status = $1, updated_at = $2 WHERE id = $3", hold.Status, hold.UpdatedAt, hold.ID)
+		if err != nil {
+			return fmt.Errorf("%s: update hold error: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// ExpireHolds marks active holds past their expiry as EXPIRED and returns how many were expired.
+// Expired holds stop counting against the available balance even before this runs.
+func (s *Storage) ExpireHolds(ctx context.Context) (int, error) {
+	op := "database.ExpireHolds"
+
+	now := time.Now().UTC()
+	res, err := s.db.ExecContext(ctx, `
+    UPDATE holds SET status = $1, updated_at = $2 WHERE status = $3 AND expires_at <= $2
+    `, models.HoldExpired, now, models.HoldActive)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	expired, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return int(expired), nil
+}
+
+// lockHold locks the wallet of a hold and then the hold itself, the same order CreateHold uses,
+// and checks that the hold can still be captured or voided.
+func lockHold(ctx context.Context, tx *sql.Tx, holdID string) (*models.Hold, error) {
+	var walletID string
+	err := tx.QueryRowContext(ctx, "SELECT wallet_id FROM holds WHERE id = $1", holdID).Scan(&walletID)
+	if err != nil {
+		return nil, fmt.Errorf("hold with id %s not found: %w", holdID, err)
+	}
+	if _, err = lockWallets(ctx, tx, walletID); err != nil {
+		return nil, fmt.Errorf("lock wallet error: %w", err)
+	}
+	hold, err := scanHold(tx.QueryRowContext(ctx, `SELECT `+holdColumns+` FROM holds WHERE id = $1 FOR UPDATE`, holdID))
+	if err != nil {
+		return nil, fmt.Errorf("lock hold error: %w", err)
+	}
+	if hold.Status == models.HoldActive && !hold.ExpiresAt.After(time.Now().UTC()) {
+		hold.Status = models.HoldExpired
+	}
+	if hold.Status != models.HoldActive {
+		return nil, requests.HoldNotActiveError{Status: hold.Status}
+	}
+	return hold, nil
+}
+
+func scanHold(row *sql.Row) (*models.Hold, error) {
+	var hold models.Hold
+	err := row.Scan(&hold.ID, &hold.WalletID, &hold.Amount, &hold.CapturedAmount, &hold.Currency, &hold.Status,
+		&hold.OperationID, &hold.ExpiresAt, &hold.CreatedAt, &hold.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldLifecycle(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 500})))
+
+	hold, err := storage.CreateHold(ctx, requests.CreateHoldRequest{WalletID: walletID, Amount: 300})
+	require.NoError(t, err)
+
+	balance, err := storage.GetWalletBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(500), balance.Balance)
+	assert.Equal(t, models.Amount(200), balance.Available)
+
+	_, err = storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 201})
+	assert.ErrorAs(t, err, &requests.InsufficientFundsError{})
+	_, err = storage.CreateHold(ctx, requests.CreateHoldRequest{WalletID: walletID, Amount: 201})
+	assert.ErrorAs(t, err, &requests.InsufficientFundsError{})
+
+	_, err = storage.CaptureHold(ctx, hold.ID, 301)
+	assert.ErrorAs(t, err, &requests.CaptureExceedsHoldError{})
+
+	captured, err := storage.CaptureHold(ctx, hold.ID, 120)
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldCaptured, captured.Status)
+	assert.Equal(t, models.Amount(120), captured.CapturedAmount)
+	require.NotNil(t, captured.OperationID)
+
+	_, err = storage.VoidHold(ctx, hold.ID)
+	assert.ErrorAs(t, err, &requests.HoldNotActiveError{})
+
+	balance, err = storage.GetWalletBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(380), balance.Balance)
+	assert.Equal(t, models.Amount(380), balance.Available)
+
+	ledgerBalance, operationsSum, _ := walletState(t, storage, walletID)
+	assert.Equal(t, 380, ledgerBalance)
+	assert.Equal(t, 380, operationsSum)
+}
+
+func TestVoidAndExpireHolds(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100})))
+
+	voided, err := storage.CreateHold(ctx, requests.CreateHoldRequest{WalletID: walletID, Amount: 40})
+	require.NoError(t, err)
+	voided, err = storage.VoidHold(ctx, voided.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldVoided, voided.Status)
+
+	expiring, err := storage.CreateHold(ctx, requests.CreateHoldRequest{WalletID: walletID, Amount: 60})
+	require.NoError(t, err)
+	_, err = storage.db.Exec("UPDATE holds SET expires_at = $1 WHERE id = $2", time.Now().UTC().Add(-time.Second), expiring.ID)
+	require.NoError(t, err)
+
+	balance, err := storage.GetWalletBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(100), balance.Available, "expired holds must not reduce the available balance")
+
+	_, err = storage.CaptureHold(ctx, expiring.ID, 0)
+	assert.ErrorAs(t, err, &requests.HoldNotActiveError{})
+
+	expired, err := storage.ExpireHolds(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, expired, 1)
+	hold, err := storage.GetHold(ctx, expiring.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldExpired, hold.Status)
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+)
+
+// ProcessOperationIdempotent applies req at most once per idempotency key.
+// The first successful call stores its response in the same transaction as the operation;
+// later calls with the same key and fingerprint get that response back without moving money again.
+// Failed calls are not recorded, so a retry after e.g. insufficient funds is evaluated anew.
+func (s *Storage) ProcessOperationIdempotent(ctx context.Context, req requests.WalletOperationRequest, key, fingerprint string) (*requests.StoredResponse, error) {
+	op := "database.ProcessOperationIdempotent"
+
+	stored, err := s.getIdempotentResponse(ctx, key, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if stored != nil {
+		return stored, nil
+	}
+
+	var resp *requests.StoredResponse
+	err = s.withTx(ctx, op, func(tx *sql.Tx) error {
+		wallet, operation, err := s.processOperation(ctx, tx, req)
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(requests.WalletOperationResponseOK(requests.WalletOperationResult{
+			WalletID:      req.WalletID,
+			OperationType: req.OperationType,
+			Balance:       wallet.Balance,
+			Currency:      wallet.Currency,
+			Fee:           operation.Fee,
+		}))
+		if err != nil {
+			return fmt.Errorf("%s: marshal response error: %w", op, err)
+		}
+		resp = &requests.StoredResponse{
+			Status: http.StatusOK,
+			Body:   body,
+		}
+		_, err = tx.ExecContext(ctx, `
+    INSERT INTO idempotency_keys (key, fingerprint, response_status, response_body, created_at)
+    VALUES ($1, $2, $3, $4, $5)
+    `, key, fingerprint, resp.Status, resp.Body, time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		// A concurrent request with the same key committed first; our transaction was rolled back.
+		if isUniqueViolation(err, "idempotency_keys_pkey") {
+			stored, err = s.getIdempotentResponse(ctx, key, fingerprint)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			if stored != nil {
+				return stored, nil
+			}
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// getIdempotentResponse returns the response stored under key, or nil if the key is unused.
+func (s *Storage) getIdempotentResponse(ctx context.Context, key, fingerprint string) (*requests.StoredResponse, error) {
+	var storedFingerprint string
+	resp := requests.StoredResponse{Replayed: true}
+	err := s.db.QueryRowContext(ctx, `
+    SELECT fingerprint, response_status, response_body FROM idempotency_keys WHERE key = $1
+    `, key).Scan(&storedFingerprint, &resp.Status, &resp.Body)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if storedFingerprint != fingerprint {
+		return nil, requests.IdempotencyKeyConflictError{}
+	}
+	return &resp, nil
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessOperationIdempotent(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+	key := uuid.New().String()
+	req := requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100}
+
+	first, err := storage.ProcessOperationIdempotent(ctx, req, key, "fingerprint-a")
+	require.NoError(t, err)
+	assert.False(t, first.Replayed)
+
+	replay, err := storage.ProcessOperationIdempotent(ctx, req, key, "fingerprint-a")
+	require.NoError(t, err)
+	assert.True(t, replay.Replayed)
+	assert.Equal(t, first.Status, replay.Status)
+	// The replay is served as stored, so it must be the very bytes first sent.
+	assert.Equal(t, first.Body, replay.Body)
+
+	_, err = storage.ProcessOperationIdempotent(ctx, req, key, "fingerprint-b")
+	var conflictErr requests.IdempotencyKeyConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+
+	balance, _, operationsCount := walletState(t, storage, walletID)
+	assert.Equal(t, 100, balance)
+	assert.Equal(t, 1, operationsCount)
+}
+
+func TestProcessOperationIdempotentConcurrentRetries(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+	key := uuid.New().String()
+	req := requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := storage.ProcessOperationIdempotent(ctx, req, key, "fingerprint"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	balance, _, operationsCount := walletState(t, storage, walletID)
+	assert.Equal(t, 100, balance)
+	assert.Equal(t, 1, operationsCount)
+}
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+const interestExpenseAccount = "interest_expense" // Interest paid out to wallets
+
+const interestAccrualColumns = `wallet_id, day, balance, rate_bps, accrued_micros, paid_at, operation_id, created_at`
+
+// SetInterestRate changes the annual rate a wallet earns interest at, from the next day accrued on.
+func (s *Storage) SetInterestRate(ctx context.Context, walletID string, rateBps int64) (*requests.WalletBalanceResponse, error) {
+	op := "database.SetInterestRate"
+
+	res, err := s.db.ExecContext(ctx, "UPDATE wallets SET interest_rate_bps = $1 WHERE wallet_id = $2", rateBps, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if updated == 0 {
+		return nil, fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, sql.ErrNoRows)
+	}
+	return s.GetWalletBalance(ctx, walletID)
+}
+
+// RunInterest accrues interest for day and pays out every month that ended by the end of it.
+// Both steps skip what was already done, so running a day again never credits twice.
+func (s *Storage) RunInterest(ctx context.Context, day time.Time) (*requests.InterestRunResponse, error) {
+	op := "database.RunInterest"
+
+	day = startOfDay(day)
+	accrued, err := s.AccrueInterest(ctx, day)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	nextDay := day.AddDate(0, 0, 1)
+	paid, err := s.PayInterest(ctx, time.Date(nextDay.Year(), nextDay.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &requests.InterestRunResponse{
+		Day:     day.Format(time.DateOnly),
+		Accrued: accrued,
+		Paid:    paid,
+	}, nil
+}
+
+// AccrueInterest records the interest every open wallet with a rate earned on day, on its balance
+// at the end of the day, and returns how many accruals were recorded. Wallets already accrued for day are skipped.
+// It must only run once day is over and the operations stamped within it have committed.
+func (s *Storage) AccrueInterest(ctx context.Context, day time.Time) (int, error) {
+	op := "database.AccrueInterest"
+
+	day = startOfDay(day)
+	rows, err := s.db.QueryContext(ctx, `
+    SELECT wallet_id, interest_rate_bps FROM wallets
+    WHERE interest_rate_bps > 0 AND status <> $2
+        AND NOT EXISTS (SELECT 1 FROM interest_accruals WHERE interest_accruals.wallet_id = wallets.wallet_id AND day = $1)
+    ORDER BY wallet_id
+    `, day, models.WalletClosed)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	var due []models.InterestAccrual
+	for rows.Next() {
+		accrual := models.InterestAccrual{Day: day}
+		if err = rows.Scan(&accrual.WalletID, &accrual.RateBps); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		due = append(due, accrual)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	accrued := 0
+	for _, accrual := range due {
+		accrual.Balance, err = balanceAt(ctx, s.db, accrual.WalletID, day.AddDate(0, 0, 1), false)
+		if err != nil {
+			return accrued, fmt.Errorf("%s: end-of-day balance error: %w", op, err)
+		}
+		accrual.AccruedMicros, err = models.DailyInterest(accrual.Balance, accrual.RateBps)
+		if err != nil {
+			return accrued, fmt.Errorf("%s: wallet %s: %w", op, accrual.WalletID, err)
+		}
+		res, err := s.db.ExecContext(ctx, `
+    INSERT INTO interest_accruals (wallet_id, day, balance, rate_bps, accrued_micros, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6)
+    ON CONFLICT (wallet_id, day) DO NOTHING
+    `, accrual.WalletID, accrual.Day, accrual.Balance, accrual.RateBps, accrual.AccruedMicros, time.Now().UTC())
+		if err != nil {
+			return accrued, fmt.Errorf("%s: %w", op, err)
+		}
+		if inserted, _ := res.RowsAffected(); inserted > 0 {
+			accrued++
+		}
+	}
+	return accrued, nil
+}
+
+// PayInterest credits every wallet with the interest it accrued on days before the given time and not paid yet,
+// one INTEREST operation per wallet and month, and returns how many operations were posted.
+// Each month of accruals is summed and then rounded to minor units half to even.
+func (s *Storage) PayInterest(ctx context.Context, before time.Time) (int, error) {
+	op := "database.PayInterest"
+
+	rows, err := s.db.QueryContext(ctx, `
+    SELECT DISTINCT wallet_id, date_trunc('month', day)::date FROM interest_accruals
+    WHERE paid_at IS NULL AND day < $1
+    ORDER BY 2, 1
+    `, startOfDay(before))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	type unpaidMonth struct {
+		walletID string
+		month    time.Time
+	}
+	var unpaid []unpaidMonth
+	for rows.Next() {
+		var u unpaidMonth
+		if err = rows.Scan(&u.walletID, &u.month); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		unpaid = append(unpaid, u)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	paid := 0
+	for _, u := range unpaid {
+		posted, err := s.payInterest(ctx, u.walletID, u.month)
+		if err != nil {
+			return paid, fmt.Errorf("%s: %w", op, err)
+		}
+		if posted {
+			paid++
+		}
+	}
+	return paid, nil
+}
+
+// payInterest pays out a wallet's unpaid accruals of one month and reports whether an operation was posted.
+// Months that round to zero are marked paid without one. Closed wallets are left alone.
+func (s *Storage) payInterest(ctx context.Context, walletID string, month time.Time) (bool, error) {
+	op := "database.payInterest"
+
+	posted := false
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		wallets, err := lockWallets(ctx, tx, walletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallet error: %w", op, err)
+		}
+		wallet, ok := wallets[walletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, sql.ErrNoRows)
+		}
+		if checkWalletStatus(wallet, false) != nil {
+			return nil
+		}
+
+		// The wallet lock serializes payers of the same wallet, so the sum cannot be paid twice.
+		from, to := month, month.AddDate(0, 1, 0)
+		var micros int64
+		err = tx.QueryRowContext(ctx, `
+    SELECT COALESCE(SUM(accrued_micros), 0) FROM interest_accruals
+    WHERE wallet_id = $1 AND day >= $2 AND day < $3 AND paid_at IS NULL
+    `, walletID, from, to).Scan(&micros)
+		if err != nil {
+			return fmt.Errorf("%s: sum accruals error: %w", op, err)
+		}
+
+		var operationID *string
+		if amount := models.MicrosToAmount(micros); amount > 0 {
+			description := "Interest for " + month.Format("January 2006")
+			operation := &models.Operation{Type: "INTEREST", Amount: amount, Description: &description}
+			if err = applyOperation(ctx, tx, wallet, operation, interestExpenseAccount); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			operationID = &operation.ID
+			posted = true
+		}
+		_, err = tx.ExecContext(ctx, `
+    UPDATE interest_accruals SET paid_at = $4, operation_id = $5
+    WHERE wallet_id = $1 AND day >= $2 AND day < $3 AND paid_at IS NULL
+    `, walletID, from, to, time.Now().UTC(), operationID)
+		if err != nil {
+			return fmt.Errorf("%s: mark accruals paid error: %w", op, err)
+		}
+		return nil
+	})
+	return posted, err
+}
+
+// ListInterestAccruals returns a wallet's accruals for the days in [from, to), oldest first.
+func (s *Storage) ListInterestAccruals(ctx context.Context, walletID string, from, to time.Time) ([]models.InterestAccrual, error) {
+	op := "database.ListInterestAccruals"
+
+	if _, err := s.GetWallet(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows, err := s.db.QueryContext(ctx, `
+    SELECT `+interestAccrualColumns+` FROM interest_accruals
+    WHERE wallet_id = $1 AND day >= $2 AND day < $3
+    ORDER BY day
+    `, walletID, startOfDay(from), startOfDay(to))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	accruals := make([]models.InterestAccrual, 0)
+	for rows.Next() {
+		var accrual models.InterestAccrual
+		err = rows.Scan(&accrual.WalletID, &accrual.Day, &accrual.Balance, &accrual.RateBps, &accrual.AccruedMicros,
+			&accrual.PaidAt, &accrual.OperationID, &accrual.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		accruals = append(accruals, accrual)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return accruals, nil
+}
+
+// startOfDay truncates t to midnight UTC. Interest days are UTC days.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyInterest(t *testing.T) {
+	tests := []struct {
+		name     string
+		balance  models.Amount
+		rateBps  int64
+		expected int64
+	}{
+		{"whole micros", 365000, 1000, 100000000},
+		{"fraction above half rounds up", 1, 10, 3},
+		{"fraction below half rounds down", 1, 1, 0},
+		{"negative balance earns nothing", -1000, 500, 0},
+		{"zero rate earns nothing", 1000, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			micros, err := models.DailyInterest(tt.balance, tt.rateBps)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, micros)
+		})
+	}
+
+	_, err := models.DailyInterest(1<<63-1, models.MaxRateBps)
+	assert.ErrorIs(t, err, models.ErrAmountOverflow)
+}
+
+func TestMicrosToAmount(t *testing.T) {
+	assert.Equal(t, models.Amount(0), models.MicrosToAmount(499999))
+	assert.Equal(t, models.Amount(0), models.MicrosToAmount(500000))
+	assert.Equal(t, models.Amount(2), models.MicrosToAmount(1500000))
+	assert.Equal(t, models.Amount(2), models.MicrosToAmount(2500000))
+	assert.Equal(t, models.Amount(3), models.MicrosToAmount(2500001))
+}
+
+func TestRunInterestIsIdempotent(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	wallet, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: wallet.WalletID, OperationType: "DEPOSIT", Amount: 365000})))
+	updated, err := storage.SetInterestRate(ctx, wallet.WalletID, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), updated.InterestRate)
+
+	// Accrue today, which already holds the deposit, and pay it out as if the month were over.
+	day := startOfDay(time.Now())
+	_, err = storage.AccrueInterest(ctx, day)
+	require.NoError(t, err)
+	accrued, err := storage.AccrueInterest(ctx, day)
+	require.NoError(t, err)
+	assert.Equal(t, 0, accrued)
+
+	nextMonth := time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = storage.PayInterest(ctx, nextMonth)
+	require.NoError(t, err)
+	paid, err := storage.PayInterest(ctx, nextMonth)
+	require.NoError(t, err)
+	assert.Equal(t, 0, paid)
+
+	accruals, err := storage.ListInterestAccruals(ctx, wallet.WalletID, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+	assert.Equal(t, models.Amount(365000), accruals[0].Balance)
+	assert.Equal(t, int64(100000000), accruals[0].AccruedMicros)
+	require.NotNil(t, accruals[0].OperationID)
+
+	page, err := storage.ListOperations(ctx, requests.OperationsFilter{WalletID: wallet.WalletID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Operations, 2)
+	assert.Equal(t, "INTEREST", page.Operations[0].OperationType)
+	assert.Equal(t, models.Amount(100), page.Operations[0].Amount)
+	assert.Equal(t, *accruals[0].OperationID, page.Operations[0].ID)
+
+	balance, operationsSum, _ := walletState(t, storage, wallet.WalletID)
+	assert.Equal(t, 365100, balance)
+	assert.Equal(t, operationsSum, balance)
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// System ledger accounts that wallet accounts are posted against.
+const (
+	externalCashAccount = "external_cash" // Money entering or leaving the system through deposits and withdrawals
+)
+
+const maxLedgerMismatches = 100
+
+// posting is one leg of a journal entry. A positive amount increases the account balance.
+type posting struct {
+	account     string
+	walletID    string // Set for wallet accounts
+	amount      models.Amount
+	currency    string
+	operationID string
+}
+
+func walletAccount(walletID string) string {
+	return "wallet:" + canonicalID(walletID)
+}
+
+func systemAccount(name, currency string) string {
+	return "system:" + name + ":" + currency
+}
+
+// walletPosting moves amount into (or, if negative, out of) a wallet account.
+func walletPosting(walletID string, amount models.Amount, currency, operationID string) posting {
+	return posting{
+		account:     walletAccount(walletID),
+		walletID:    walletID,
+		amount:      amount,
+		currency:    currency,
+		operationID: operationID,
+	}
+}
+
+func systemPosting(name string, amount models.Amount, currency, operationID string) posting {
+	return posting{
+		account:     systemAccount(name, currency),
+		amount:      amount,
+		currency:    currency,
+		operationID: operationID,
+	}
+}
+
+// postJournalEntry records postings as a single journal entry of the given kind.
+// Postgres rejects the transaction at commit unless the postings sum to zero per currency.
+func postJournalEntry(ctx context.Context, tx *sql.Tx, kind string, postings ...posting) error {
+	accountValues := make([]string, 0, len(postings))
+	accountArgs := make([]interface{}, 0, 3*len(postings))
+	for _, p := range postings {
+		var walletID interface{}
+		if p.walletID != "" {
+			walletID = p.walletID
+		}
+		n := len(accountArgs)
+		accountValues = append(accountValues, fmt.Sprintf("($%d, $%d::uuid, $%d)", n+1, n+2, n+3))
+		accountArgs = append(accountArgs, p.account, walletID, p.currency)
+	}
+	_, err := tx.ExecContext(ctx, `
+    INSERT INTO ledger_accounts (account_id, wallet_id, currency)
+    VALUES `+strings.Join(accountValues, ", ")+`
+    ON CONFLICT (account_id) DO NOTHING
+    `, accountArgs...)
+	if err != nil {
+		return fmt.Errorf("open ledger accounts: %w", err)
+	}
+
+	entryID := genUUID()
+	_, err = tx.ExecContext(ctx, "INSERT INTO journal_entries (id, kind, created_at) VALUES ($1, $2, $3)", entryID, kind, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("insert journal entry: %w", err)
+	}
+
+	postingValues := make([]string, 0, len(postings))
+	postingArgs := make([]interface{}, 0, 5*len(postings))
+	for _, p := range postings {
+		var operationID interface{}
+		if p.operationID != "" {
+			operationID = p.operationID
+		}
+		n := len(postingArgs)
+		postingValues = append(postingValues, fmt.Sprintf("($%d, $%d, $%d::uuid, $%d, $%d)", n+1, n+2, n+3, n+4, n+5))
+		postingArgs = append(postingArgs, entryID, p.account, operationID, p.amount, p.currency)
+	}
+	_, err = tx.ExecContext(ctx, `
+    INSERT INTO postings (entry_id, account_id, operation_id, amount, currency)
+    VALUES `+strings.Join(postingValues, ", "), postingArgs...)
+	if err != nil {
+		return fmt.Errorf("insert postings: %w", err)
+	}
+	return nil
+}
+
+// VerifyLedger checks that all postings sum to zero per currency
+// and that every wallet balance equals the sum of its wallet account postings.
+func (s *Storage) VerifyLedger(ctx context.Context) (*requests.LedgerReport, error) {
+	op := "database.VerifyLedger"
+
+	report := &requests.LedgerReport{
+		Balanced:   true,
+		Totals:     map[string]models.Amount{},
+		Mismatches: []requests.LedgerMismatch{},
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT currency, SUM(amount) FROM postings GROUP BY currency")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var currency string
+		var total models.Amount
+		if err = rows.Scan(&currency, &total); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		report.Totals[currency] = total
+		if total != 0 {
+			report.Balanced = false
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mismatches, err := s.db.QueryContext(ctx, `
+    SELECT wallets.wallet_id, wallets.balance, COALESCE(SUM(postings.amount), 0)
+    FROM wallets
+    LEFT JOIN postings ON postings.account_id = 'wallet:' || wallets.wallet_id
+    GROUP BY wallets.wallet_id, wallets.balance
+    HAVING wallets.balance <> COALESCE(SUM(postings.amount), 0)
+    ORDER BY wallets.wallet_id
+    LIMIT $1
+    `, maxLedgerMismatches)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer mismatches.Close()
+	for mismatches.Next() {
+		var mismatch requests.LedgerMismatch
+		if err = mismatches.Scan(&mismatch.WalletID, &mismatch.Balance, &mismatch.LedgerBalance); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		report.Mismatches = append(report.Mismatches, mismatch)
+		report.Balanced = false
+	}
+	if err = mismatches.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerStaysBalanced(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	first, second := uuid.New().String(), uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: first, OperationType: "DEPOSIT", Amount: 300})))
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: second, OperationType: "DEPOSIT", Amount: 50})))
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: first, OperationType: "WITHDRAW", Amount: 100})))
+	_, err := storage.Transfer(ctx, requests.TransferRequest{FromWalletID: first, ToWalletID: second, Amount: 75})
+	require.NoError(t, err)
+
+	report, err := storage.VerifyLedger(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, report.Totals["USD"])
+
+	balance, _, _ := walletState(t, storage, first)
+	assert.Equal(t, 125, balance)
+	balance, _, _ = walletState(t, storage, second)
+	assert.Equal(t, 125, balance)
+}
+
+func TestUnbalancedJournalEntryIsRejected(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	tx, err := storage.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	err = postJournalEntry(ctx, tx, "TEST", systemPosting(externalCashAccount, 1, "USD", ""))
+	require.NoError(t, err)
+	assert.Error(t, tx.Commit())
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// walletTransitions lists the statuses each status may move to. CLOSED is final.
+var walletTransitions = map[string][]string{
+	models.WalletActive: {models.WalletFrozen, models.WalletClosed},
+	models.WalletFrozen: {models.WalletActive, models.WalletClosed},
+}
+
+// checkWalletStatus returns WalletNotActiveError if the wallet cannot take part in an operation:
+// frozen wallets only accept credits and closed wallets accept nothing.
+func checkWalletStatus(wallet *models.Wallets, debit bool) error {
+	switch wallet.Status {
+	case models.WalletClosed:
+		return requests.WalletNotActiveError{Status: wallet.Status}
+	case models.WalletFrozen:
+		if debit {
+			return requests.WalletNotActiveError{Status: wallet.Status}
+		}
+	}
+	return nil
+}
+
+// CreateWallet opens an empty active wallet, generating its id unless req carries one.
+// A sub-wallet takes the currency of its parent, which must not be closed, and its owner unless req names one.
+func (s *Storage) CreateWallet(ctx context.Context, req requests.CreateWalletRequest) (*requests.WalletBalanceResponse, error) {
+	op := "database.CreateWallet"
+
+	walletID := req.WalletID
+	if walletID == "" {
+		walletID = genUUID()
+	}
+	currency := req.Currency
+	var parentID, ownerID *string
+	if req.OwnerID != "" {
+		ownerID = &req.OwnerID
+	}
+	var createdAt time.Time
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		if req.ParentID != "" {
+			// The parent stays locked until the child is in, so it cannot be closed in between.
+			wallets, err := lockWallets(ctx, tx, req.ParentID)
+			if err != nil {
+				return fmt.Errorf("%s: lock wallet error: %w", op, err)
+			}
+			parent, ok := wallets[req.ParentID]
+			if !ok {
+				return fmt.Errorf("%s: parent wallet with id %s not found: %w", op, req.ParentID, sql.ErrNoRows)
+			}
+			if err = checkWalletStatus(parent, false); err != nil {
+				return err
+			}
+			if currency == "" {
+				currency = parent.Currency
+			}
+			if currency != parent.Currency {
+				return requests.CurrencyMismatchError{WalletCurrency: parent.Currency, Currency: currency}
+			}
+			parentID = &parent.WalletID
+			if ownerID == nil {
+				ownerID = parent.OwnerID
+			}
+		}
+		if req.OwnerID != "" {
+			if err := checkOwnerExists(ctx, tx, req.OwnerID); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		if currency == "" {
+			currency = s.defaultCurrency
+		}
+		err := tx.QueryRowContext(ctx, `
+    INSERT INTO wallets (wallet_id, currency, status, parent_id, owner_id) VALUES ($1, $2, $3, $4, $5)
+    RETURNING created_at
+    `, walletID, currency, models.WalletActive, parentID, ownerID).Scan(&createdAt)
+		if err != nil {
+			if isUniqueViolation(err, "wallets_pkey") {
+				return requests.WalletExistsError{}
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		event := requests.WalletCreatedV1{WalletID: walletID, Currency: currency, ParentID: parentID, OwnerID: ownerID}
+		if err = enqueueEvent(ctx, tx, walletID, event); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &requests.WalletBalanceResponse{
+		WalletID:  walletID,
+		Currency:  currency,
+		Status:    models.WalletActive,
+		Tier:      models.DefaultTier,
+		ParentID:  parentID,
+		OwnerID:   ownerID,
+		CreatedAt: &createdAt,
+	}, nil
+}
+
+// SetWalletStatus moves a wallet through its lifecycle. A wallet can only be closed once its balance
+// is zero and nothing is held on it or on any of its sub-wallets.
+func (s *Storage) SetWalletStatus(ctx context.Context, walletID, status string) (*requests.WalletBalanceResponse, error) {
+	op := "database.SetWalletStatus"
+
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		// Closing looks at every sub-wallet, so the whole subtree is locked up front.
+		if status == models.WalletClosed {
+			if err := lockSubtree(ctx, tx, walletID); err != nil {
+				return fmt.Errorf("%s: lock sub-wallets error: %w", op, err)
+			}
+		}
+		wallets, err := lockWallets(ctx, tx, walletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallet error: %w", op, err)
+		}
+		wallet, ok := wallets[walletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, sql.ErrNoRows)
+		}
+		if wallet.Status == status {
+			return nil
+		}
+		allowed := false
+		for _, next := range walletTransitions[wallet.Status] {
+			allowed = allowed || next == status
+		}
+		if !allowed {
+			return requests.WalletStatusTransitionError{From: wallet.Status, To: status}
+		}
+		if status == models.WalletClosed {
+			held, err := heldAmount(ctx, tx, walletID)
+			if err != nil {
+				return fmt.Errorf("%s: sum holds error: %w", op, err)
+			}
+			if wallet.Balance != 0 || held != 0 {
+				return requests.WalletNotEmptyError{Balance: wallet.Balance, Held: held}
+			}
+			nonEmpty, err := nonEmptySubWallets(ctx, tx, walletID)
+			if err != nil {
+				return fmt.Errorf("%s: check sub-wallets error: %w", op, err)
+			}
+			if nonEmpty > 0 {
+				return requests.SubWalletsNotEmptyError{Count: nonEmpty}
+			}
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE wallets SET status = $1 WHERE wallet_id = $2", status, walletID)
+		if err != nil {
+			return fmt.Errorf("%s: update wallet error: %w", op, err)
+		}
+		event := requests.WalletStatusChangedV1{WalletID: wallet.WalletID, From: wallet.Status, To: status}
+		if err = enqueueEvent(ctx, tx, walletID, event); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetWalletBalance(ctx, walletID)
+}
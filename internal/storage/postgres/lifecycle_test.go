@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletLifecycle(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	wallet, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{Currency: "EUR"})
+	require.NoError(t, err)
+	assert.Equal(t, models.WalletActive, wallet.Status)
+	walletID := wallet.WalletID
+
+	_, err = storage.CreateWallet(ctx, requests.CreateWalletRequest{WalletID: walletID})
+	assert.ErrorAs(t, err, &requests.WalletExistsError{})
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100})))
+
+	_, err = storage.SetWalletStatus(ctx, walletID, models.WalletFrozen)
+	require.NoError(t, err)
+	_, err = storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 10})
+	assert.ErrorAs(t, err, &requests.WalletNotActiveError{})
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 10})))
+
+	_, err = storage.SetWalletStatus(ctx, walletID, models.WalletClosed)
+	assert.ErrorAs(t, err, &requests.WalletNotEmptyError{})
+
+	_, err = storage.SetWalletStatus(ctx, walletID, models.WalletActive)
+	require.NoError(t, err)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 110})))
+
+	closed, err := storage.SetWalletStatus(ctx, walletID, models.WalletClosed)
+	require.NoError(t, err)
+	assert.Equal(t, models.WalletClosed, closed.Status)
+	_, err = storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 10})
+	assert.ErrorAs(t, err, &requests.WalletNotActiveError{})
+	_, err = storage.SetWalletStatus(ctx, walletID, models.WalletActive)
+	assert.ErrorAs(t, err, &requests.WalletStatusTransitionError{})
+}
+
+func TestProcessOperationWithoutAutoCreate(t *testing.T) {
+	storage := newTestStorage(t)
+	storage.autoCreate = false
+	ctx := context.Background()
+
+	_, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: uuid.New().String(), OperationType: "DEPOSIT", Amount: 100})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/config"
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// Names of the withdrawal limits, as reported in WithdrawalLimitError.
+const (
+	limitPerOperation = "per-operation"
+	limitDaily        = "daily"
+	limitMonthly      = "monthly"
+	limitHourlyCount  = "hourly count"
+)
+
+// globalWithdrawalLimits converts the configured limits, where 0 means no limit.
+func globalWithdrawalLimits(cfg config.WithdrawalLimitsConfig) requests.WithdrawalLimits {
+	return requests.WithdrawalLimits{
+		PerOperation: positive(models.Amount(cfg.PerOperation)),
+		Daily:        positive(models.Amount(cfg.Daily)),
+		Monthly:      positive(models.Amount(cfg.Monthly)),
+		HourlyCount:  positive(cfg.HourlyCount),
+	}
+}
+
+func positive[T ~int | ~int64](v T) *T {
+	if v <= 0 {
+		return nil
+	}
+	return &v
+}
+
+// walletWithdrawalLimits returns the limits set on the wallet itself; unset ones are nil.
+func walletWithdrawalLimits(ctx context.Context, q queryer, walletID string) (requests.WithdrawalLimits, error) {
+	var limits requests.WithdrawalLimits
+	var perOperation, daily, monthly, hourlyCount sql.NullInt64
+	err := q.QueryRowContext(ctx, `
+    SELECT per_operation, daily, monthly, hourly_count FROM withdrawal_limits WHERE wallet_id = $1
+    `, walletID).Scan(&perOperation, &daily, &monthly, &hourlyCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return limits, nil
+		}
+		return limits, err
+	}
+	limits.PerOperation = nullable[models.Amount](perOperation)
+	limits.Daily = nullable[models.Amount](daily)
+	limits.Monthly = nullable[models.Amount](monthly)
+	limits.HourlyCount = nullable[int](hourlyCount)
+	return limits, nil
+}
+
+func nullable[T ~int | ~int64](v sql.NullInt64) *T {
+	if !v.Valid {
+		return nil
+	}
+	i := T(v.Int64)
+	return &i
+}
+
+// effectiveLimits fills the limits a wallet does not set with the global ones.
+func (s *Storage) effectiveLimits(own requests.WithdrawalLimits) requests.WithdrawalLimits {
+	return requests.WithdrawalLimits{
+		PerOperation: pick(own.PerOperation, s.limits.PerOperation),
+		Daily:        pick(own.Daily, s.limits.Daily),
+		Monthly:      pick(own.Monthly, s.limits.Monthly),
+		HourlyCount:  pick(own.HourlyCount, s.limits.HourlyCount),
+	}
+}
+
+func pick[T any](own, global *T) *T {
+	if own != nil {
+		return own
+	}
+	return global
+}
+
+// checkWithdrawalLimits returns WithdrawalLimitError if withdrawing amount from the wallet would breach one of
+// its limits, counting every WITHDRAW already recorded in operations except fees. The caller must hold the wallet row lock,
+// so concurrent withdrawals cannot both slip under a limit.
+func (s *Storage) checkWithdrawalLimits(ctx context.Context, tx *sql.Tx, walletID string, amount models.Amount) error {
+	own, err := walletWithdrawalLimits(ctx, tx, walletID)
+	if err != nil {
+		return fmt.Errorf("get withdrawal limits error: %w", err)
+	}
+	limits := s.effectiveLimits(own)
+	if limits.PerOperation != nil && amount > *limits.PerOperation {
+		return requests.WithdrawalLimitError{Limit: limitPerOperation, Max: int64(*limits.PerOperation)}
+	}
+	if limits.Daily == nil && limits.Monthly == nil && limits.HourlyCount == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	var daily, monthly models.Amount
+	var hourlyCount int
+	err = tx.QueryRowContext(ctx, `
+    SELECT
+        COALESCE(SUM(amount) FILTER (WHERE timestamp > $3), 0),
+        COALESCE(SUM(amount), 0),
+        COUNT(*) FILTER (WHERE timestamp > $4)
+    FROM operations
+    WHERE wallet_id = $1 AND type = 'WITHDRAW' AND fee_for IS NULL AND timestamp > $2
+    `, walletID, now.Add(-30*24*time.Hour), now.Add(-24*time.Hour), now.Add(-time.Hour)).Scan(&daily, &monthly, &hourlyCount)
+	if err != nil {
+		return fmt.Errorf("sum withdrawals error: %w", err)
+	}
+	if limits.Daily != nil && daily+amount > *limits.Daily {
+		return requests.WithdrawalLimitError{Limit: limitDaily, Max: int64(*limits.Daily)}
+	}
+	if limits.Monthly != nil && monthly+amount > *limits.Monthly {
+		return requests.WithdrawalLimitError{Limit: limitMonthly, Max: int64(*limits.Monthly)}
+	}
+	if limits.HourlyCount != nil && hourlyCount+1 > *limits.HourlyCount {
+		return requests.WithdrawalLimitError{Limit: limitHourlyCount, Max: int64(*limits.HourlyCount)}
+	}
+	return nil
+}
+
+func (s *Storage) GetWithdrawalLimits(ctx context.Context, walletID string) (*requests.WithdrawalLimitsResponse, error) {
+	op := "database.GetWithdrawalLimits"
+
+	if _, err := s.GetWallet(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	own, err := walletWithdrawalLimits(ctx, s.db, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &requests.WithdrawalLimitsResponse{
+		WalletID:  walletID,
+		Wallet:    own,
+		Effective: s.effectiveLimits(own),
+	}, nil
+}
+
+// SetWithdrawalLimits replaces the wallet's own limits; nil fields fall back to the global ones.
+func (s *Storage) SetWithdrawalLimits(ctx context.Context, walletID string, limits requests.WithdrawalLimits) (*requests.WithdrawalLimitsResponse, error) {
+	op := "database.SetWithdrawalLimits"
+
+	if _, err := s.GetWallet(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	_, err := s.db.ExecContext(ctx, `
+    INSERT INTO withdrawal_limits (wallet_id, per_operation, daily, monthly, hourly_count)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (wallet_id) DO UPDATE SET
+        per_operation = EXCLUDED.per_operation,
+        daily = EXCLUDED.daily,
+        monthly = EXCLUDED.monthly,
+        hourly_count = EXCLUDED.hourly_count
+    `, walletID, limits.PerOperation, limits.Daily, limits.Monthly, limits.HourlyCount)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &requests.WithdrawalLimitsResponse{
+		WalletID:  walletID,
+		Wallet:    limits,
+		Effective: s.effectiveLimits(limits),
+	}, nil
+}
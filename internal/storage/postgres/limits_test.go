@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawalLimits(t *testing.T) {
+	storage := newTestStorage(t)
+	perOperation := models.Amount(300)
+	storage.limits = requests.WithdrawalLimits{PerOperation: &perOperation}
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 1000})))
+
+	var limitErr requests.WithdrawalLimitError
+	_, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 301})
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, limitPerOperation, limitErr.Limit)
+
+	daily, hourlyCount := models.Amount(500), 3
+	limits, err := storage.SetWithdrawalLimits(ctx, walletID, requests.WithdrawalLimits{Daily: &daily, HourlyCount: &hourlyCount})
+	require.NoError(t, err)
+	assert.Equal(t, perOperation, *limits.Effective.PerOperation)
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 300})))
+	_, err = storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 201})
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, limitDaily, limitErr.Limit)
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 200})))
+	// Lifting the daily limit leaves the hourly count as the one that bites.
+	_, err = storage.SetWithdrawalLimits(ctx, walletID, requests.WithdrawalLimits{HourlyCount: &hourlyCount})
+	require.NoError(t, err)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 100})))
+	_, err = storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 1})
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, limitHourlyCount, limitErr.Limit)
+
+	balance, operationsSum, _ := walletState(t, storage, walletID)
+	assert.Equal(t, 400, balance)
+	assert.Equal(t, 400, operationsSum)
+}
+
+func TestWithdrawalLimitsOnCaptureAndReversal(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 1000})))
+	page, err := storage.ListOperations(ctx, requests.OperationsFilter{WalletID: walletID, Limit: 1})
+	require.NoError(t, err)
+	deposit := page.Operations[0]
+	hold, err := storage.CreateHold(ctx, requests.CreateHoldRequest{WalletID: walletID, Amount: 400})
+	require.NoError(t, err)
+
+	daily := models.Amount(300)
+	_, err = storage.SetWithdrawalLimits(ctx, walletID, requests.WithdrawalLimits{Daily: &daily})
+	require.NoError(t, err)
+
+	var limitErr requests.WithdrawalLimitError
+	_, err = storage.CaptureHold(ctx, hold.ID, 0)
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, limitDaily, limitErr.Limit)
+	_, err = storage.ReverseOperation(ctx, deposit.ID, 301)
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, limitDaily, limitErr.Limit)
+
+	// A capture within the limit uses it up for the reversal.
+	_, err = storage.CaptureHold(ctx, hold.ID, 200)
+	require.NoError(t, err)
+	_, err = storage.ReverseOperation(ctx, deposit.ID, 101)
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, limitDaily, limitErr.Limit)
+
+	balance, operationsSum, _ := walletState(t, storage, walletID)
+	assert.Equal(t, 800, balance)
+	assert.Equal(t, 800, operationsSum)
+}
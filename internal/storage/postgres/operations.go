@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+)
+
+const operationColumns = `id, wallet_id, type, amount, currency, timestamp, transfer_id, reversal_of,
+    description, external_reference, metadata, fee, fee_for, exchange_rate, spread_bps`
+
+// ListOperations returns one page of a wallet's operation history, newest first.
+// The page is read one row past filter.Limit to know whether a next page exists.
+func (s *Storage) ListOperations(ctx context.Context, filter requests.OperationsFilter) (*requests.OperationsPage, error) {
+	op := "database.ListOperations"
+
+	if _, err := s.GetWallet(ctx, filter.WalletID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conditions := []string{"wallet_id = $1"}
+	args := []interface{}{filter.WalletID}
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+	if filter.OperationType != "" {
+		addCondition("type = $%d", filter.OperationType)
+	}
+	if filter.MinAmount != nil {
+		addCondition("amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		addCondition("amount <= $%d", *filter.MaxAmount)
+	}
+	if filter.From != nil {
+		addCondition("timestamp >= $%d", filter.From.UTC())
+	}
+	if filter.To != nil {
+		addCondition("timestamp < $%d", filter.To.UTC())
+	}
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.Timestamp.UTC(), filter.Cursor.ID)
+		conditions = append(conditions, fmt.Sprintf("(timestamp, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, filter.Limit+1)
+
+	query := fmt.Sprintf(`
+    SELECT %s
+    FROM operations
+    WHERE %s
+    ORDER BY timestamp DESC, id DESC
+    LIMIT $%d
+    `, operationColumns, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	page := &requests.OperationsPage{
+		Operations: make([]requests.OperationResponse, 0, filter.Limit),
+	}
+	for rows.Next() {
+		operation, err := scanOperation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		page.Operations = append(page.Operations, *operation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(page.Operations) > filter.Limit {
+		page.Operations = page.Operations[:filter.Limit]
+		last := page.Operations[len(page.Operations)-1]
+		page.NextCursor = requests.OperationsCursor{Timestamp: last.Timestamp, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+// GetOperationByReference returns the operation a wallet recorded under the caller's external reference.
+func (s *Storage) GetOperationByReference(ctx context.Context, walletID, externalReference string) (*requests.OperationResponse, error) {
+	op := "database.GetOperationByReference"
+
+	row := s.db.QueryRowContext(ctx, `SELECT `+operationColumns+` FROM operations WHERE wallet_id = $1 AND external_reference = $2`,
+		walletID, externalReference)
+	operation, err := scanOperation(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: operation with external reference %s not found: %w", op, externalReference, err)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return operation, nil
+}
+
+func scanOperation(row scanner) (*requests.OperationResponse, error) {
+	var (
+		operation requests.OperationResponse
+		metadata  []byte
+	)
+	err := row.Scan(&operation.ID, &operation.WalletID, &operation.OperationType, &operation.Amount, &operation.Currency,
+		&operation.Timestamp, &operation.TransferID, &operation.ReversalOf,
+		&operation.Description, &operation.ExternalReference, &metadata, &operation.Fee, &operation.FeeFor,
+		&operation.ExchangeRate, &operation.SpreadBps)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata) > 0 {
+		operation.Metadata = metadata
+	}
+	return &operation, nil
+}
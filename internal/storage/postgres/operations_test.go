@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOperationsPagination(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	for amount := models.Amount(1); amount <= 5; amount++ {
+		require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{
+			WalletID:      walletID,
+			OperationType: "DEPOSIT",
+			Amount:        amount,
+		})))
+	}
+
+	var amounts []models.Amount
+	filter := requests.OperationsFilter{WalletID: walletID, Limit: 2}
+	for {
+		page, err := storage.ListOperations(ctx, filter)
+		require.NoError(t, err)
+		for _, operation := range page.Operations {
+			amounts = append(amounts, operation.Amount)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor, err = requests.DecodeOperationsCursor(page.NextCursor)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []models.Amount{5, 4, 3, 2, 1}, amounts)
+
+	minAmount, maxAmount := models.Amount(2), models.Amount(3)
+	page, err := storage.ListOperations(ctx, requests.OperationsFilter{
+		WalletID:  walletID,
+		MinAmount: &minAmount,
+		MaxAmount: &maxAmount,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Operations, 2)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestProcessOperationCurrency(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{
+		WalletID:      walletID,
+		OperationType: "DEPOSIT",
+		Amount:        100,
+		Currency:      "EUR",
+	})))
+
+	_, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{
+		WalletID:      walletID,
+		OperationType: "DEPOSIT",
+		Amount:        100,
+		Currency:      "USD",
+	})
+	var currencyMismatchErr requests.CurrencyMismatchError
+	require.ErrorAs(t, err, &currencyMismatchErr)
+	assert.Equal(t, "EUR", currencyMismatchErr.WalletCurrency)
+
+	balance, err := storage.GetWalletBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(100), balance.Balance)
+	assert.Equal(t, "EUR", balance.Currency)
+}
+
+func TestOperationMetadata(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID, otherWalletID := uuid.New().String(), uuid.New().String()
+
+	req := requests.WalletOperationRequest{
+		WalletID:          walletID,
+		OperationType:     "DEPOSIT",
+		Amount:            100,
+		Description:       "May rent",
+		ExternalReference: "invoice-42",
+		Metadata:          []byte(`{"orderId": 7, "tags": ["rent"]}`),
+	}
+	require.NoError(t, processed(storage.ProcessOperation(ctx, req)))
+
+	operation, err := storage.GetOperationByReference(ctx, walletID, "invoice-42")
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(100), operation.Amount)
+	require.NotNil(t, operation.Description)
+	assert.Equal(t, "May rent", *operation.Description)
+	assert.JSONEq(t, `{"orderId": 7, "tags": ["rent"]}`, string(operation.Metadata))
+
+	page, err := storage.ListOperations(ctx, requests.OperationsFilter{WalletID: walletID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Operations, 1)
+	assert.Equal(t, "invoice-42", *page.Operations[0].ExternalReference)
+
+	_, err = storage.ProcessOperation(ctx, req)
+	assert.ErrorAs(t, err, &requests.ExternalReferenceConflictError{})
+	_, _, count := walletState(t, storage, walletID)
+	assert.Equal(t, 1, count)
+
+	// References are only unique per wallet.
+	req.WalletID = otherWalletID
+	require.NoError(t, processed(storage.ProcessOperation(ctx, req)))
+
+	_, err = storage.GetOperationByReference(ctx, walletID, "invoice-43")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// A null metadata is stored as none at all.
+	req.ExternalReference, req.Metadata = "invoice-43", []byte(`null`)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, req)))
+	operation, err = storage.GetOperationByReference(ctx, otherWalletID, "invoice-43")
+	require.NoError(t, err)
+	assert.Nil(t, operation.Metadata)
+}
+
+func TestProcessOperationOverflow(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{
+		WalletID:      walletID,
+		OperationType: "DEPOSIT",
+		Amount:        math.MaxInt64,
+	})))
+	_, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{
+		WalletID:      walletID,
+		OperationType: "DEPOSIT",
+		Amount:        1,
+	})
+	assert.ErrorIs(t, err, models.ErrAmountOverflow)
+
+	balance, err := storage.GetWalletBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(math.MaxInt64), balance.Balance)
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/events"
+)
+
+// maxErrorLength bounds the error kept on an event that failed to publish or a webhook delivery that failed.
+const maxErrorLength = 1000
+
+// enqueueEvent writes an event about a wallet to the outbox inside tx, so it is published if and only if
+// the change it describes commits.
+func enqueueEvent(ctx context.Context, tx *sql.Tx, walletID string, payload requests.EventPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode event error: %w", err)
+	}
+	now := time.Now().UTC()
+	_, err = tx.ExecContext(ctx, `
+    INSERT INTO outbox_events (id, type, version, wallet_id, payload, created_at, next_attempt_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $6)
+    `, genUUID(), payload.EventType(), payload.EventVersion(), walletID, data, now)
+	if err != nil {
+		return fmt.Errorf("enqueue event error: %w", err)
+	}
+	return nil
+}
+
+// DispatchEvents claims up to a batch of pending outbox events that are due, hands them to publisher in the
+// order they were written and marks the published ones delivered. A failed event is retried later with
+// exponential backoff, while the rest of the batch goes on. Rows are claimed with FOR UPDATE SKIP LOCKED,
+// so any number of dispatchers can run side by side without publishing the same event at the same time.
+// It returns the number of events delivered and whether the batch was full.
+func (s *Storage) DispatchEvents(ctx context.Context, publisher events.Publisher) (int, bool, error) {
+	op := "database.DispatchEvents"
+
+	var delivered, claimed int
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		rows, err := tx.QueryContext(ctx, `
+    SELECT id, sequence, type, version, wallet_id, payload, created_at, attempts
+    FROM outbox_events
+    WHERE delivered_at IS NULL AND next_attempt_at <= $1
+    ORDER BY sequence
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+    `, now, s.outbox.BatchSize)
+		if err != nil {
+			return fmt.Errorf("%s: claim events error: %w", op, err)
+		}
+		var (
+			batch    []requests.EventEnvelope
+			attempts []int
+		)
+		for rows.Next() {
+			var (
+				event   requests.EventEnvelope
+				data    []byte
+				attempt int
+			)
+			err = rows.Scan(&event.ID, &event.Sequence, &event.Type, &event.Version, &event.WalletID, &data,
+				&event.OccurredAt, &attempt)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			event.Data = data
+			batch = append(batch, event)
+			attempts = append(attempts, attempt+1)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		claimed = len(batch)
+
+		for i, event := range batch {
+			if err := publisher.Publish(ctx, event); err != nil {
+				message := truncateError(err.Error())
+				_, err = tx.ExecContext(ctx, `
+    UPDATE outbox_events SET attempts = $1, next_attempt_at = $2, last_error = $3 WHERE id = $4
+    `, attempts[i], now.Add(s.eventRetryDelay(attempts[i])), message, event.ID)
+				if err != nil {
+					return fmt.Errorf("%s: record failure error: %w", op, err)
+				}
+				continue
+			}
+			_, err = tx.ExecContext(ctx, `
+    UPDATE outbox_events SET attempts = $1, delivered_at = $2, last_error = NULL WHERE id = $3
+    `, attempts[i], time.Now().UTC(), event.ID)
+			if err != nil {
+				return fmt.Errorf("%s: mark delivered error: %w", op, err)
+			}
+			delivered++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return delivered, claimed > 0 && claimed == s.outbox.BatchSize, nil
+}
+
+// eventRetryDelay is how long an event waits after its attempt-th failed publication.
+func (s *Storage) eventRetryDelay(attempt int) time.Duration {
+	return backoff(s.outbox.RetryDelay, s.outbox.MaxRetryDelay, attempt)
+}
+
+// backoff is the delay after the attempt-th failure: delay doubled after every failure but the first,
+// up to maxDelay.
+func backoff(delay, maxDelay time.Duration, attempt int) time.Duration {
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// truncateError cuts an error message down to what is kept in the database.
+func truncateError(message string) string {
+	if len(message) > maxErrorLength {
+		message = strings.ToValidUTF8(message[:maxErrorLength], "")
+	}
+	return message
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/config"
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/events"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventRetryDelay(t *testing.T) {
+	s := &Storage{outbox: config.OutboxConfig{RetryDelay: time.Second, MaxRetryDelay: 10 * time.Second}}
+
+	assert.Equal(t, time.Second, s.eventRetryDelay(1))
+	assert.Equal(t, 2*time.Second, s.eventRetryDelay(2))
+	assert.Equal(t, 8*time.Second, s.eventRetryDelay(4))
+	assert.Equal(t, 10*time.Second, s.eventRetryDelay(5))
+	assert.Equal(t, 10*time.Second, s.eventRetryDelay(100))
+}
+
+func TestDispatchEvents(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	from, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	to, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: from.WalletID, OperationType: "DEPOSIT", Amount: 500})))
+	_, err = storage.Transfer(ctx, requests.TransferRequest{FromWalletID: from.WalletID, ToWalletID: to.WalletID, Amount: 200})
+	require.NoError(t, err)
+	// Rolled back changes leave no events behind.
+	require.Error(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: to.WalletID, OperationType: "WITHDRAW", Amount: 1000})))
+	_, err = storage.SetWalletStatus(ctx, to.WalletID, models.WalletFrozen)
+	require.NoError(t, err)
+
+	// The first publication of the transfer credit fails and is retried later.
+	var published []requests.EventEnvelope
+	failed := false
+	publisher := events.PublisherFunc(func(ctx context.Context, event requests.EventEnvelope) error {
+		if event.WalletID != from.WalletID && event.WalletID != to.WalletID {
+			return nil
+		}
+		if event.WalletID == to.WalletID && event.Type == requests.EventOperationApplied && !failed {
+			failed = true
+			return errors.New("broker unavailable")
+		}
+		published = append(published, event)
+		return nil
+	})
+	dispatchAll := func() {
+		for {
+			_, more, err := storage.DispatchEvents(ctx, publisher)
+			require.NoError(t, err)
+			if !more {
+				return
+			}
+		}
+	}
+
+	dispatchAll()
+	require.True(t, failed)
+	var types []string
+	for _, event := range published {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{
+		requests.EventWalletCreated, requests.EventWalletCreated,
+		requests.EventOperationApplied, requests.EventOperationApplied,
+		requests.EventWalletStatusChanged,
+	}, types)
+
+	var debit requests.OperationAppliedV1
+	require.NoError(t, json.Unmarshal(published[3].Data, &debit))
+	assert.Equal(t, from.WalletID, debit.WalletID)
+	assert.Equal(t, "WITHDRAW", debit.OperationType)
+	assert.Equal(t, models.Amount(300), debit.Balance)
+	assert.NotNil(t, debit.TransferID)
+	assert.Equal(t, 1, published[3].Version)
+
+	var lastError string
+	err = storage.db.QueryRowContext(ctx, `
+    SELECT last_error FROM outbox_events WHERE wallet_id = $1 AND type = $2 AND delivered_at IS NULL
+    `, to.WalletID, requests.EventOperationApplied).Scan(&lastError)
+	require.NoError(t, err)
+	assert.Equal(t, "broker unavailable", lastError)
+
+	// Nothing is republished before the retry is due.
+	published = nil
+	dispatchAll()
+	assert.Empty(t, published)
+
+	_, err = storage.db.ExecContext(ctx, "UPDATE outbox_events SET next_attempt_at = $1 WHERE wallet_id = $2 AND delivered_at IS NULL",
+		time.Now().UTC(), to.WalletID)
+	require.NoError(t, err)
+	dispatchAll()
+	require.Len(t, published, 1)
+	var credit requests.OperationAppliedV1
+	require.NoError(t, json.Unmarshal(published[0].Data, &credit))
+	assert.Equal(t, models.Amount(200), credit.Balance)
+	assert.Equal(t, debit.TransferID, credit.TransferID)
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// spendable returns how much can still be debited from wallet: its balance less active holds,
+// plus its overdraft limit. The caller must hold the wallet row lock for the result to stay valid.
+func spendable(ctx context.Context, q queryer, wallet *models.Wallets) (models.Amount, error) {
+	held, err := heldAmount(ctx, q, wallet.WalletID)
+	if err != nil {
+		return 0, err
+	}
+	headroom, err := (wallet.Balance - held).Add(wallet.OverdraftLimit)
+	if err != nil {
+		// More than fits in 64 bits is as good as no limit.
+		return math.MaxInt64, nil
+	}
+	return headroom, nil
+}
+
+// SetOverdraftLimit changes how far below zero a wallet's balance may go.
+// Lowering the limit under what the wallet already owes only blocks further debits.
+func (s *Storage) SetOverdraftLimit(ctx context.Context, walletID string, limit models.Amount) (*requests.WalletBalanceResponse, error) {
+	op := "database.SetOverdraftLimit"
+
+	res, err := s.db.ExecContext(ctx, "UPDATE wallets SET overdraft_limit = $1 WHERE wallet_id = $2", limit, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if updated == 0 {
+		return nil, fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, sql.ErrNoRows)
+	}
+	return s.GetWalletBalance(ctx, walletID)
+}
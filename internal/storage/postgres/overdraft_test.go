@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverdraftLimit(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100})))
+
+	_, err := storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 101})
+	var insufficientFundsErr requests.InsufficientFundsError
+	require.ErrorAs(t, err, &insufficientFundsErr)
+	assert.Equal(t, models.Amount(100), insufficientFundsErr.Headroom)
+
+	balance, err := storage.SetOverdraftLimit(ctx, walletID, 500)
+	require.NoError(t, err)
+	assert.Equal(t, 500, balance.OverdraftLimit)
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 400})))
+	_, err = storage.CreateHold(ctx, requests.CreateHoldRequest{WalletID: walletID, Amount: 150})
+	require.NoError(t, err)
+
+	_, err = storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 51})
+	require.ErrorAs(t, err, &insufficientFundsErr)
+	assert.Equal(t, models.Amount(50), insufficientFundsErr.Headroom)
+
+	ledgerBalance, operationsSum, _ := walletState(t, storage, walletID)
+	assert.Equal(t, -300, ledgerBalance)
+	assert.Equal(t, -300, operationsSum)
+
+	_, err = storage.SetOverdraftLimit(ctx, uuid.New().String(), 500)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
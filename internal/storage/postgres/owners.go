@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+const ownerColumns = `id, external_id, display_name, metadata, created_at`
+
+// CreateOwner records the owner wallets can be opened for. External ids are unique.
+func (s *Storage) CreateOwner(ctx context.Context, req requests.CreateOwnerRequest) (*models.Owner, error) {
+	op := "database.CreateOwner"
+
+	owner := &models.Owner{
+		ID:          genUUID(),
+		ExternalID:  req.ExternalID,
+		DisplayName: req.DisplayName,
+		CreatedAt:   time.Now().UTC(),
+		Metadata:    presentJSON(req.Metadata),
+	}
+	_, err := s.db.ExecContext(ctx, `
+    INSERT INTO owners (id, external_id, display_name, metadata, created_at) VALUES ($1, $2, $3, $4, $5)
+    `, owner.ID, owner.ExternalID, owner.DisplayName, nullJSON(owner.Metadata), owner.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err, "owners_external_id_key") {
+			return nil, requests.OwnerExistsError{}
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return owner, nil
+}
+
+func (s *Storage) GetOwner(ctx context.Context, ownerID string) (*models.Owner, error) {
+	op := "database.GetOwner"
+
+	owner, err := scanOwner(s.db.QueryRowContext(ctx, `SELECT `+ownerColumns+` FROM owners WHERE id = $1`, ownerID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: owner with id %s not found: %w", op, ownerID, err)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return owner, nil
+}
+
+// GetOwnerByExternalID returns the owner known to the calling service as externalID.
+func (s *Storage) GetOwnerByExternalID(ctx context.Context, externalID string) (*models.Owner, error) {
+	op := "database.GetOwnerByExternalID"
+
+	owner, err := scanOwner(s.db.QueryRowContext(ctx, `SELECT `+ownerColumns+` FROM owners WHERE external_id = $1`, externalID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: owner with external id %s not found: %w", op, externalID, err)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return owner, nil
+}
+
+// SetWalletOwner hands a wallet over to another owner. Its sub-wallets keep theirs.
+func (s *Storage) SetWalletOwner(ctx context.Context, walletID, ownerID string) (*requests.WalletBalanceResponse, error) {
+	op := "database.SetWalletOwner"
+
+	if err := checkOwnerExists(ctx, s.db, ownerID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	res, err := s.db.ExecContext(ctx, "UPDATE wallets SET owner_id = $1 WHERE wallet_id = $2", ownerID, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if updated == 0 {
+		return nil, fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, sql.ErrNoRows)
+	}
+	return s.GetWalletBalance(ctx, walletID)
+}
+
+// ListWallets returns a page of the wallets matching filter with their available balances.
+// Filtering on an owner that does not exist returns OwnerNotFoundError rather than an empty page.
+func (s *Storage) ListWallets(ctx context.Context, filter requests.WalletsFilter) (*requests.WalletsPage, error) {
+	op := "database.ListWallets"
+
+	if filter.OwnerID != "" {
+		if err := checkOwnerExists(ctx, s.db, filter.OwnerID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	conditions := []string{"TRUE"}
+	args := []interface{}{models.HoldActive, time.Now().UTC()}
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+	if filter.OwnerID != "" {
+		addCondition("wallets.owner_id = $%d", filter.OwnerID)
+	}
+	if filter.Status != "" {
+		addCondition("wallets.status = $%d", filter.Status)
+	}
+	if filter.Currency != "" {
+		addCondition("wallets.currency = $%d", filter.Currency)
+	}
+	if filter.Tier != "" {
+		addCondition("wallets.tier = $%d", filter.Tier)
+	}
+	if filter.MinBalance != nil {
+		addCondition("wallets.balance >= $%d", *filter.MinBalance)
+	}
+	if filter.MaxBalance != nil {
+		addCondition("wallets.balance <= $%d", *filter.MaxBalance)
+	}
+
+	column, direction, comparison := "wallets.created_at", "ASC", ">"
+	if filter.Sort == requests.SortByBalance {
+		column = "wallets.balance"
+	}
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	if filter.Cursor != nil {
+		var value interface{} = filter.Cursor.CreatedAt.UTC()
+		if filter.Sort == requests.SortByBalance {
+			value = filter.Cursor.Balance
+		}
+		args = append(args, value, filter.Cursor.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, wallets.wallet_id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)))
+	}
+	args = append(args, filter.Limit+1)
+
+	query := fmt.Sprintf(`
+    SELECT wallets.wallet_id, wallets.balance, wallets.currency, wallets.overdraft_limit, wallets.status, wallets.tier,
+        wallets.interest_rate_bps, wallets.parent_id, wallets.owner_id, wallets.created_at, COALESCE(held.amount, 0)
+    FROM wallets
+    LEFT JOIN LATERAL (
+        SELECT SUM(amount) AS amount FROM holds WHERE holds.wallet_id = wallets.wallet_id AND status = $1 AND expires_at > $2
+    ) held ON TRUE
+    WHERE %s
+    ORDER BY %s %s, wallets.wallet_id %s
+    LIMIT $%d
+    `, strings.Join(conditions, " AND "), column, direction, direction, len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	page := &requests.WalletsPage{
+		Wallets: make([]requests.WalletBalanceResponse, 0, filter.Limit),
+	}
+	for rows.Next() {
+		var (
+			wallet requests.WalletBalanceResponse
+			held   models.Amount
+		)
+		wallet.CreatedAt = new(time.Time)
+		err = rows.Scan(&wallet.WalletID, &wallet.Balance, &wallet.Currency, &wallet.OverdraftLimit, &wallet.Status,
+			&wallet.Tier, &wallet.InterestRate, &wallet.ParentID, &wallet.OwnerID, wallet.CreatedAt, &held)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		wallet.Available = wallet.Balance - held
+		page.Wallets = append(page.Wallets, wallet)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(page.Wallets) > filter.Limit {
+		page.Wallets = page.Wallets[:filter.Limit]
+		last := page.Wallets[len(page.Wallets)-1]
+		cursor := requests.WalletsCursor{CreatedAt: *last.CreatedAt, Balance: last.Balance, ID: last.WalletID}
+		page.NextCursor = cursor.Encode(filter.Sort)
+	}
+	return page, nil
+}
+
+// checkOwnerExists returns OwnerNotFoundError unless ownerID is a known owner.
+func checkOwnerExists(ctx context.Context, q queryer, ownerID string) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM owners WHERE id = $1)", ownerID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return requests.OwnerNotFoundError{OwnerID: ownerID}
+	}
+	return nil
+}
+
+func scanOwner(row scanner) (*models.Owner, error) {
+	var (
+		owner    models.Owner
+		metadata []byte
+	)
+	err := row.Scan(&owner.ID, &owner.ExternalID, &owner.DisplayName, &metadata, &owner.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata) > 0 {
+		owner.Metadata = metadata
+	}
+	return &owner, nil
+}
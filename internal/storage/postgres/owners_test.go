@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwners(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	externalID := "user-" + genUUID()
+	owner, err := storage.CreateOwner(ctx, requests.CreateOwnerRequest{ExternalID: externalID, DisplayName: "Ada", Metadata: []byte(`{"plan": "pro"}`)})
+	require.NoError(t, err)
+	_, err = storage.CreateOwner(ctx, requests.CreateOwnerRequest{ExternalID: externalID, DisplayName: "Ada again"})
+	assert.ErrorAs(t, err, &requests.OwnerExistsError{})
+
+	found, err := storage.GetOwnerByExternalID(ctx, externalID)
+	require.NoError(t, err)
+	assert.Equal(t, owner.ID, found.ID)
+	assert.JSONEq(t, `{"plan": "pro"}`, string(found.Metadata))
+
+	_, err = storage.CreateWallet(ctx, requests.CreateWalletRequest{OwnerID: genUUID()})
+	assert.ErrorAs(t, err, &requests.OwnerNotFoundError{})
+
+	first, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{OwnerID: owner.ID})
+	require.NoError(t, err)
+	second, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{OwnerID: owner.ID})
+	require.NoError(t, err)
+	// Sub-wallets default to the owner of their parent.
+	child, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{ParentID: first.WalletID})
+	require.NoError(t, err)
+	require.NotNil(t, child.OwnerID)
+	assert.Equal(t, owner.ID, *child.OwnerID)
+	// Wallets opened before owners existed can be handed to one.
+	legacy, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	_, err = storage.SetWalletOwner(ctx, legacy.WalletID, owner.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: second.WalletID, OperationType: "DEPOSIT", Amount: 500})))
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: legacy.WalletID, OperationType: "DEPOSIT", Amount: 200})))
+	_, err = storage.CreateHold(ctx, requests.CreateHoldRequest{WalletID: second.WalletID, Amount: 100})
+	require.NoError(t, err)
+
+	filter := requests.WalletsFilter{OwnerID: owner.ID, Sort: requests.SortByBalance, Descending: true, Limit: 2}
+	page, err := storage.ListWallets(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, page.Wallets, 2)
+	assert.Equal(t, second.WalletID, page.Wallets[0].WalletID)
+	assert.Equal(t, models.Amount(400), page.Wallets[0].Available)
+	assert.Equal(t, legacy.WalletID, page.Wallets[1].WalletID)
+	require.NotEmpty(t, page.NextCursor)
+
+	filter.Cursor, err = requests.DecodeWalletsCursor(page.NextCursor, filter.Sort)
+	require.NoError(t, err)
+	page, err = storage.ListWallets(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, page.Wallets, 2)
+	assert.Empty(t, page.NextCursor)
+	assert.ElementsMatch(t, []string{first.WalletID, child.WalletID}, []string{page.Wallets[0].WalletID, page.Wallets[1].WalletID})
+
+	minBalance := models.Amount(1)
+	page, err = storage.ListWallets(ctx, requests.WalletsFilter{OwnerID: owner.ID, MinBalance: &minBalance, Sort: requests.SortByCreatedAt, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Wallets, 2)
+	assert.Equal(t, second.WalletID, page.Wallets[0].WalletID)
+	assert.Equal(t, legacy.WalletID, page.Wallets[1].WalletID)
+
+	_, err = storage.ListWallets(ctx, requests.WalletsFilter{OwnerID: genUUID(), Sort: requests.SortByCreatedAt, Limit: 10})
+	assert.ErrorAs(t, err, &requests.OwnerNotFoundError{})
+}
@@ -1,31 +1,63 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/foreground-eclipse/wallet/config"
 	requests "github.com/foreground-eclipse/wallet/internal/api"
 	"github.com/foreground-eclipse/wallet/internal/models"
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 // docker run --name walletDB -p 5432:5432 -e POSTGRES_USER=postgres -e POSTGRES_PASSWORD=Tatsh -e POSTGRES_DB=wallet -d postgres
 type Storage struct {
-	db *sql.DB
+	db              *sql.DB
+	defaultCurrency string                    // Currency of wallets created without an explicit one
+	holdTTL         time.Duration             // Lifetime of holds created without an explicit TTL
+	limits          requests.WithdrawalLimits // Global withdrawal limits, overridable per wallet
+	autoCreate      bool                      // Create unknown wallets on their first operation
+	schedules       config.ScheduleConfig     // Retry policy of scheduled operations
+	feeWallets      map[string]string         // Fee-revenue wallet per currency
+	feeRules        []models.FeeRule          // Rules read from feeRulesFile; the fee_rules table is used when empty
+	feeRulesFile    string
+	fxQuoteTTL      time.Duration        // How long a quoted FX rate can be exchanged at
+	outbox          config.OutboxConfig  // Batch size and retry policy of the outbox dispatcher
+	webhooks        config.WebhookConfig // Retry policy of webhook deliveries
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 func genUUID() string {
 	return uuid.New().String()
 }
 
+// isUniqueViolation reports whether err was caused by a violation of the named unique constraint.
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
 func New(cfg *config.Config) (*Storage, error) {
 	const op = "storage.postgres.New"
 
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	connStr := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
 		cfg.Database.Host,
 		cfg.Database.User,
@@ -44,25 +76,33 @@ func New(cfg *config.Config) (*Storage, error) {
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	var feeRules []models.FeeRule
+	if cfg.Wallet.Fees.RulesFile != "" {
+		if feeRules, err = loadFeeRules(cfg.Wallet.Fees.RulesFile); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
 
 	return &Storage{
-		db: db,
+		db:              db,
+		defaultCurrency: cfg.Wallet.DefaultCurrency,
+		holdTTL:         cfg.Wallet.HoldTTL,
+		limits:          globalWithdrawalLimits(cfg.Wallet.Withdrawals),
+		autoCreate:      cfg.Wallet.AutoCreate,
+		schedules:       cfg.Wallet.Schedules,
+		feeWallets:      cfg.Wallet.Fees.Wallets,
+		feeRules:        feeRules,
+		feeRulesFile:    cfg.Wallet.Fees.RulesFile,
+		fxQuoteTTL:      cfg.Wallet.FX.QuoteTTL,
+		outbox:          cfg.Wallet.Outbox,
+		webhooks:        cfg.Wallet.Webhooks,
 	}, nil
 }
 
-func (s *Storage) CreateWallet(ctx context.Context, walletID string) error {
-	op := "database.CreateWallet"
-	_, err := s.db.ExecContext(ctx, "INSERT INTO wallets (wallet_id) VALUES ($1)", walletID)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-	return nil
-}
-
 func (s *Storage) GetWallet(ctx context.Context, walletID string) (*models.Wallets, error) {
 	op := "database.GetWallet"
 	var wallet models.Wallets
-	err := s.db.QueryRowContext(ctx, `SELECT wallet_id, balance FROM wallets WHERE wallet_id = $1`, walletID).Scan(&wallet.WalletID, &wallet.Balance)
+	err := s.db.QueryRowContext(ctx, `SELECT wallet_id, balance, currency, overdraft_limit, status, tier, interest_rate_bps, parent_id, owner_id, created_at FROM wallets WHERE wallet_id = $1`, walletID).Scan(&wallet.WalletID, &wallet.Balance, &wallet.Currency, &wallet.OverdraftLimit, &wallet.Status, &wallet.Tier, &wallet.InterestRate, &wallet.ParentID, &wallet.OwnerID, &wallet.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, err)
@@ -72,72 +112,304 @@ func (s *Storage) GetWallet(ctx context.Context, walletID string) (*models.Walle
 	return &wallet, nil
 }
 
-func (s *Storage) ProcessOperation(ctx context.Context, req requests.WalletOperationRequest) error {
+func (s *Storage) ProcessOperation(ctx context.Context, req requests.WalletOperationRequest) (*models.Operation, error) {
 	op := "database.ProcessOperation"
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	var operation *models.Operation
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var err error
+		_, operation, err = s.processOperation(ctx, tx, req)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, err
 	}
+	return operation, nil
+}
 
-	defer func() {
-		if err != nil {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				fmt.Printf("transaction rollback failed: %v", rbErr)
-			}
-		}
-	}()
+// processOperation applies req to its wallet inside tx and returns the wallet with its new balance
+// and the recorded operation.
+// With auto-create on, the wallet row is created if missing. It is locked before the balance is read,
+// so concurrent operations on the same wallet are serialized by postgres.
+// A fee due under the fee rules is charged on top of the amount, to the fee-revenue wallet of the currency,
+// which is locked together with the wallet.
+func (s *Storage) processOperation(ctx context.Context, tx *sql.Tx, req requests.WalletOperationRequest) (*models.Wallets, *models.Operation, error) {
+	op := "database.processOperation"
 
-	wallet, err := s.GetWallet(ctx, req.WalletID)
+	currency := req.Currency
+	if currency == "" {
+		currency = s.defaultCurrency
+	}
+	if s.autoCreate {
+		if err := autoCreateWallet(ctx, tx, req.WalletID, currency); err != nil {
+			return nil, nil, fmt.Errorf("%s: create wallet error: %w", op, err)
+		}
+	}
+	rules, err := s.feeRulesFor(ctx, tx, req.OperationType)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			if err = s.CreateWallet(ctx, req.WalletID); err != nil {
-				return fmt.Errorf("%s: create wallet error: %w", op, err)
-			}
-		} else {
-			return fmt.Errorf("%s: get wallet error: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: get fee rules error: %w", op, err)
+	}
+	lock := []string{req.WalletID}
+	var feeWalletID string
+	if len(rules) > 0 {
+		if feeWalletID, err = s.feeWalletFor(ctx, tx, req); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
 		}
-
-		wallet = &models.Wallets{
-			WalletID: req.WalletID,
-			Balance:  0,
+		if feeWalletID != "" {
+			lock = append(lock, feeWalletID)
+		}
+	}
+	wallets, err := lockWallets(ctx, tx, lock...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: get wallet error: %w", op, err)
+	}
+	wallet, ok := wallets[req.WalletID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: wallet with id %s not found: %w", op, req.WalletID, sql.ErrNoRows)
+	}
+	if req.Currency != "" && req.Currency != wallet.Currency {
+		return nil, nil, requests.CurrencyMismatchError{WalletCurrency: wallet.Currency, Currency: req.Currency}
+	}
+	var fee models.Amount
+	var feeWallet *models.Wallets
+	if feeWalletID == "" || canonicalID(feeWalletID) != canonicalID(wallet.WalletID) {
+		if fee, err = operationFee(rules, req, wallet); err != nil {
+			return nil, nil, err
 		}
 	}
-	var newBalance int
+	if fee > 0 {
+		if feeWallet, err = checkFeeWallet(wallets[feeWalletID], wallet); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err = checkWalletStatus(wallet, req.OperationType == "WITHDRAW" || fee > 0); err != nil {
+		return nil, nil, err
+	}
+
 	switch req.OperationType {
 	case "DEPOSIT":
-		newBalance = wallet.Balance + req.Amount
+		if fee > 0 {
+			headroom, err := spendable(ctx, tx, wallet)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: sum holds error: %w", op, err)
+			}
+			// A fee larger than the deposit is paid out of what the wallet already had.
+			if credited, err := headroom.Add(req.Amount); err == nil && credited < fee {
+				return nil, nil, requests.InsufficientFundsError{Headroom: headroom}
+			}
+		}
 	case "WITHDRAW":
-		if wallet.Balance < req.Amount {
-			return requests.InsufficientFundsError{}
+		if err = s.checkWithdrawalLimits(ctx, tx, req.WalletID, req.Amount); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		debit, err := req.Amount.Add(fee)
+		if err != nil {
+			return nil, nil, err
+		}
+		headroom, err := spendable(ctx, tx, wallet)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: sum holds error: %w", op, err)
+		}
+		if headroom < debit {
+			return nil, nil, requests.InsufficientFundsError{Headroom: headroom}
 		}
-		newBalance = wallet.Balance - req.Amount
 	default:
-		return fmt.Errorf("%s: invalid operation type", op)
+		return nil, nil, fmt.Errorf("%s: invalid operation type", op)
+	}
+	operation := &models.Operation{Type: req.OperationType, Amount: req.Amount, Fee: fee, Metadata: presentJSON(req.Metadata)}
+	if req.Description != "" {
+		operation.Description = &req.Description
+	}
+	if req.ExternalReference != "" {
+		operation.ExternalReference = &req.ExternalReference
 	}
-	_, err = tx.ExecContext(ctx, "UPDATE wallets SET balance = $1 WHERE wallet_id = $2", newBalance, req.WalletID)
+	if err = applyOperation(ctx, tx, wallet, operation, externalCashAccount); err != nil {
+		if isUniqueViolation(err, "operations_external_reference_key") {
+			return nil, nil, requests.ExternalReferenceConflictError{ExternalReference: req.ExternalReference}
+		}
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if fee > 0 {
+		if err = chargeFee(ctx, tx, wallet, feeWallet, operation); err != nil {
+			return nil, nil, fmt.Errorf("%s: charge fee error: %w", op, err)
+		}
+	}
+
+	return wallet, operation, nil
+}
+
+// applyOperation books a DEPOSIT, WITHDRAW or INTEREST on a wallet locked by the caller: it updates the balance,
+// records the operation and posts it to the ledger against the counterAccount system account.
+// The caller sets the type, amount and any references of operation; the rest is filled in here.
+// Funds checks are the caller's job; a balance that would not fit in 64 bits fails with models.ErrAmountOverflow.
+func applyOperation(ctx context.Context, tx *sql.Tx, wallet *models.Wallets, operation *models.Operation, counterAccount string) error {
+	signed := operation.SignedAmount()
+	balance, err := wallet.Balance.Add(signed)
 	if err != nil {
-		return fmt.Errorf("%s: update wallet error: %w", op, err)
+		return err
 	}
-	operation := models.Operation{
-		ID:        genUUID(),
-		WalletID:  req.WalletID,
-		Type:      req.OperationType,
-		Amount:    req.Amount,
-		Timestamp: time.Now(),
+	wallet.Balance = balance
+
+	_, err = tx.ExecContext(ctx, "UPDATE wallets SET balance = $1 WHERE wallet_id = $2", wallet.Balance, wallet.WalletID)
+	if err != nil {
+		return fmt.Errorf("update wallet error: %w", err)
+	}
+	operation.ID = genUUID()
+	operation.WalletID = wallet.WalletID
+	if operation.Timestamp, err = stampTime(ctx, tx); err != nil {
+		return err
+	}
+	operation.Currency = wallet.Currency
+	if err = insertOperation(ctx, tx, *operation); err != nil {
+		return fmt.Errorf("insert operation error: %w", err)
+	}
+	err = postJournalEntry(ctx, tx, operation.Type,
+		walletPosting(wallet.WalletID, signed, wallet.Currency, operation.ID),
+		systemPosting(counterAccount, -signed, wallet.Currency, operation.ID),
+	)
+	if err != nil {
+		return fmt.Errorf("post journal entry error: %w", err)
+	}
+	return nil
+}
+
+// lockWallets takes row locks on the given wallets in ascending id order,
+// so that two transactions locking the same pair can never deadlock.
+// Wallets that do not exist are absent from the returned map.
+func lockWallets(ctx context.Context, tx *sql.Tx, walletIDs ...string) (map[string]*models.Wallets, error) {
+	ids := sortedWalletIDs(walletIDs)
+	wallets := make(map[string]*models.Wallets, len(ids))
+	for _, id := range ids {
+		if _, ok := wallets[id]; ok {
+			continue
+		}
+		var wallet models.Wallets
+		err := tx.QueryRowContext(ctx, `SELECT wallet_id, balance, currency, overdraft_limit, status, tier, parent_id, owner_id FROM wallets WHERE wallet_id = $1 FOR UPDATE`, id).Scan(&wallet.WalletID, &wallet.Balance, &wallet.Currency, &wallet.OverdraftLimit, &wallet.Status, &wallet.Tier, &wallet.ParentID, &wallet.OwnerID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, err
+		}
+		wallets[id] = &wallet
 	}
-	_, err = tx.ExecContext(ctx, `
-    INSERT INTO operations (id, wallet_id, type, amount, timestamp)
-    VALUES ($1, $2, $3, $4, $5)
-    `, operation.ID, operation.WalletID, operation.Type, operation.Amount, operation.Timestamp)
+	return wallets, nil
+}
+
+// sortedWalletIDs returns a copy of walletIDs in the order wallets are locked in.
+func sortedWalletIDs(walletIDs []string) []string {
+	ids := make([]string, len(walletIDs))
+	copy(ids, walletIDs)
+	sort.Slice(ids, func(i, j int) bool {
+		return canonicalID(ids[i]) < canonicalID(ids[j])
+	})
+	return ids
+}
+
+// canonicalID returns the lowercase hyphenated form of a wallet id,
+// which sorts in the same order as postgres compares UUID values.
+func canonicalID(id string) string {
+	parsed, err := uuid.Parse(id)
 	if err != nil {
-		return fmt.Errorf("%s: insert operation error: %w", op, err)
+		return id
 	}
+	return parsed.String()
+}
 
-	err = tx.Commit()
+// nullJSON passes missing JSON, or a literal null, as NULL. lib/pq would send a nil json.RawMessage
+// as an empty string, which is not valid JSON.
+func nullJSON(raw json.RawMessage) interface{} {
+	if presentJSON(raw) == nil {
+		return nil
+	}
+	return raw
+}
 
-	return err
+// presentJSON returns raw, or nil when it is empty or a literal null.
+func presentJSON(raw json.RawMessage) json.RawMessage {
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+	return raw
+}
 
+// stampTime reads the database clock for an operation timestamp. Stamping on the database rather than
+// the replica keeps every stamp after the start of its transaction, which balance snapshots rely on.
+func stampTime(ctx context.Context, tx *sql.Tx) (time.Time, error) {
+	var now time.Time
+	if err := tx.QueryRowContext(ctx, "SELECT clock_timestamp()").Scan(&now); err != nil {
+		return time.Time{}, fmt.Errorf("read clock error: %w", err)
+	}
+	return now.UTC(), nil
+}
+
+// insertOperation records an operation and queues its OperationApplied event. The caller must have updated
+// the wallet balance already, as the event carries the balance right after the operation.
+func insertOperation(ctx context.Context, tx *sql.Tx, operation models.Operation) error {
+	_, err := tx.ExecContext(ctx, `
+    INSERT INTO operations (id, wallet_id, type, amount, timestamp, transfer_id, currency, reversal_of,
+        description, external_reference, metadata, fee, fee_for, exchange_rate, spread_bps)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+    `, operation.ID, operation.WalletID, operation.Type, operation.Amount, operation.Timestamp, operation.TransferID,
+		operation.Currency, operation.ReversalOf, operation.Description, operation.ExternalReference, nullJSON(operation.Metadata),
+		operation.Fee, operation.FeeFor, operation.ExchangeRate, operation.SpreadBps)
+	if err != nil {
+		return err
+	}
+
+	event := requests.OperationAppliedV1{
+		OperationID:       operation.ID,
+		WalletID:          operation.WalletID,
+		OperationType:     operation.Type,
+		Amount:            operation.Amount,
+		Currency:          operation.Currency,
+		Timestamp:         operation.Timestamp,
+		TransferID:        operation.TransferID,
+		ReversalOf:        operation.ReversalOf,
+		FeeFor:            operation.FeeFor,
+		ExternalReference: operation.ExternalReference,
+	}
+	err = tx.QueryRowContext(ctx, "SELECT balance FROM wallets WHERE wallet_id = $1", operation.WalletID).Scan(&event.Balance)
+	if err != nil {
+		return fmt.Errorf("get balance error: %w", err)
+	}
+	return enqueueEvent(ctx, tx, operation.WalletID, event)
+}
+
+// autoCreateWallet opens walletID in currency unless it exists, for operations on wallets nobody created.
+func autoCreateWallet(ctx context.Context, tx *sql.Tx, walletID, currency string) error {
+	res, err := tx.ExecContext(ctx, "INSERT INTO wallets (wallet_id, currency) VALUES ($1, $2) ON CONFLICT (wallet_id) DO NOTHING", walletID, currency)
+	if err != nil {
+		return err
+	}
+	created, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if created == 0 {
+		return nil
+	}
+	return enqueueEvent(ctx, tx, walletID, requests.WalletCreatedV1{WalletID: walletID, Currency: currency})
+}
+
+// withTx runs fn in a transaction, committing if fn succeeds and rolling back otherwise.
+func (s *Storage) withTx(ctx context.Context, op string, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			fmt.Printf("transaction rollback failed: %v", rbErr)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
 }
 
 func (s *Storage) GetWalletBalance(ctx context.Context, walletID string) (*requests.WalletBalanceResponse, error) {
@@ -149,8 +421,21 @@ func (s *Storage) GetWalletBalance(ctx context.Context, walletID string) (*reque
 		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	held, err := heldAmount(ctx, s.db, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 	return &requests.WalletBalanceResponse{
-		WalletID: wallet.WalletID,
-		Balance:  wallet.Balance,
+		WalletID:       wallet.WalletID,
+		Balance:        wallet.Balance,
+		Available:      wallet.Balance - held,
+		Currency:       wallet.Currency,
+		OverdraftLimit: wallet.OverdraftLimit,
+		Status:         wallet.Status,
+		Tier:           wallet.Tier,
+		InterestRate:   wallet.InterestRate,
+		ParentID:       wallet.ParentID,
+		OwnerID:        wallet.OwnerID,
+		CreatedAt:      &wallet.CreatedAt,
 	}, nil
 }
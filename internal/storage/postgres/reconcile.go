@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+const reconciliationAccount = "reconciliation" // Counterpart of balance corrections made by cmd/reconcile
+
+// signedAmountSQL is the effect of an operation row on its wallet balance.
+const signedAmountSQL = `CASE operations.type WHEN 'DEPOSIT' THEN operations.amount WHEN 'INTEREST' THEN operations.amount WHEN 'WITHDRAW' THEN -operations.amount ELSE 0 END`
+
+// ReconcileBalances walks all wallets in wallet_id order, batchSize at a time, and calls fn for every wallet
+// whose balance differs from the sum of its DEPOSIT, INTEREST and WITHDRAW operations. It returns the number of wallets checked.
+// Each batch reads balances and operations in one statement, so a batch is a consistent snapshot.
+func (s *Storage) ReconcileBalances(ctx context.Context, batchSize int, fn func(models.BalanceMismatch) error) (int, error) {
+	op := "database.ReconcileBalances"
+
+	var after sql.NullString
+	checked := 0
+	for {
+		rows, err := s.db.QueryContext(ctx, `
+    SELECT batch.wallet_id, batch.balance, batch.currency, COALESCE(SUM(`+signedAmountSQL+`), 0)
+    FROM (
+        SELECT wallet_id, balance, currency FROM wallets
+        WHERE $1::uuid IS NULL OR wallet_id > $1::uuid
+        ORDER BY wallet_id
+        LIMIT $2
+    ) AS batch
+    LEFT JOIN operations ON operations.wallet_id = batch.wallet_id
+    GROUP BY batch.wallet_id, batch.balance, batch.currency
+    ORDER BY batch.wallet_id
+    `, after, batchSize)
+		if err != nil {
+			return checked, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var mismatches []models.BalanceMismatch
+		n := 0
+		for rows.Next() {
+			var wallet models.BalanceMismatch
+			if err = rows.Scan(&wallet.WalletID, &wallet.Balance, &wallet.Currency, &wallet.ComputedBalance); err != nil {
+				rows.Close()
+				return checked, fmt.Errorf("%s: %w", op, err)
+			}
+			n++
+			after = sql.NullString{String: wallet.WalletID, Valid: true}
+			if wallet.Balance != wallet.ComputedBalance {
+				mismatches = append(mismatches, wallet)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return checked, fmt.Errorf("%s: %w", op, err)
+		}
+		checked += n
+
+		for _, mismatch := range mismatches {
+			if err = fn(mismatch); err != nil {
+				return checked, err
+			}
+		}
+		if n < batchSize {
+			return checked, nil
+		}
+	}
+}
+
+// RepairBalance sets a wallet balance to the sum of its operations and records the change in balance_adjustments.
+// If the wallet ledger account disagrees with the new balance, the difference is posted against the system
+// reconciliation account. It returns the applied correction, which is zero if the wallet was fixed in the meantime.
+func (s *Storage) RepairBalance(ctx context.Context, walletID, reason string) (*models.BalanceMismatch, error) {
+	op := "database.RepairBalance"
+
+	var repaired *models.BalanceMismatch
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		wallets, err := lockWallets(ctx, tx, walletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallet error: %w", op, err)
+		}
+		wallet, ok := wallets[walletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, sql.ErrNoRows)
+		}
+
+		repaired = &models.BalanceMismatch{
+			WalletID: wallet.WalletID,
+			Currency: wallet.Currency,
+			Balance:  wallet.Balance,
+		}
+		err = tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(`+signedAmountSQL+`), 0) FROM operations WHERE wallet_id = $1`, walletID).Scan(&repaired.ComputedBalance)
+		if err != nil {
+			return fmt.Errorf("%s: sum operations error: %w", op, err)
+		}
+		if repaired.Difference() == 0 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE wallets SET balance = $1 WHERE wallet_id = $2", repaired.ComputedBalance, walletID)
+		if err != nil {
+			return fmt.Errorf("%s: update wallet error: %w", op, err)
+		}
+		_, err = tx.ExecContext(ctx, `
+    INSERT INTO balance_adjustments (id, wallet_id, old_balance, new_balance, reason, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6)
+    `, genUUID(), walletID, repaired.Balance, repaired.ComputedBalance, reason, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("%s: insert balance adjustment error: %w", op, err)
+		}
+		event := requests.BalanceAdjustedV1{
+			WalletID:   wallet.WalletID,
+			Currency:   wallet.Currency,
+			OldBalance: repaired.Balance,
+			NewBalance: repaired.ComputedBalance,
+			Reason:     reason,
+		}
+		if err = enqueueEvent(ctx, tx, walletID, event); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		var ledgerBalance models.Amount
+		err = tx.QueryRowContext(ctx, "SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = $1", walletAccount(walletID)).Scan(&ledgerBalance)
+		if err != nil {
+			return fmt.Errorf("%s: sum postings error: %w", op, err)
+		}
+		correction := repaired.ComputedBalance - ledgerBalance
+		if correction == 0 {
+			return nil
+		}
+		err = postJournalEntry(ctx, tx, "RECONCILIATION",
+			walletPosting(walletID, correction, wallet.Currency, ""),
+			systemPosting(reconciliationAccount, -correction, wallet.Currency, ""),
+		)
+		if err != nil {
+			return fmt.Errorf("%s: post journal entry error: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repaired, nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileAndRepairBalance(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 200})))
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 50})))
+
+	// Simulate drift: the balance is overwritten without a matching operation or posting.
+	_, err := storage.db.Exec("UPDATE wallets SET balance = 999 WHERE wallet_id = $1", walletID)
+	require.NoError(t, err)
+
+	var found *models.BalanceMismatch
+	checked, err := storage.ReconcileBalances(ctx, 2, func(mismatch models.BalanceMismatch) error {
+		if mismatch.WalletID == walletID {
+			found = &mismatch
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Positive(t, checked)
+	require.NotNil(t, found)
+	assert.Equal(t, models.Amount(999), found.Balance)
+	assert.Equal(t, models.Amount(150), found.ComputedBalance)
+
+	repaired, err := storage.RepairBalance(ctx, walletID, "test")
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(849), repaired.Difference())
+
+	balance, operationsSum, _ := walletState(t, storage, walletID)
+	assert.Equal(t, 150, balance)
+	assert.Equal(t, operationsSum, balance)
+
+	var adjustments int
+	require.NoError(t, storage.db.QueryRow("SELECT COUNT(*) FROM balance_adjustments WHERE wallet_id = $1", walletID).Scan(&adjustments))
+	assert.Equal(t, 1, adjustments)
+
+	repaired, err = storage.RepairBalance(ctx, walletID, "test")
+	require.NoError(t, err)
+	assert.Zero(t, repaired.Difference())
+}
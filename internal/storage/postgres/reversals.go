@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// ReverseOperation books a compensating operation of the opposite type that references the original one.
+// amount may be less than the original for a partial refund; zero reverses whatever is left.
+// Reversing a DEPOSIT withdraws money, so it is subject to the usual funds and withdrawal limit checks.
+// The fee charged on the original operation is not refunded.
+func (s *Storage) ReverseOperation(ctx context.Context, operationID string, amount models.Amount) (*requests.OperationResponse, error) {
+	op := "database.ReverseOperation"
+
+	var reversal *models.Operation
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var walletID string
+		err := tx.QueryRowContext(ctx, "SELECT wallet_id FROM operations WHERE id = $1", operationID).Scan(&walletID)
+		if err != nil {
+			return fmt.Errorf("%s: operation with id %s not found: %w", op, operationID, err)
+		}
+		wallets, err := lockWallets(ctx, tx, walletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallet error: %w", op, err)
+		}
+		wallet := wallets[walletID]
+
+		// Reversals of an operation always lock its wallet first, so the sum below cannot change under us.
+		var original models.Operation
+		err = tx.QueryRowContext(ctx, `
+    SELECT id, type, amount, transfer_id, reversal_of, fee_for FROM operations WHERE id = $1
+    `, operationID).Scan(&original.ID, &original.Type, &original.Amount, &original.TransferID, &original.ReversalOf, &original.FeeFor)
+		if err != nil {
+			return fmt.Errorf("%s: get operation error: %w", op, err)
+		}
+		if original.ReversalOf != nil {
+			return requests.OperationNotReversibleError{Reason: "it is a reversal"}
+		}
+		if original.TransferID != nil {
+			return requests.OperationNotReversibleError{Reason: "it is part of a transfer"}
+		}
+		if original.FeeFor != nil {
+			return requests.OperationNotReversibleError{Reason: "it is a fee"}
+		}
+
+		var reversed models.Amount
+		err = tx.QueryRowContext(ctx, "SELECT COALESCE(SUM(amount), 0) FROM operations WHERE reversal_of = $1", operationID).Scan(&reversed)
+		if err != nil {
+			return fmt.Errorf("%s: sum reversals error: %w", op, err)
+		}
+		remaining := original.Amount - reversed
+		if amount == 0 {
+			amount = remaining
+		}
+		if remaining == 0 || amount > remaining {
+			return requests.ReversalExceedsOriginalError{Remaining: remaining}
+		}
+
+		reversal = &models.Operation{Amount: amount, ReversalOf: &original.ID}
+		switch original.Type {
+		case "DEPOSIT":
+			reversal.Type = "WITHDRAW"
+			if err = checkWalletStatus(wallet, true); err != nil {
+				return err
+			}
+			if err = s.checkWithdrawalLimits(ctx, tx, walletID, amount); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			headroom, err := spendable(ctx, tx, wallet)
+			if err != nil {
+				return fmt.Errorf("%s: sum holds error: %w", op, err)
+			}
+			if headroom < amount {
+				return requests.InsufficientFundsError{Headroom: headroom}
+			}
+		case "WITHDRAW":
+			reversal.Type = "DEPOSIT"
+			if err = checkWalletStatus(wallet, false); err != nil {
+				return err
+			}
+		default:
+			return requests.OperationNotReversibleError{Reason: "unsupported operation type " + original.Type}
+		}
+
+		if err = applyOperation(ctx, tx, wallet, reversal, externalCashAccount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &requests.OperationResponse{
+		ID:            reversal.ID,
+		WalletID:      reversal.WalletID,
+		OperationType: reversal.Type,
+		Amount:        reversal.Amount,
+		Currency:      reversal.Currency,
+		Timestamp:     reversal.Timestamp,
+		ReversalOf:    reversal.ReversalOf,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseOperation(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100})))
+	page, err := storage.ListOperations(ctx, requests.OperationsFilter{WalletID: walletID, Limit: 1})
+	require.NoError(t, err)
+	deposit := page.Operations[0]
+
+	partial, err := storage.ReverseOperation(ctx, deposit.ID, 30)
+	require.NoError(t, err)
+	assert.Equal(t, "WITHDRAW", partial.OperationType)
+	assert.Equal(t, deposit.ID, *partial.ReversalOf)
+
+	_, err = storage.ReverseOperation(ctx, deposit.ID, 71)
+	assert.ErrorAs(t, err, &requests.ReversalExceedsOriginalError{})
+
+	_, err = storage.ReverseOperation(ctx, partial.ID, 0)
+	assert.ErrorAs(t, err, &requests.OperationNotReversibleError{})
+
+	// The rest of the deposit was already spent.
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 50})))
+	_, err = storage.ReverseOperation(ctx, deposit.ID, 0)
+	assert.ErrorAs(t, err, &requests.InsufficientFundsError{})
+
+	rest, err := storage.ReverseOperation(ctx, deposit.ID, 20)
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(20), rest.Amount)
+
+	balance, operationsSum, _ := walletState(t, storage, walletID)
+	assert.Equal(t, 0, balance)
+	assert.Equal(t, operationsSum, balance)
+}
@@ -0,0 +1,309 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+const scheduleColumns = `id, wallet_id, type, amount, currency, interval_unit, interval_count, start_at, end_at, next_run_at,
+    occurrence, status, attempts, last_error, last_operation_id, created_at, updated_at`
+
+// CreateSchedule registers a future-dated or recurring operation on an existing wallet.
+func (s *Storage) CreateSchedule(ctx context.Context, req requests.CreateScheduleRequest) (*models.Schedule, error) {
+	op := "database.CreateSchedule"
+
+	wallet, err := s.GetWallet(ctx, req.WalletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if req.Currency != "" && req.Currency != wallet.Currency {
+		return nil, requests.CurrencyMismatchError{WalletCurrency: wallet.Currency, Currency: req.Currency}
+	}
+	if wallet.Status == models.WalletClosed {
+		return nil, requests.WalletNotActiveError{Status: wallet.Status}
+	}
+
+	now := time.Now().UTC()
+	schedule := &models.Schedule{
+		ID:            genUUID(),
+		WalletID:      wallet.WalletID,
+		Type:          req.OperationType,
+		Amount:        req.Amount,
+		Currency:      wallet.Currency,
+		IntervalCount: 1,
+		StartAt:       now,
+		EndAt:         req.EndAt,
+		Status:        models.ScheduleActive,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if req.StartAt != nil {
+		schedule.StartAt = req.StartAt.UTC()
+	}
+	if req.Interval != "" {
+		schedule.Interval = &req.Interval
+	}
+	if req.IntervalCount > 0 {
+		schedule.IntervalCount = req.IntervalCount
+	}
+	if schedule.EndAt != nil {
+		endAt := schedule.EndAt.UTC()
+		schedule.EndAt = &endAt
+	}
+	schedule.NextRunAt = schedule.StartAt
+
+	_, err = s.db.ExecContext(ctx, `
+    INSERT INTO schedules (`+scheduleColumns+`)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+    `, schedule.ID, schedule.WalletID, schedule.Type, schedule.Amount, schedule.Currency, schedule.Interval, schedule.IntervalCount,
+		schedule.StartAt, schedule.EndAt, schedule.NextRunAt, schedule.Occurrence, schedule.Status, schedule.Attempts,
+		schedule.LastError, schedule.LastOperationID, schedule.CreatedAt, schedule.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return schedule, nil
+}
+
+func (s *Storage) GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	op := "database.GetSchedule"
+
+	schedule, err := scanSchedule(s.db.QueryRowContext(ctx, `SELECT `+scheduleColumns+` FROM schedules WHERE id = $1`, scheduleID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return schedule, nil
+}
+
+// ListSchedules returns all schedules of a wallet, oldest first.
+func (s *Storage) ListSchedules(ctx context.Context, walletID string) ([]models.Schedule, error) {
+	op := "database.ListSchedules"
+
+	if _, err := s.GetWallet(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+scheduleColumns+` FROM schedules WHERE wallet_id = $1 ORDER BY created_at, id`, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	schedules := make([]models.Schedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		schedules = append(schedules, *schedule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return schedules, nil
+}
+
+// UpdateSchedule changes the amount, end or status of a schedule. Resuming a FAILED schedule
+// retries the occurrence it failed on straight away.
+func (s *Storage) UpdateSchedule(ctx context.Context, scheduleID string, req requests.UpdateScheduleRequest) (*models.Schedule, error) {
+	op := "database.UpdateSchedule"
+
+	var schedule *models.Schedule
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var err error
+		schedule, err = lockSchedule(ctx, tx, scheduleID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if req.Amount != nil {
+			schedule.Amount = *req.Amount
+		}
+		if req.EndAt != nil {
+			endAt := req.EndAt.UTC()
+			schedule.EndAt = &endAt
+		}
+		if req.Status != nil && *req.Status != schedule.Status {
+			if schedule.Status == models.ScheduleFailed {
+				schedule.Attempts = 0
+				schedule.NextRunAt = schedule.OccurrenceAt(schedule.Occurrence)
+			}
+			schedule.Status = *req.Status
+		}
+		schedule.UpdatedAt = time.Now().UTC()
+		return updateSchedule(ctx, tx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// CancelSchedule stops a schedule for good. Operations it already ran are kept.
+func (s *Storage) CancelSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	op := "database.CancelSchedule"
+
+	var schedule *models.Schedule
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var err error
+		schedule, err = lockSchedule(ctx, tx, scheduleID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		schedule.Status = models.ScheduleCancelled
+		schedule.UpdatedAt = time.Now().UTC()
+		return updateSchedule(ctx, tx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// RunDueSchedules runs every occurrence due at now and returns how many were attempted.
+// Each occurrence runs in its own transaction together with the schedule update, and due schedules
+// are claimed with SKIP LOCKED, so several replicas can run this at once without running anything twice.
+// Occurrences missed while no scheduler was running are all caught up.
+func (s *Storage) RunDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	op := "database.RunDueSchedules"
+
+	ran := 0
+	for {
+		found, err := s.runNextSchedule(ctx, now.UTC())
+		if err != nil {
+			return ran, fmt.Errorf("%s: %w", op, err)
+		}
+		if !found {
+			return ran, nil
+		}
+		ran++
+	}
+}
+
+func (s *Storage) runNextSchedule(ctx context.Context, now time.Time) (bool, error) {
+	op := "database.runNextSchedule"
+
+	found := false
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		schedule, err := scanSchedule(tx.QueryRowContext(ctx, `
+    SELECT `+scheduleColumns+` FROM schedules
+    WHERE status = $1 AND next_run_at <= $2
+    ORDER BY next_run_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+    `, models.ScheduleActive, now))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("claim schedule error: %w", err)
+		}
+		found = true
+		schedule.UpdatedAt = now
+
+		if schedule.EndAt != nil && schedule.OccurrenceAt(schedule.Occurrence).After(*schedule.EndAt) {
+			schedule.Status = models.ScheduleCompleted
+			return updateSchedule(ctx, tx, schedule)
+		}
+
+		// A failed operation must not take the schedule update down with it.
+		if _, err = tx.ExecContext(ctx, "SAVEPOINT schedule_run"); err != nil {
+			return err
+		}
+		_, operation, runErr := s.processOperation(ctx, tx, requests.WalletOperationRequest{
+			WalletID:      schedule.WalletID,
+			OperationType: schedule.Type,
+			Amount:        schedule.Amount,
+			Currency:      schedule.Currency,
+		})
+		if runErr != nil {
+			if _, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT schedule_run"); err != nil {
+				return err
+			}
+			s.scheduleFailed(schedule, runErr, now)
+		} else {
+			scheduleSucceeded(schedule, operation)
+		}
+		return updateSchedule(ctx, tx, schedule)
+	})
+	return found, err
+}
+
+// scheduleSucceeded moves a schedule on to its next occurrence, completing it after the last one.
+func scheduleSucceeded(schedule *models.Schedule, operation *models.Operation) {
+	schedule.Occurrence++
+	schedule.Attempts = 0
+	schedule.LastError = nil
+	schedule.LastOperationID = &operation.ID
+	next := schedule.OccurrenceAt(schedule.Occurrence)
+	if schedule.Interval == nil || (schedule.EndAt != nil && next.After(*schedule.EndAt)) {
+		schedule.Status = models.ScheduleCompleted
+		return
+	}
+	schedule.NextRunAt = next
+}
+
+// scheduleFailed applies the retry policy: failures that may clear up on their own, such as insufficient funds,
+// are retried with exponential backoff until the attempts run out. Anything that cannot succeed fails right away.
+func (s *Storage) scheduleFailed(schedule *models.Schedule, runErr error, now time.Time) {
+	message := runErr.Error()
+	schedule.LastError = &message
+	schedule.Attempts++
+	if !retryableScheduleError(runErr) || schedule.Attempts >= s.schedules.MaxAttempts {
+		schedule.Status = models.ScheduleFailed
+		return
+	}
+	schedule.NextRunAt = now.Add(backoff(s.schedules.RetryDelay, s.schedules.MaxRetryDelay, schedule.Attempts))
+}
+
+func retryableScheduleError(err error) bool {
+	var walletNotActiveErr requests.WalletNotActiveError
+	if errors.As(err, &walletNotActiveErr) {
+		return walletNotActiveErr.Status == models.WalletFrozen
+	}
+	var currencyMismatchErr requests.CurrencyMismatchError
+	return !errors.Is(err, sql.ErrNoRows) && !errors.As(err, &currencyMismatchErr)
+}
+
+// lockSchedule locks a schedule that can still be changed.
+func lockSchedule(ctx context.Context, tx *sql.Tx, scheduleID string) (*models.Schedule, error) {
+	schedule, err := scanSchedule(tx.QueryRowContext(ctx, `SELECT `+scheduleColumns+` FROM schedules WHERE id = $1 FOR UPDATE`, scheduleID))
+	if err != nil {
+		return nil, fmt.Errorf("schedule with id %s not found: %w", scheduleID, err)
+	}
+	if schedule.Status == models.ScheduleCompleted || schedule.Status == models.ScheduleCancelled {
+		return nil, requests.ScheduleFinishedError{Status: schedule.Status}
+	}
+	return schedule, nil
+}
+
+func updateSchedule(ctx context.Context, tx *sql.Tx, schedule *models.Schedule) error {
+	_, err := tx.ExecContext(ctx, `
+    UPDATE schedules SET amount = $1, end_at = $2, next_run_at = $3, occurrence = $4, status = $5, attempts = $6,
+        last_error = $7, last_operation_id = $8, updated_at = $9
+    WHERE id = $10
+    `, schedule.Amount, schedule.EndAt, schedule.NextRunAt, schedule.Occurrence, schedule.Status, schedule.Attempts,
+		schedule.LastError, schedule.LastOperationID, schedule.UpdatedAt, schedule.ID)
+	if err != nil {
+		return fmt.Errorf("update schedule error: %w", err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSchedule(row scanner) (*models.Schedule, error) {
+	var schedule models.Schedule
+	err := row.Scan(&schedule.ID, &schedule.WalletID, &schedule.Type, &schedule.Amount, &schedule.Currency, &schedule.Interval,
+		&schedule.IntervalCount, &schedule.StartAt, &schedule.EndAt, &schedule.NextRunAt, &schedule.Occurrence, &schedule.Status,
+		&schedule.Attempts, &schedule.LastError, &schedule.LastOperationID, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
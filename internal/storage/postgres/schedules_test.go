@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/config"
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleOccurrences(t *testing.T) {
+	month := models.IntervalMonth
+	schedule := models.Schedule{Interval: &month, IntervalCount: 1, StartAt: time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)}
+	assert.Equal(t, time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), schedule.OccurrenceAt(1))
+	assert.Equal(t, time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC), schedule.OccurrenceAt(2))
+	assert.Equal(t, time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), schedule.OccurrenceAt(12))
+
+	week := models.IntervalWeek
+	schedule = models.Schedule{Interval: &week, IntervalCount: 2, StartAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC), schedule.OccurrenceAt(2))
+}
+
+func TestScheduleRetryPolicy(t *testing.T) {
+	storage := &Storage{schedules: config.ScheduleConfig{RetryDelay: time.Minute, MaxRetryDelay: 90 * time.Second, MaxAttempts: 4}}
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	schedule := &models.Schedule{Status: models.ScheduleActive}
+
+	storage.scheduleFailed(schedule, requests.InsufficientFundsError{}, now)
+	assert.Equal(t, models.ScheduleActive, schedule.Status)
+	assert.Equal(t, now.Add(time.Minute), schedule.NextRunAt)
+	// The doubled delay is capped.
+	storage.scheduleFailed(schedule, requests.InsufficientFundsError{}, now)
+	assert.Equal(t, now.Add(90*time.Second), schedule.NextRunAt)
+	storage.scheduleFailed(schedule, requests.InsufficientFundsError{}, now)
+	assert.Equal(t, now.Add(90*time.Second), schedule.NextRunAt)
+	storage.scheduleFailed(schedule, requests.InsufficientFundsError{}, now)
+	assert.Equal(t, models.ScheduleFailed, schedule.Status)
+
+	// A huge attempt count neither overflows nor goes past the cap.
+	schedule = &models.Schedule{Status: models.ScheduleActive, Attempts: 100}
+	storage.schedules.MaxAttempts = 1000
+	storage.scheduleFailed(schedule, requests.InsufficientFundsError{}, now)
+	assert.Equal(t, now.Add(90*time.Second), schedule.NextRunAt)
+
+	schedule = &models.Schedule{Status: models.ScheduleActive}
+	storage.scheduleFailed(schedule, requests.WalletNotActiveError{Status: models.WalletClosed}, now)
+	assert.Equal(t, models.ScheduleFailed, schedule.Status)
+	assert.Equal(t, "wallet is CLOSED", *schedule.LastError)
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Wallet.Schedules = config.ScheduleConfig{RetryDelay: 0, MaxRetryDelay: time.Hour}
+	cfg.Wallet.Webhooks = config.WebhookConfig{Timeout: 5 * time.Second, Lease: time.Minute}
+	_, err := New(cfg)
+	assert.ErrorContains(t, err, "WALLET_SCHEDULE_RETRY_DELAY and WALLET_SCHEDULE_MAX_RETRY_DELAY must be positive")
+
+	cfg.Wallet.Schedules.RetryDelay = time.Minute
+	cfg.Wallet.Webhooks.Lease = 5 * time.Second
+	_, err = New(cfg)
+	assert.ErrorContains(t, err, "WALLET_WEBHOOK_LEASE must be longer than WALLET_WEBHOOK_TIMEOUT")
+}
+
+func TestRunDueSchedules(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 250})))
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	schedule, err := storage.CreateSchedule(ctx, requests.CreateScheduleRequest{
+		WalletID:      walletID,
+		OperationType: "WITHDRAW",
+		Amount:        100,
+		StartAt:       &start,
+		Interval:      models.IntervalDay,
+	})
+	require.NoError(t, err)
+
+	// Several replicas running at once still run each occurrence exactly once.
+	done := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			_, err := storage.RunDueSchedules(ctx, start.Add(2*24*time.Hour))
+			done <- err
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		require.NoError(t, <-done)
+	}
+
+	schedule, err = storage.GetSchedule(ctx, schedule.ID)
+	require.NoError(t, err)
+	// Two of the three due occurrences could be paid; the third is waiting for a retry.
+	assert.Equal(t, 2, schedule.Occurrence)
+	assert.Equal(t, 1, schedule.Attempts)
+	assert.Equal(t, models.ScheduleActive, schedule.Status)
+	require.NotNil(t, schedule.LastError)
+
+	balance, operationsSum, _ := walletState(t, storage, walletID)
+	assert.Equal(t, 50, balance)
+	assert.Equal(t, 50, operationsSum)
+
+	cancelled, err := storage.CancelSchedule(ctx, schedule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ScheduleCancelled, cancelled.Status)
+	_, err = storage.UpdateSchedule(ctx, schedule.ID, requests.UpdateScheduleRequest{})
+	assert.ErrorAs(t, err, &requests.ScheduleFinishedError{})
+}
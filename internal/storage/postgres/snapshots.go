@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// GetWalletBalanceAt returns the balance a wallet had at asOf, computed from its operations.
+// The sum starts from the latest balance snapshot taken at or before asOf, so long histories stay cheap.
+func (s *Storage) GetWalletBalanceAt(ctx context.Context, walletID string, asOf time.Time) (*requests.HistoricalBalanceResponse, error) {
+	op := "database.GetWalletBalanceAt"
+
+	wallet, err := s.GetWallet(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	resp := &requests.HistoricalBalanceResponse{
+		WalletID: wallet.WalletID,
+		Currency: wallet.Currency,
+		AsOf:     asOf,
+	}
+	resp.Balance, err = balanceAt(ctx, s.db, walletID, asOf, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return resp, nil
+}
+
+// balanceAt sums a wallet's operations up to at, or strictly before it unless inclusive,
+// starting from the latest balance snapshot that covers no operations past that bound.
+func balanceAt(ctx context.Context, q queryer, walletID string, at time.Time, inclusive bool) (models.Amount, error) {
+	cmp := "<"
+	if inclusive {
+		cmp = "<="
+	}
+	var balance models.Amount
+	err := q.QueryRowContext(ctx, `
+    WITH snapshot AS (
+        SELECT taken_at, balance FROM balance_snapshots
+        WHERE wallet_id = $1 AND taken_at `+cmp+` $2
+        ORDER BY taken_at DESC
+        LIMIT 1
+    )
+    SELECT COALESCE((SELECT balance FROM snapshot), 0) + COALESCE(SUM(`+signedAmountSQL+`), 0)
+    FROM operations
+    WHERE wallet_id = $1 AND timestamp `+cmp+` $2
+        AND timestamp > COALESCE((SELECT taken_at FROM snapshot), '-infinity'::timestamp)
+    `, walletID, at.UTC()).Scan(&balance)
+	return balance, err
+}
+
+// SnapshotBalances records the balance of every wallet with operations since its previous snapshot and returns
+// how many snapshots were taken. Snapshots are final, so they are taken at the given time or, if earlier, just
+// before the oldest transaction still open: operations are stamped by the database clock inside their transaction,
+// so anything stamped up to then has committed, however long it took.
+func (s *Storage) SnapshotBalances(ctx context.Context, at time.Time) (int, error) {
+	op := "database.SnapshotBalances"
+
+	// Sessions of other roles show no xact_start unless the role may read all stats; they do not write operations.
+	var watermark time.Time
+	err := s.db.QueryRowContext(ctx, `
+    SELECT LEAST($1::timestamptz, MIN(xact_start) - interval '1 microsecond')
+    FROM pg_stat_activity
+    WHERE datname = current_database() AND backend_type = 'client backend' AND pid <> pg_backend_pid()
+    `, at.UTC()).Scan(&watermark)
+	if err != nil {
+		return 0, fmt.Errorf("%s: find watermark error: %w", op, err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+    INSERT INTO balance_snapshots (wallet_id, taken_at, balance)
+    SELECT wallets.wallet_id, $1, COALESCE(previous.balance, 0) + changes.delta
+    FROM wallets
+    LEFT JOIN LATERAL (
+        SELECT taken_at, balance FROM balance_snapshots
+        WHERE balance_snapshots.wallet_id = wallets.wallet_id AND taken_at <= $1
+        ORDER BY taken_at DESC
+        LIMIT 1
+    ) AS previous ON true
+    CROSS JOIN LATERAL (
+        SELECT COUNT(*) AS count, COALESCE(SUM(`+signedAmountSQL+`), 0) AS delta
+        FROM operations
+        WHERE operations.wallet_id = wallets.wallet_id AND operations.timestamp <= $1
+            AND operations.timestamp > COALESCE(previous.taken_at, '-infinity'::timestamp)
+    ) AS changes
+    WHERE changes.count > 0
+    ON CONFLICT (wallet_id, taken_at) DO NOTHING
+    `, watermark.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	taken, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return int(taken), nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWalletBalanceAt(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100})))
+	first := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 30})))
+	second := time.Now()
+
+	taken, err := storage.SnapshotBalances(ctx, second)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, taken, 1)
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 5})))
+
+	for _, tt := range []struct {
+		asOf     time.Time
+		expected models.Amount
+	}{
+		{first.Add(-time.Hour), 0},
+		{first, 100},
+		{second, 70},
+		{time.Now(), 75},
+	} {
+		balance, err := storage.GetWalletBalanceAt(ctx, walletID, tt.asOf)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, balance.Balance, tt.asOf)
+	}
+
+	// A second run only snapshots wallets that changed since.
+	taken, err = storage.SnapshotBalances(ctx, second)
+	require.NoError(t, err)
+	assert.Equal(t, 0, taken)
+}
+
+func TestSnapshotWaitsForOpenTransactions(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100})))
+
+	// A deposit is stamped but commits only after a snapshot has been taken past its timestamp.
+	tx, err := storage.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	wallets, err := lockWallets(ctx, tx, walletID)
+	require.NoError(t, err)
+	require.NoError(t, applyOperation(ctx, tx, wallets[walletID], &models.Operation{Type: "DEPOSIT", Amount: 20}, externalCashAccount))
+	time.Sleep(10 * time.Millisecond)
+	_, err = storage.SnapshotBalances(ctx, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	balance, err := storage.GetWalletBalanceAt(ctx, walletID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(120), balance.Balance)
+
+	// Once nothing older is open, the next snapshot covers the late deposit.
+	_, err = storage.SnapshotBalances(ctx, time.Now())
+	require.NoError(t, err)
+	balance, err = storage.GetWalletBalanceAt(ctx, walletID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(120), balance.Balance)
+}
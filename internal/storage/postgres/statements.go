@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// StreamStatement builds the statement of a wallet for [from, to) without buffering it: begin is called with the
+// opening balance, then line for every operation in order, and the returned statement carries the closing balance.
+// Everything is read in one repeatable-read transaction, so the balances always add up.
+func (s *Storage) StreamStatement(ctx context.Context, walletID string, from, to time.Time,
+	begin func(models.Statement) error, line func(models.StatementLine) error) (*models.Statement, error) {
+	op := "database.StreamStatement"
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	statement := models.Statement{From: from, To: to}
+	err = tx.QueryRowContext(ctx, "SELECT wallet_id, currency FROM wallets WHERE wallet_id = $1", walletID).Scan(&statement.WalletID, &statement.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, err)
+	}
+	statement.OpeningBalance, err = balanceAt(ctx, tx, walletID, from, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: opening balance error: %w", op, err)
+	}
+	if err = begin(statement); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+    SELECT id, wallet_id, type, amount, timestamp, transfer_id, currency, reversal_of
+    FROM operations
+    WHERE wallet_id = $1 AND timestamp >= $2 AND timestamp < $3
+    ORDER BY timestamp, id
+    `, walletID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	balance := statement.OpeningBalance
+	for rows.Next() {
+		var l models.StatementLine
+		err = rows.Scan(&l.ID, &l.WalletID, &l.Type, &l.Amount, &l.Timestamp, &l.TransferID, &l.Currency, &l.ReversalOf)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		balance += l.SignedAmount()
+		l.Balance = balance
+		if err = line(l); err != nil {
+			return nil, err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	statement.ClosingBalance = balance
+	return &statement, nil
+}
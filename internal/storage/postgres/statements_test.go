@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamStatement(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+	walletID := uuid.New().String()
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 100})))
+	time.Sleep(10 * time.Millisecond)
+	from := time.Now()
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "WITHDRAW", Amount: 30})))
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 5})))
+	to := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: walletID, OperationType: "DEPOSIT", Amount: 1000})))
+
+	var opening models.Amount
+	var lines []models.StatementLine
+	statement, err := storage.StreamStatement(ctx, walletID, from, to,
+		func(s models.Statement) error {
+			opening = s.OpeningBalance
+			return nil
+		},
+		func(l models.StatementLine) error {
+			lines = append(lines, l)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(100), opening)
+	require.Len(t, lines, 2)
+	assert.Equal(t, models.Amount(70), lines[0].Balance)
+	assert.Equal(t, models.Amount(75), lines[1].Balance)
+	assert.Equal(t, models.Amount(75), statement.ClosingBalance)
+}
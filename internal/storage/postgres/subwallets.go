@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// subtreeSQL selects the ids of wallet $1 and every wallet under it, with their depth below it.
+const subtreeSQL = `
+    WITH RECURSIVE subtree (wallet_id, depth) AS (
+        SELECT wallet_id, 0 FROM wallets WHERE wallet_id = $1
+        UNION ALL
+        SELECT wallets.wallet_id, subtree.depth + 1 FROM wallets JOIN subtree ON wallets.parent_id = subtree.wallet_id
+    )`
+
+// GetWalletTree returns a wallet with every wallet under it, each with the balances of its own subtree.
+func (s *Storage) GetWalletTree(ctx context.Context, walletID string) (*requests.WalletTreeResponse, error) {
+	op := "database.GetWalletTree"
+
+	rows, err := s.db.QueryContext(ctx, subtreeSQL+`
+    SELECT wallets.wallet_id, wallets.balance, wallets.currency, wallets.overdraft_limit, wallets.status, wallets.tier,
+        wallets.interest_rate_bps, wallets.parent_id, wallets.owner_id, wallets.created_at, COALESCE(held.amount, 0)
+    FROM subtree
+    JOIN wallets ON wallets.wallet_id = subtree.wallet_id
+    LEFT JOIN (
+        SELECT wallet_id, SUM(amount) AS amount FROM holds WHERE status = $2 AND expires_at > $3 GROUP BY wallet_id
+    ) held ON held.wallet_id = subtree.wallet_id
+    ORDER BY subtree.depth, wallets.wallet_id
+    `, walletID, models.HoldActive, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	// Parents come before their children, so each node can be hung under one already read.
+	var root *requests.WalletTreeResponse
+	nodes := make(map[string]*requests.WalletTreeResponse)
+	var order []*requests.WalletTreeResponse
+	for rows.Next() {
+		node := &requests.WalletTreeResponse{Children: make([]*requests.WalletTreeResponse, 0)}
+		var held models.Amount
+		node.CreatedAt = new(time.Time)
+		err = rows.Scan(&node.WalletID, &node.Balance, &node.Currency, &node.OverdraftLimit, &node.Status, &node.Tier,
+			&node.InterestRate, &node.ParentID, &node.OwnerID, node.CreatedAt, &held)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		node.Available = node.Balance - held
+		nodes[canonicalID(node.WalletID)] = node
+		order = append(order, node)
+		if root == nil {
+			root = node
+			continue
+		}
+		parent := nodes[canonicalID(*node.ParentID)]
+		parent.Children = append(parent.Children, node)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("%s: wallet with id %s not found: %w", op, walletID, sql.ErrNoRows)
+	}
+
+	// Roll the balances up from the deepest wallets.
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		node.TotalBalance, node.TotalAvailable = node.Balance, node.Available
+		for _, child := range node.Children {
+			if node.TotalBalance, err = node.TotalBalance.Add(child.TotalBalance); err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			if node.TotalAvailable, err = node.TotalAvailable.Add(child.TotalAvailable); err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+	return root, nil
+}
+
+// Sweep moves money between a wallet and its parent or one of its children in a single transaction,
+// by default the whole available balance of the source. Sweeps stay inside one tree, so unlike transfers
+// they do not count towards withdrawal limits. Both legs share a transfer id, as with transfers.
+func (s *Storage) Sweep(ctx context.Context, req requests.SweepRequest) (*requests.TransferResponse, error) {
+	op := "database.Sweep"
+
+	var resp *requests.TransferResponse
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		wallets, err := lockWallets(ctx, tx, req.FromWalletID, req.ToWalletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallets error: %w", op, err)
+		}
+		from, ok := wallets[req.FromWalletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, req.FromWalletID, sql.ErrNoRows)
+		}
+		to, ok := wallets[req.ToWalletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, req.ToWalletID, sql.ErrNoRows)
+		}
+		if !isParentOf(from, to) && !isParentOf(to, from) {
+			return requests.WalletsNotRelatedError{}
+		}
+		if err = checkWalletStatus(from, true); err != nil {
+			return err
+		}
+		if err = checkWalletStatus(to, false); err != nil {
+			return err
+		}
+
+		amount := req.Amount
+		if amount == 0 {
+			held, err := heldAmount(ctx, tx, from.WalletID)
+			if err != nil {
+				return fmt.Errorf("%s: sum holds error: %w", op, err)
+			}
+			amount = from.Balance - held
+			if amount <= 0 {
+				return requests.InsufficientFundsError{Headroom: 0}
+			}
+		}
+		headroom, err := spendable(ctx, tx, from)
+		if err != nil {
+			return fmt.Errorf("%s: sum holds error: %w", op, err)
+		}
+		if headroom < amount {
+			return requests.InsufficientFundsError{Headroom: headroom}
+		}
+		transferID, err := moveFunds(ctx, tx, from, to, amount, "SWEEP")
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		resp = &requests.TransferResponse{
+			TransferID:   transferID,
+			FromWalletID: req.FromWalletID,
+			ToWalletID:   req.ToWalletID,
+			Amount:       amount,
+			FromBalance:  from.Balance,
+			ToBalance:    to.Balance,
+			Currency:     from.Currency,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// isParentOf reports whether child is a direct sub-wallet of parent.
+func isParentOf(parent, child *models.Wallets) bool {
+	return child.ParentID != nil && canonicalID(*child.ParentID) == canonicalID(parent.WalletID)
+}
+
+// lockSubtree locks wallet walletID and every wallet under it, in the same order as lockWallets.
+func lockSubtree(ctx context.Context, tx *sql.Tx, walletID string) error {
+	rows, err := tx.QueryContext(ctx, subtreeSQL+`
+    SELECT wallets.wallet_id FROM subtree
+    JOIN wallets ON wallets.wallet_id = subtree.wallet_id
+    ORDER BY wallets.wallet_id
+    FOR UPDATE OF wallets
+    `, walletID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// nonEmptySubWallets counts the wallets under walletID, at any depth, that hold or reserve money.
+// The caller must hold the subtree locks, or a sub-wallet could take money right after being counted.
+func nonEmptySubWallets(ctx context.Context, q queryer, walletID string) (int, error) {
+	var count int
+	err := q.QueryRowContext(ctx, subtreeSQL+`
+    SELECT COUNT(*) FROM subtree
+    JOIN wallets ON wallets.wallet_id = subtree.wallet_id
+    WHERE subtree.depth > 0 AND (wallets.balance <> 0 OR EXISTS (
+        SELECT 1 FROM holds WHERE holds.wallet_id = wallets.wallet_id AND holds.status = $2 AND holds.expires_at > $3
+    ))
+    `, walletID, models.HoldActive, time.Now().UTC()).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubWallets(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	parent, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{Currency: "EUR"})
+	require.NoError(t, err)
+	child, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{ParentID: parent.WalletID})
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", child.Currency)
+	grandchild, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{ParentID: child.WalletID})
+	require.NoError(t, err)
+	_, err = storage.CreateWallet(ctx, requests.CreateWalletRequest{ParentID: parent.WalletID, Currency: "USD"})
+	assert.ErrorAs(t, err, &requests.CurrencyMismatchError{})
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: parent.WalletID, OperationType: "DEPOSIT", Amount: 1000})))
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: grandchild.WalletID, OperationType: "DEPOSIT", Amount: 300})))
+
+	tree, err := storage.GetWalletTree(ctx, parent.WalletID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(1300), tree.TotalBalance)
+	require.Len(t, tree.Children, 1)
+	assert.Equal(t, models.Amount(300), tree.Children[0].TotalBalance)
+	require.Len(t, tree.Children[0].Children, 1)
+	assert.Equal(t, grandchild.WalletID, tree.Children[0].Children[0].WalletID)
+
+	// Sweeps only go one level up or down.
+	_, err = storage.Sweep(ctx, requests.SweepRequest{FromWalletID: grandchild.WalletID, ToWalletID: parent.WalletID})
+	assert.ErrorAs(t, err, &requests.WalletsNotRelatedError{})
+
+	_, err = storage.SetWalletStatus(ctx, child.WalletID, models.WalletClosed)
+	assert.ErrorAs(t, err, &requests.SubWalletsNotEmptyError{})
+
+	sweep, err := storage.Sweep(ctx, requests.SweepRequest{FromWalletID: grandchild.WalletID, ToWalletID: child.WalletID})
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(300), sweep.Amount)
+	_, err = storage.Sweep(ctx, requests.SweepRequest{FromWalletID: child.WalletID, ToWalletID: parent.WalletID, Amount: 300})
+	require.NoError(t, err)
+
+	_, err = storage.SetWalletStatus(ctx, child.WalletID, models.WalletClosed)
+	require.NoError(t, err)
+	_, err = storage.CreateWallet(ctx, requests.CreateWalletRequest{ParentID: child.WalletID})
+	assert.ErrorAs(t, err, &requests.WalletNotActiveError{})
+
+	tree, err = storage.GetWalletTree(ctx, parent.WalletID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Amount(1300), tree.Balance)
+	assert.Equal(t, models.Amount(1300), tree.TotalBalance)
+	for _, walletID := range []string{parent.WalletID, child.WalletID, grandchild.WalletID} {
+		balance, operationsSum, _ := walletState(t, storage, walletID)
+		assert.Equal(t, operationsSum, balance)
+	}
+}
+
+func TestCloseWaitsForSubWallets(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	parent, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	child, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{ParentID: parent.WalletID})
+	require.NoError(t, err)
+
+	// A deposit on the child is in flight while the parent is being closed.
+	tx, err := storage.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	wallets, err := lockWallets(ctx, tx, child.WalletID)
+	require.NoError(t, err)
+
+	closed := make(chan error, 1)
+	go func() {
+		_, err := storage.SetWalletStatus(ctx, parent.WalletID, models.WalletClosed)
+		closed <- err
+	}()
+	select {
+	case err = <-closed:
+		t.Fatalf("close did not wait for the sub-wallet lock: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, applyOperation(ctx, tx, wallets[child.WalletID], &models.Operation{Type: "DEPOSIT", Amount: 10}, externalCashAccount))
+	require.NoError(t, tx.Commit())
+	assert.ErrorAs(t, <-closed, &requests.SubWalletsNotEmptyError{})
+}
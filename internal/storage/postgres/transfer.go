@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+)
+
+// Transfer moves req.Amount from one wallet to another in a single transaction.
+// Both legs are recorded in operations as a WITHDRAW and a DEPOSIT sharing the same transfer id.
+func (s *Storage) Transfer(ctx context.Context, req requests.TransferRequest) (*requests.TransferResponse, error) {
+	op := "database.Transfer"
+
+	var resp *requests.TransferResponse
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		wallets, err := lockWallets(ctx, tx, req.FromWalletID, req.ToWalletID)
+		if err != nil {
+			return fmt.Errorf("%s: lock wallets error: %w", op, err)
+		}
+		from, ok := wallets[req.FromWalletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, req.FromWalletID, sql.ErrNoRows)
+		}
+		to, ok := wallets[req.ToWalletID]
+		if !ok {
+			return fmt.Errorf("%s: wallet with id %s not found: %w", op, req.ToWalletID, sql.ErrNoRows)
+		}
+
+		if from.Currency != to.Currency {
+			return requests.CurrencyMismatchError{WalletCurrency: to.Currency, Currency: from.Currency}
+		}
+		if err = checkWalletStatus(from, true); err != nil {
+			return err
+		}
+		if err = checkWalletStatus(to, false); err != nil {
+			return err
+		}
+
+		if err = s.checkWithdrawalLimits(ctx, tx, req.FromWalletID, req.Amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		headroom, err := spendable(ctx, tx, from)
+		if err != nil {
+			return fmt.Errorf("%s: sum holds error: %w", op, err)
+		}
+		if headroom < req.Amount {
+			return requests.InsufficientFundsError{Headroom: headroom}
+		}
+		transferID, err := moveFunds(ctx, tx, from, to, req.Amount, "TRANSFER")
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		resp = &requests.TransferResponse{
+			TransferID:   transferID,
+			FromWalletID: req.FromWalletID,
+			ToWalletID:   req.ToWalletID,
+			Amount:       req.Amount,
+			FromBalance:  from.Balance,
+			ToBalance:    to.Balance,
+			Currency:     from.Currency,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// moveFunds books amount from one locked wallet to another of the same currency as a WITHDRAW and a DEPOSIT
+// sharing a new transfer id, posted to the ledger as one entry of the given kind, and returns the transfer id.
+// Status, limit and funds checks are the caller's job.
+func moveFunds(ctx context.Context, tx *sql.Tx, from, to *models.Wallets, amount models.Amount, kind string) (string, error) {
+	var err error
+	if to.Balance, err = to.Balance.Add(amount); err != nil {
+		return "", err
+	}
+	from.Balance -= amount
+
+	for _, wallet := range []*models.Wallets{from, to} {
+		_, err = tx.ExecContext(ctx, "UPDATE wallets SET balance = $1 WHERE wallet_id = $2", wallet.Balance, wallet.WalletID)
+		if err != nil {
+			return "", fmt.Errorf("update wallet error: %w", err)
+		}
+	}
+
+	transferID := genUUID()
+	now, err := stampTime(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+	legs := []models.Operation{
+		{
+			ID:         genUUID(),
+			WalletID:   from.WalletID,
+			Type:       "WITHDRAW",
+			Amount:     amount,
+			Timestamp:  now,
+			TransferID: &transferID,
+			Currency:   from.Currency,
+		},
+		{
+			ID:         genUUID(),
+			WalletID:   to.WalletID,
+			Type:       "DEPOSIT",
+			Amount:     amount,
+			Timestamp:  now,
+			TransferID: &transferID,
+			Currency:   to.Currency,
+		},
+	}
+	for _, leg := range legs {
+		if err = insertOperation(ctx, tx, leg); err != nil {
+			return "", fmt.Errorf("insert operation error: %w", err)
+		}
+	}
+	err = postJournalEntry(ctx, tx, kind,
+		walletPosting(from.WalletID, -amount, from.Currency, legs[0].ID),
+		walletPosting(to.WalletID, amount, to.Currency, legs[1].ID),
+	)
+	if err != nil {
+		return "", fmt.Errorf("post journal entry error: %w", err)
+	}
+	return transferID, nil
+}
@@ -0,0 +1,579 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/foreground-eclipse/wallet/internal/webhooks"
+	"github.com/lib/pq"
+)
+
+const webhookColumns = `id, url, event_types, wallet_id, owner_id, secret, status, created_at, updated_at`
+
+// webhookDeliveryColumns are qualified, as deliveries are mostly read joined to their subscription.
+const webhookDeliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.wallet_id, d.body, d.status, d.attempts,
+    d.next_attempt_at, d.last_status_code, d.last_error, d.delivered_at, d.created_at, d.updated_at`
+
+const webhookAttemptColumns = `id, delivery_id, status_code, error, response_body, duration_ms, created_at`
+
+// CreateWebhook subscribes a URL to events. A secret is generated when none is given.
+func (s *Storage) CreateWebhook(ctx context.Context, req requests.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	op := "database.CreateWebhook"
+
+	if req.WalletID != nil {
+		if _, err := s.GetWallet(ctx, *req.WalletID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if req.OwnerID != nil {
+		if err := checkOwnerExists(ctx, s.db, *req.OwnerID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = webhooks.NewSecret(); err != nil {
+			return nil, fmt.Errorf("%s: generate secret error: %w", op, err)
+		}
+	}
+
+	now := time.Now().UTC()
+	subscription := &models.WebhookSubscription{
+		ID:         genUUID(),
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		WalletID:   req.WalletID,
+		OwnerID:    req.OwnerID,
+		Secret:     secret,
+		Status:     models.WebhookActive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	_, err := s.db.ExecContext(ctx, `
+    INSERT INTO webhook_subscriptions (`+webhookColumns+`)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `, subscription.ID, subscription.URL, pq.Array(subscription.EventTypes), subscription.WalletID, subscription.OwnerID,
+		subscription.Secret, subscription.Status, subscription.CreatedAt, subscription.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return subscription, nil
+}
+
+func (s *Storage) GetWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error) {
+	op := "database.GetWebhook"
+
+	subscription, err := scanWebhook(s.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id = $1`, webhookID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return subscription, nil
+}
+
+// ListWebhooks returns every webhook subscription, disabled ones included, oldest first.
+func (s *Storage) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	op := "database.ListWebhooks"
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]models.WebhookSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return subscriptions, nil
+}
+
+// UpdateWebhook changes the URL, event types or status of a subscription. Deliveries queued while it was
+// paused go out once it is active again.
+func (s *Storage) UpdateWebhook(ctx context.Context, webhookID string, req requests.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	op := "database.UpdateWebhook"
+
+	var subscription *models.WebhookSubscription
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var err error
+		subscription, err = lockWebhook(ctx, tx, webhookID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if req.URL != nil {
+			subscription.URL = *req.URL
+		}
+		if req.EventTypes != nil {
+			subscription.EventTypes = *req.EventTypes
+		}
+		if req.Status != nil {
+			subscription.Status = *req.Status
+		}
+		subscription.UpdatedAt = time.Now().UTC()
+		return updateWebhook(ctx, tx, subscription)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// DeleteWebhook disables a subscription for good. Its pending deliveries are marked DEAD; the delivery log is kept.
+func (s *Storage) DeleteWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error) {
+	op := "database.DeleteWebhook"
+
+	var subscription *models.WebhookSubscription
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var err error
+		subscription, err = lockWebhook(ctx, tx, webhookID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		subscription.Status = models.WebhookDisabled
+		subscription.UpdatedAt = time.Now().UTC()
+		if err = updateWebhook(ctx, tx, subscription); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+    UPDATE webhook_deliveries SET status = $1, last_error = $2, updated_at = $3 WHERE subscription_id = $4 AND status = $5
+    `, models.DeliveryDead, "subscription disabled", subscription.UpdatedAt, subscription.ID, models.DeliveryPending)
+		if err != nil {
+			return fmt.Errorf("%s: kill deliveries error: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// QueueWebhookDeliveries queues a delivery of event for every subscription that is not disabled, takes its type
+// and covers its wallet, and returns how many were queued. A subscription gets each event at most once,
+// however often the event is queued.
+func (s *Storage) QueueWebhookDeliveries(ctx context.Context, event requests.EventEnvelope) (int, error) {
+	op := "database.QueueWebhookDeliveries"
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("%s: encode event error: %w", op, err)
+	}
+	queued := 0
+	err = s.withTx(ctx, op, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+    SELECT id FROM webhook_subscriptions
+    WHERE status IN ($1, $2) AND $3 = ANY(event_types)
+        AND (wallet_id IS NULL OR wallet_id = $4)
+        AND (owner_id IS NULL OR owner_id = (SELECT owner_id FROM wallets WHERE wallet_id = $4))
+    `, models.WebhookActive, models.WebhookPaused, event.Type, event.WalletID)
+		if err != nil {
+			return fmt.Errorf("%s: find subscriptions error: %w", op, err)
+		}
+		var subscriptionIDs []string
+		for rows.Next() {
+			var id string
+			if err = rows.Scan(&id); err != nil {
+				rows.Close()
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			subscriptionIDs = append(subscriptionIDs, id)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		now := time.Now().UTC()
+		for _, subscriptionID := range subscriptionIDs {
+			result, err := tx.ExecContext(ctx, `
+    INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, wallet_id, body, status, next_attempt_at,
+        created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8, $8)
+    ON CONFLICT (subscription_id, event_id) DO NOTHING
+    `, genUUID(), subscriptionID, event.ID, event.Type, event.WalletID, string(body), models.DeliveryPending, now)
+			if err != nil {
+				return fmt.Errorf("%s: queue delivery error: %w", op, err)
+			}
+			inserted, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			queued += int(inserted)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return queued, nil
+}
+
+// ListWebhookDeliveries returns the latest deliveries of a subscription, newest first.
+func (s *Storage) ListWebhookDeliveries(ctx context.Context, webhookID string, filter requests.WebhookDeliveriesFilter) ([]models.WebhookDelivery, error) {
+	op := "database.ListWebhookDeliveries"
+
+	if _, err := s.GetWebhook(ctx, webhookID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	conditions := []string{"d.subscription_id = $1"}
+	args := []interface{}{webhookID}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("d.status = $%d", len(args)))
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = requests.DefaultDeliveriesLimit
+	}
+	args = append(args, limit)
+	rows, err := s.db.QueryContext(ctx, `
+    SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries d
+    WHERE `+strings.Join(conditions, " AND ")+`
+    ORDER BY d.created_at DESC, d.id DESC
+    LIMIT $`+fmt.Sprint(len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deliveries, nil
+}
+
+// GetWebhookDelivery returns a delivery of a subscription.
+func (s *Storage) GetWebhookDelivery(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	op := "database.GetWebhookDelivery"
+
+	delivery, err := scanWebhookDelivery(s.db.QueryRowContext(ctx, `
+    SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries d WHERE d.id = $1 AND d.subscription_id = $2
+    `, deliveryID, webhookID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: delivery with id %s not found: %w", op, deliveryID, err)
+	}
+	return delivery, nil
+}
+
+// ListWebhookAttempts returns every attempt at a delivery, oldest first.
+func (s *Storage) ListWebhookAttempts(ctx context.Context, deliveryID string) ([]models.WebhookAttempt, error) {
+	op := "database.ListWebhookAttempts"
+
+	rows, err := s.db.QueryContext(ctx, `
+    SELECT `+webhookAttemptColumns+` FROM webhook_delivery_attempts WHERE delivery_id = $1 ORDER BY created_at, id
+    `, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	attempts := make([]models.WebhookAttempt, 0)
+	for rows.Next() {
+		var attempt models.WebhookAttempt
+		err = rows.Scan(&attempt.ID, &attempt.DeliveryID, &attempt.StatusCode, &attempt.Error, &attempt.ResponseBody,
+			&attempt.DurationMs, &attempt.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		attempts = append(attempts, attempt)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return attempts, nil
+}
+
+// RedeliverWebhook queues a delivery to be sent again right away with a fresh set of attempts, whether it is DEAD,
+// DELIVERED or PENDING and already due. A pending one that is being sent or waiting for a retry is left alone.
+func (s *Storage) RedeliverWebhook(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	op := "database.RedeliverWebhook"
+
+	var delivery *models.WebhookDelivery
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		var status string
+		err := tx.QueryRowContext(ctx, `SELECT status FROM webhook_subscriptions WHERE id = $1 FOR SHARE`, webhookID).Scan(&status)
+		if err != nil {
+			return fmt.Errorf("%s: webhook with id %s not found: %w", op, webhookID, err)
+		}
+		if status == models.WebhookDisabled {
+			return requests.WebhookDisabledError{}
+		}
+		delivery, err = scanWebhookDelivery(tx.QueryRowContext(ctx, `
+    SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries d WHERE d.id = $1 AND d.subscription_id = $2 FOR UPDATE
+    `, deliveryID, webhookID))
+		if err != nil {
+			return fmt.Errorf("%s: delivery with id %s not found: %w", op, deliveryID, err)
+		}
+		// A pending delivery due later may be in flight under a lease; resetting it would send it twice at once.
+		now := time.Now().UTC()
+		if delivery.Status == models.DeliveryPending && delivery.NextAttemptAt.After(now) {
+			return requests.DeliveryPendingError{NextAttemptAt: delivery.NextAttemptAt}
+		}
+		delivery.Status = models.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = now
+		delivery.DeliveredAt = nil
+		delivery.UpdatedAt = now
+		return updateWebhookDelivery(ctx, tx, delivery)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// DeliverWebhooks sends every delivery due at now to an active subscription and returns how many were attempted.
+// Up to Workers deliveries are in flight at once. Each one is claimed with SKIP LOCKED in a short transaction that
+// leases it by pushing next_attempt_at out, sent with no transaction open and recorded in a second transaction,
+// so a slow receiver holds up neither the database nor the other deliveries, and several replicas can run this
+// at once without sending anything twice at the same time. A failed delivery is retried with exponential backoff
+// until it runs out of attempts and is marked DEAD; one whose sender died is picked up again when its lease ends.
+func (s *Storage) DeliverWebhooks(ctx context.Context, sender webhooks.Sender, now time.Time) (int, error) {
+	op := "database.DeliverWebhooks"
+
+	workers := s.webhooks.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		attempted int
+		firstErr  error
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				found, err := s.deliverNextWebhook(ctx, sender, now.UTC())
+				mu.Lock()
+				if found {
+					attempted++
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				stop := !found || firstErr != nil
+				mu.Unlock()
+				if stop {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return attempted, fmt.Errorf("%s: %w", op, firstErr)
+	}
+	return attempted, nil
+}
+
+func (s *Storage) deliverNextWebhook(ctx context.Context, sender webhooks.Sender, now time.Time) (bool, error) {
+	delivery, url, secret, err := s.claimWebhookDelivery(ctx, now)
+	if err != nil || delivery == nil {
+		return false, err
+	}
+	result := sender.Send(ctx, webhooks.Request{
+		URL:        url,
+		Secret:     secret,
+		DeliveryID: delivery.ID,
+		EventID:    delivery.EventID,
+		Body:       []byte(delivery.Body),
+	})
+	return true, s.recordWebhookAttempt(ctx, delivery, result)
+}
+
+// claimWebhookDelivery leases the next delivery due at dueBy, along with its subscription's URL and secret,
+// and returns a nil delivery when none is due. The lease is the delivery's new next_attempt_at. It runs from
+// the time of the claim rather than dueBy, which is when the run started and may be long past.
+func (s *Storage) claimWebhookDelivery(ctx context.Context, dueBy time.Time) (*models.WebhookDelivery, string, string, error) {
+	op := "database.claimWebhookDelivery"
+
+	var delivery *models.WebhookDelivery
+	var url, secret string
+	err := s.withTx(ctx, op, func(tx *sql.Tx) error {
+		// A lease still running is never due, whatever dueBy says.
+		now := time.Now().UTC()
+		if dueBy.After(now) {
+			dueBy = now
+		}
+		// Retries are scheduled after dueBy, so a failing receiver is not tried again within one run.
+		row := tx.QueryRowContext(ctx, `
+    SELECT `+webhookDeliveryColumns+`, s.url, s.secret
+    FROM webhook_deliveries d
+    JOIN webhook_subscriptions s ON s.id = d.subscription_id
+    WHERE d.status = $1 AND d.next_attempt_at <= $2 AND s.status = $3
+    ORDER BY d.next_attempt_at
+    LIMIT 1
+    FOR UPDATE OF d SKIP LOCKED
+    `, models.DeliveryPending, dueBy, models.WebhookActive)
+		var err error
+		delivery, err = scanWebhookDelivery(row, &url, &secret)
+		if err != nil {
+			delivery = nil
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		// Postgres keeps microseconds; the lease is compared as stored when the attempt is recorded.
+		delivery.NextAttemptAt = now.Add(s.webhooks.Lease).Truncate(time.Microsecond)
+		_, err = tx.ExecContext(ctx, "UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id = $2", delivery.NextAttemptAt, delivery.ID)
+		if err != nil {
+			return fmt.Errorf("%s: lease delivery error: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", "", err
+	}
+	return delivery, url, secret, nil
+}
+
+// recordWebhookAttempt logs an attempt at a leased delivery and applies its outcome to the delivery, unless the
+// delivery changed in the meantime: it was redelivered, its subscription deleted or its lease ran out and
+// another sender took it over. The attempt is logged either way.
+func (s *Storage) recordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery, result webhooks.Result) error {
+	op := "database.recordWebhookAttempt"
+
+	now := time.Now().UTC()
+	var statusCode *int
+	if result.StatusCode != 0 {
+		statusCode = &result.StatusCode
+	}
+	var message, responseBody *string
+	if result.Error != "" {
+		truncated := truncateError(cleanText(result.Error))
+		message = &truncated
+	}
+	if result.ResponseBody != "" {
+		cleaned := cleanText(result.ResponseBody)
+		responseBody = &cleaned
+	}
+	return s.withTx(ctx, op, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+    INSERT INTO webhook_delivery_attempts (`+webhookAttemptColumns+`)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `, genUUID(), delivery.ID, statusCode, message, responseBody, result.Duration.Milliseconds(), now)
+		if err != nil {
+			return fmt.Errorf("%s: log attempt error: %w", op, err)
+		}
+
+		var leased bool
+		err = tx.QueryRowContext(ctx, `
+    SELECT status = $2 AND attempts = $3 AND next_attempt_at = $4 FROM webhook_deliveries WHERE id = $1 FOR UPDATE
+    `, delivery.ID, models.DeliveryPending, delivery.Attempts, delivery.NextAttemptAt).Scan(&leased)
+		if err != nil {
+			return fmt.Errorf("%s: lock delivery error: %w", op, err)
+		}
+		if !leased {
+			return nil
+		}
+
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = message
+		delivery.UpdatedAt = now
+		switch {
+		case result.OK():
+			delivery.Status = models.DeliveryDelivered
+			delivery.DeliveredAt = &now
+		case delivery.Attempts >= s.webhooks.MaxAttempts:
+			delivery.Status = models.DeliveryDead
+		default:
+			delivery.NextAttemptAt = now.Add(s.webhookRetryDelay(delivery.Attempts))
+		}
+		return updateWebhookDelivery(ctx, tx, delivery)
+	})
+}
+
+// webhookRetryDelay is how long a delivery waits after its attempt-th failure.
+func (s *Storage) webhookRetryDelay(attempt int) time.Duration {
+	return backoff(s.webhooks.RetryDelay, s.webhooks.MaxRetryDelay, attempt)
+}
+
+// cleanText makes what a receiver sent back storable as TEXT, which takes neither NUL nor invalid UTF-8.
+func cleanText(text string) string {
+	return strings.ToValidUTF8(strings.ReplaceAll(text, "\x00", ""), "")
+}
+
+// lockWebhook locks a subscription that can still be changed.
+func lockWebhook(ctx context.Context, tx *sql.Tx, webhookID string) (*models.WebhookSubscription, error) {
+	subscription, err := scanWebhook(tx.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id = $1 FOR UPDATE`, webhookID))
+	if err != nil {
+		return nil, fmt.Errorf("webhook with id %s not found: %w", webhookID, err)
+	}
+	if subscription.Status == models.WebhookDisabled {
+		return nil, requests.WebhookDisabledError{}
+	}
+	return subscription, nil
+}
+
+func updateWebhook(ctx context.Context, tx *sql.Tx, subscription *models.WebhookSubscription) error {
+	_, err := tx.ExecContext(ctx, `
+    UPDATE webhook_subscriptions SET url = $1, event_types = $2, status = $3, updated_at = $4 WHERE id = $5
+    `, subscription.URL, pq.Array(subscription.EventTypes), subscription.Status, subscription.UpdatedAt, subscription.ID)
+	if err != nil {
+		return fmt.Errorf("update webhook error: %w", err)
+	}
+	return nil
+}
+
+func updateWebhookDelivery(ctx context.Context, tx *sql.Tx, delivery *models.WebhookDelivery) error {
+	_, err := tx.ExecContext(ctx, `
+    UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5,
+        delivered_at = $6, updated_at = $7
+    WHERE id = $8
+    `, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError,
+		delivery.DeliveredAt, delivery.UpdatedAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("update delivery error: %w", err)
+	}
+	return nil
+}
+
+func scanWebhook(row scanner) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := row.Scan(&subscription.ID, &subscription.URL, pq.Array(&subscription.EventTypes), &subscription.WalletID,
+		&subscription.OwnerID, &subscription.Secret, &subscription.Status, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// scanWebhookDelivery scans the delivery columns followed by any extra columns of the row.
+func scanWebhookDelivery(row scanner, extra ...interface{}) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	dest := []interface{}{&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &delivery.WalletID,
+		&delivery.Body, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode,
+		&delivery.LastError, &delivery.DeliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/foreground-eclipse/wallet/config"
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/foreground-eclipse/wallet/internal/models"
+	"github.com/foreground-eclipse/wallet/internal/webhooks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	s := &Storage{webhooks: config.WebhookConfig{RetryDelay: 30 * time.Second, MaxRetryDelay: time.Hour}}
+
+	assert.Equal(t, 30*time.Second, s.webhookRetryDelay(1))
+	assert.Equal(t, time.Minute, s.webhookRetryDelay(2))
+	assert.Equal(t, 32*time.Minute, s.webhookRetryDelay(7))
+	assert.Equal(t, time.Hour, s.webhookRetryDelay(8))
+}
+
+// testReceiver is a webhook endpoint that checks signatures and fails while down is set.
+type testReceiver struct {
+	mu       sync.Mutex
+	down     bool
+	received []requests.EventEnvelope
+}
+
+func (r *testReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	if err := webhooks.Verify(testWebhookSecret, req.Header, body, time.Minute, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.down {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+		return
+	}
+	var event requests.EventEnvelope
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.received = append(r.received, event)
+}
+
+const testWebhookSecret = "webhook-test-secret"
+
+func TestWebhookDeliveries(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	receiver := &testReceiver{down: true}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	client := webhooks.NewClient(time.Second)
+
+	wallet, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	other, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	subscription, err := storage.CreateWebhook(ctx, requests.CreateWebhookRequest{
+		URL:        server.URL,
+		EventTypes: []string{requests.EventOperationApplied},
+		WalletID:   &wallet.WalletID,
+		Secret:     testWebhookSecret,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.DeleteWebhook(context.Background(), subscription.ID) })
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: wallet.WalletID, OperationType: "DEPOSIT", Amount: 500})))
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: other.WalletID, OperationType: "DEPOSIT", Amount: 500})))
+
+	// Only the deposit into the subscribed wallet is queued, once however often it is published.
+	var deposit requests.EventEnvelope
+	publisher := webhooks.NewPublisher(storage)
+	for {
+		_, more, err := storage.DispatchEvents(ctx, publisher)
+		require.NoError(t, err)
+		if !more {
+			break
+		}
+	}
+	deliveries, err := storage.ListWebhookDeliveries(ctx, subscription.ID, requests.WebhookDeliveriesFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.NoError(t, json.Unmarshal([]byte(deliveries[0].Body), &deposit))
+	assert.Equal(t, requests.EventOperationApplied, deposit.Type)
+	queued, err := storage.QueueWebhookDeliveries(ctx, deposit)
+	require.NoError(t, err)
+	assert.Zero(t, queued)
+	deliveryID := deliveries[0].ID
+
+	// The receiver is down: the first failure is retried later, the second is the last.
+	_, err = storage.DeliverWebhooks(ctx, client, time.Now())
+	require.NoError(t, err)
+	delivery, err := storage.GetWebhookDelivery(ctx, subscription.ID, deliveryID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	require.NotNil(t, delivery.LastStatusCode)
+	assert.Equal(t, http.StatusServiceUnavailable, *delivery.LastStatusCode)
+	assert.True(t, delivery.NextAttemptAt.After(time.Now().UTC().Add(30*time.Second)))
+
+	_, err = storage.db.ExecContext(ctx, "UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id = $2", time.Now().UTC(), deliveryID)
+	require.NoError(t, err)
+	_, err = storage.DeliverWebhooks(ctx, client, time.Now())
+	require.NoError(t, err)
+	dead, err := storage.ListWebhookDeliveries(ctx, subscription.ID, requests.WebhookDeliveriesFilter{Status: models.DeliveryDead})
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 2, dead[0].Attempts)
+
+	// Dead deliveries are only sent again by hand.
+	receiver.down = false
+	_, err = storage.DeliverWebhooks(ctx, client, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, receiver.received)
+
+	delivery, err = storage.RedeliverWebhook(ctx, subscription.ID, deliveryID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryPending, delivery.Status)
+	assert.Zero(t, delivery.Attempts)
+	_, err = storage.DeliverWebhooks(ctx, client, time.Now())
+	require.NoError(t, err)
+	require.Len(t, receiver.received, 1)
+	assert.Equal(t, deposit.ID, receiver.received[0].ID)
+
+	delivery, err = storage.GetWebhookDelivery(ctx, subscription.ID, deliveryID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryDelivered, delivery.Status)
+	assert.NotNil(t, delivery.DeliveredAt)
+	assert.Nil(t, delivery.LastError)
+	attempts, err := storage.ListWebhookAttempts(ctx, deliveryID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 3)
+	assert.Equal(t, "down for maintenance\n", *attempts[0].ResponseBody)
+	assert.Equal(t, http.StatusOK, *attempts[2].StatusCode)
+
+	// Deleted subscriptions take no more deliveries, automatic or manual.
+	disabled, err := storage.DeleteWebhook(ctx, subscription.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDisabled, disabled.Status)
+	_, err = storage.RedeliverWebhook(ctx, subscription.ID, deliveryID)
+	assert.ErrorAs(t, err, &requests.WebhookDisabledError{})
+	_, err = storage.UpdateWebhook(ctx, subscription.ID, requests.UpdateWebhookRequest{URL: &server.URL})
+	assert.ErrorAs(t, err, &requests.WebhookDisabledError{})
+}
+
+func TestSlowWebhookReceiverHoldsUpNothing(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slowServer.Close()
+	healthy := &testReceiver{}
+	healthyServer := httptest.NewServer(healthy)
+	defer healthyServer.Close()
+	client := webhooks.NewClient(5 * time.Second)
+
+	wallet, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	subscribe := func(url string) *models.WebhookSubscription {
+		subscription, err := storage.CreateWebhook(ctx, requests.CreateWebhookRequest{
+			URL:        url,
+			EventTypes: []string{requests.EventOperationApplied},
+			WalletID:   &wallet.WalletID,
+			Secret:     testWebhookSecret,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { storage.DeleteWebhook(context.Background(), subscription.ID) })
+		return subscription
+	}
+	slow, fast := subscribe(slowServer.URL), subscribe(healthyServer.URL)
+
+	require.NoError(t, processed(storage.ProcessOperation(ctx, requests.WalletOperationRequest{WalletID: wallet.WalletID, OperationType: "DEPOSIT", Amount: 500})))
+	publisher := webhooks.NewPublisher(storage)
+	for {
+		_, more, err := storage.DispatchEvents(ctx, publisher)
+		require.NoError(t, err)
+		if !more {
+			break
+		}
+	}
+	slowDeliveries, err := storage.ListWebhookDeliveries(ctx, slow.ID, requests.WebhookDeliveriesFilter{})
+	require.NoError(t, err)
+	require.Len(t, slowDeliveries, 1)
+	slowID := slowDeliveries[0].ID
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := storage.DeliverWebhooks(ctx, client, time.Now())
+		done <- err
+	}()
+
+	// The healthy receiver gets its delivery while the slow one is still in flight.
+	require.Eventually(t, func() bool {
+		delivered, err := storage.ListWebhookDeliveries(ctx, fast.ID, requests.WebhookDeliveriesFilter{Status: models.DeliveryDelivered})
+		return err == nil && len(delivered) == 1
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// No lock is held during the send, so redelivering the slow delivery answers at once, refusing
+	// to send it a second time while it is in flight.
+	redeliverCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = storage.RedeliverWebhook(redeliverCtx, slow.ID, slowID)
+	assert.ErrorAs(t, err, &requests.DeliveryPendingError{})
+
+	close(release)
+	require.NoError(t, <-done)
+
+	delivery, err := storage.GetWebhookDelivery(ctx, slow.ID, slowID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryDelivered, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	attempts, err := storage.ListWebhookAttempts(ctx, slowID)
+	require.NoError(t, err)
+	assert.Len(t, attempts, 1)
+}
+
+func TestWebhookLeaseRunsFromClaim(t *testing.T) {
+	storage := newTestStorage(t)
+	ctx := context.Background()
+
+	wallet, err := storage.CreateWallet(ctx, requests.CreateWalletRequest{})
+	require.NoError(t, err)
+	subscription, err := storage.CreateWebhook(ctx, requests.CreateWebhookRequest{
+		URL:        "http://127.0.0.1:1",
+		EventTypes: []string{requests.EventOperationApplied},
+		WalletID:   &wallet.WalletID,
+		Secret:     testWebhookSecret,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.DeleteWebhook(context.Background(), subscription.ID) })
+	queued, err := storage.QueueWebhookDeliveries(ctx, requests.EventEnvelope{ID: uuid.New().String(), Type: requests.EventOperationApplied, WalletID: wallet.WalletID})
+	require.NoError(t, err)
+	require.Equal(t, 1, queued)
+
+	// A run that started a while ago still leases from the moment of the claim.
+	dueBy := time.Now()
+	time.Sleep(50 * time.Millisecond)
+	claimed := time.Now().UTC()
+	delivery, _, _, err := storage.claimWebhookDelivery(ctx, dueBy)
+	require.NoError(t, err)
+	require.NotNil(t, delivery)
+	assert.False(t, delivery.NextAttemptAt.Before(claimed.Add(storage.webhooks.Lease).Truncate(time.Microsecond)))
+
+	// A run whose due bound lies past the lease still cannot take the delivery over.
+	stolen, _, _, err := storage.claimWebhookDelivery(ctx, time.Now().Add(2*storage.webhooks.Lease))
+	require.NoError(t, err)
+	if stolen != nil {
+		assert.NotEqual(t, delivery.ID, stolen.ID)
+	}
+}
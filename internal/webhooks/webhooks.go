@@ -0,0 +1,160 @@
+// Package webhooks queues outbox events for the webhook subscriptions that want them and signs and POSTs
+// the resulting deliveries.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+)
+
+// Headers of every delivery. The signature is "sha256=" followed by the hex HMAC-SHA256, keyed with the
+// subscription secret, of the timestamp, a dot and the body, so a captured delivery cannot be replayed later
+// with a new timestamp.
+const (
+	SignatureHeader  = "Webhook-Signature"
+	TimestampHeader  = "Webhook-Timestamp" // Unix seconds the delivery was signed at
+	EventIDHeader    = "Webhook-Event-Id"  // The same on every delivery of an event, to deduplicate on
+	DeliveryIDHeader = "Webhook-Delivery-Id"
+)
+
+// maxResponseBody bounds how much of a receiver's response is kept in the delivery log.
+const maxResponseBody = 1024
+
+// Sign returns the signature header value of body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a delivery received at now, rejecting ones signed more than tolerance away
+// from it. Receivers written in Go can use it as is.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return errors.New("missing or invalid timestamp")
+	}
+	signedAt := time.Unix(timestamp, 0)
+	if now.Sub(signedAt) > tolerance || signedAt.Sub(now) > tolerance {
+		return errors.New("timestamp out of tolerance")
+	}
+	if !hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(Sign(secret, timestamp, body))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// NewSecret returns a random secret for a subscription created without one.
+func NewSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// Request is one attempt at a delivery.
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID string
+	EventID    string
+	Body       []byte
+}
+
+// Result is what came of an attempt. Only 2xx responses count as delivered.
+type Result struct {
+	StatusCode   int // Zero when no response came back
+	Error        string
+	ResponseBody string
+	Duration     time.Duration
+}
+
+func (r Result) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Sender POSTs deliveries to their receivers.
+type Sender interface {
+	Send(ctx context.Context, req Request) Result
+}
+
+// Client is the Sender the server uses. It does not follow redirects: a receiver that moved must be
+// updated on its subscription.
+type Client struct {
+	http *http.Client
+	now  func() time.Time
+}
+
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		http: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+func (c *Client) Send(ctx context.Context, req Request) Result {
+	start := time.Now()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+	timestamp := c.now().Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "wallet-webhooks/1")
+	httpReq.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(SignatureHeader, Sign(req.Secret, timestamp, req.Body))
+	httpReq.Header.Set(EventIDHeader, req.EventID)
+	httpReq.Header.Set(DeliveryIDHeader, req.DeliveryID)
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return Result{Error: err.Error(), Duration: time.Since(start)}
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result := Result{StatusCode: resp.StatusCode, ResponseBody: string(body), Duration: time.Since(start)}
+	if !result.OK() {
+		result.Error = fmt.Sprintf("receiver responded %s", resp.Status)
+	}
+	return result
+}
+
+// DeliveryQueuer records a delivery of an event for every subscription that wants it.
+// Queuing an event twice must not queue its deliveries twice.
+type DeliveryQueuer interface {
+	QueueWebhookDeliveries(ctx context.Context, event requests.EventEnvelope) (int, error)
+}
+
+// Publisher is the events.Publisher that turns outbox events into webhook deliveries.
+type Publisher struct {
+	queuer DeliveryQueuer
+}
+
+func NewPublisher(queuer DeliveryQueuer) *Publisher {
+	return &Publisher{queuer: queuer}
+}
+
+func (p *Publisher) Publish(ctx context.Context, event requests.EventEnvelope) error {
+	_, err := p.queuer.QueueWebhookDeliveries(ctx, event)
+	return err
+}
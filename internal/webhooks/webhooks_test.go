@@ -0,0 +1,123 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	requests "github.com/foreground-eclipse/wallet/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":"e1"}`)
+	now := time.Unix(1714554000, 0)
+	header := http.Header{}
+	header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	header.Set(SignatureHeader, Sign(testSecret, now.Unix(), body))
+
+	// HMAC-SHA256 of "1714554000.{"id":"e1"}", computed independently.
+	assert.Equal(t, "sha256=9ecf53b3bd1d8ea974a8a15eac8549e1a58811134509527f81731adb567f4ca8", header.Get(SignatureHeader))
+	assert.NoError(t, Verify(testSecret, header, body, 5*time.Minute, now.Add(time.Minute)))
+	assert.EqualError(t, Verify("another secret", header, body, 5*time.Minute, now), "signature mismatch")
+	assert.EqualError(t, Verify(testSecret, header, []byte(`{"id":"e2"}`), 5*time.Minute, now), "signature mismatch")
+	assert.EqualError(t, Verify(testSecret, header, body, 5*time.Minute, now.Add(10*time.Minute)), "timestamp out of tolerance")
+
+	// A signature cannot be moved onto a newer timestamp.
+	header.Set(TimestampHeader, strconv.FormatInt(now.Unix()+600, 10))
+	assert.EqualError(t, Verify(testSecret, header, body, 5*time.Minute, now.Add(10*time.Minute)), "signature mismatch")
+}
+
+func TestClientSend(t *testing.T) {
+	body := []byte(`{"id":"e1","type":"OperationApplied"}`)
+	var received http.Header
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		got, _ := io.ReadAll(r.Body)
+		if err := Verify(testSecret, r.Header, got, time.Minute, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer receiver.Close()
+
+	result := NewClient(time.Second).Send(context.Background(), Request{
+		URL:        receiver.URL,
+		Secret:     testSecret,
+		DeliveryID: "d1",
+		EventID:    "e1",
+		Body:       body,
+	})
+	require.True(t, result.OK(), result.Error)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, "ok", result.ResponseBody)
+	assert.Empty(t, result.Error)
+	assert.Equal(t, "e1", received.Get(EventIDHeader))
+	assert.Equal(t, "d1", received.Get(DeliveryIDHeader))
+	assert.Equal(t, "application/json", received.Get("Content-Type"))
+}
+
+func TestClientSendFailures(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/large":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(make([]byte, 10*maxResponseBody))
+		}
+	}))
+	defer receiver.Close()
+	client := NewClient(50 * time.Millisecond)
+	send := func(path string) Result {
+		return client.Send(context.Background(), Request{URL: receiver.URL + path, Secret: testSecret, Body: []byte(`{}`)})
+	}
+
+	result := send("/error")
+	assert.False(t, result.OK())
+	assert.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	assert.Equal(t, "receiver responded 500 Internal Server Error", result.Error)
+	assert.Equal(t, "boom\n", result.ResponseBody)
+
+	// Redirects are not followed.
+	result = send("/moved")
+	assert.False(t, result.OK())
+	assert.Equal(t, http.StatusFound, result.StatusCode)
+
+	result = send("/slow")
+	assert.False(t, result.OK())
+	assert.Zero(t, result.StatusCode)
+	assert.Contains(t, result.Error, "Client.Timeout exceeded")
+
+	result = send("/large")
+	assert.Len(t, result.ResponseBody, maxResponseBody)
+}
+
+type queuerFunc func(ctx context.Context, event requests.EventEnvelope) (int, error)
+
+func (f queuerFunc) QueueWebhookDeliveries(ctx context.Context, event requests.EventEnvelope) (int, error) {
+	return f(ctx, event)
+}
+
+func TestPublisher(t *testing.T) {
+	var queued []string
+	publisher := NewPublisher(queuerFunc(func(ctx context.Context, event requests.EventEnvelope) (int, error) {
+		queued = append(queued, event.ID)
+		return 1, nil
+	}))
+
+	require.NoError(t, publisher.Publish(context.Background(), requests.EventEnvelope{ID: "e1"}))
+	assert.Equal(t, []string{"e1"}, queued)
+}